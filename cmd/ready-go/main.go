@@ -1,13 +1,11 @@
 package main
 
 import (
-	"embed"
 	"fmt"
-	"log"
 	"os"
 
 	internalcli "github.com/muazwzxv/ready-go-cli/internal/cli"
-	"github.com/muazwzxv/ready-go-cli/internal/generator"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
 	"github.com/urfave/cli/v2"
 )
 
@@ -15,18 +13,15 @@ var (
 	version = "2.3.0"
 )
 
-//go:embed all:templates
-var embeddedTemplates embed.FS
-
 func main() {
-	// Set the embedded templates for the generator
-	generator.SetEmbeddedTemplates(embeddedTemplates)
-
 	app := &cli.App{
 		Name:     "ready-go",
 		Usage:    "Scaffold production-ready Go projects with clean architecture",
 		Version:  version,
+		Flags:    internalcli.GlobalFlags(),
 		Commands: internalcli.Commands(),
+		// Errors are printed once below, with their hint and exit code
+		ExitErrHandler: func(*cli.Context, error) {},
 		Authors: []*cli.Author{
 			{
 				Name:  "Ready-Go CLI",
@@ -37,6 +32,9 @@ func main() {
 
 	if err := app.Run(os.Args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		log.Fatal(err)
+		if hint := errs.Hint(err); hint != "" {
+			fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
+		}
+		os.Exit(errs.ExitCode(err))
 	}
 }
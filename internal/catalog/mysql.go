@@ -0,0 +1,258 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// mysqlReader reads information_schema for the database named in the DSN
+type mysqlReader struct{}
+
+func (mysqlReader) tables(ctx context.Context, db *sql.DB) ([]string, error) {
+	return queryStrings(ctx, db, `
+		SELECT TABLE_NAME FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'
+		ORDER BY TABLE_NAME`)
+}
+
+func (r mysqlReader) define(ctx context.Context, db *sql.DB, table string) (*Definition, error) {
+	columns, err := r.columns(ctx, db, table)
+	if err != nil || columns == nil {
+		return nil, err
+	}
+
+	constraints, err := r.indexes(ctx, db, table)
+	if err != nil {
+		return nil, err
+	}
+	foreignKeys, err := r.foreignKeys(ctx, db, table)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Definition{
+		Table:      table,
+		Statements: []string{createTable(table, columns, append(constraints, foreignKeys...))},
+	}, nil
+}
+
+var currentTimestamp = regexp.MustCompile(`(?i)^(CURRENT_TIMESTAMP|NOW|LOCALTIME|LOCALTIMESTAMP)(\(\d*\))?$`)
+
+// columns returns the table's columns, or nil if the table doesn't exist
+func (mysqlReader) columns(ctx context.Context, db *sql.DB, table string) ([]columnDef, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT, GENERATION_EXPRESSION
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+		ORDER BY ORDINAL_POSITION`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []columnDef
+	for rows.Next() {
+		var name, typ, nullable, extra, comment string
+		var def, generation sql.NullString
+		if err := rows.Scan(&name, &typ, &nullable, &def, &extra, &comment, &generation); err != nil {
+			return nil, err
+		}
+		extra = strings.ToUpper(extra)
+
+		c := columnDef{name: name, parts: []string{typ}}
+		if generation.String != "" {
+			kind := "VIRTUAL"
+			if strings.Contains(extra, "STORED") {
+				kind = "STORED"
+			}
+			c.parts = append(c.parts, fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", generation.String, kind))
+		}
+		if nullable == "NO" {
+			c.parts = append(c.parts, "NOT NULL")
+		} else {
+			c.parts = append(c.parts, "NULL")
+		}
+		if def.Valid && generation.String == "" {
+			c.parts = append(c.parts, "DEFAULT "+mysqlDefault(def.String, typ, extra))
+		}
+		if strings.Contains(extra, "AUTO_INCREMENT") {
+			c.parts = append(c.parts, "AUTO_INCREMENT")
+		}
+		if _, onUpdate, ok := strings.Cut(extra, "ON UPDATE "); ok {
+			c.parts = append(c.parts, "ON UPDATE "+strings.Fields(onUpdate)[0])
+		}
+		if comment != "" {
+			c.parts = append(c.parts, "COMMENT "+sqlString(comment))
+		}
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+// mysqlDefault renders COLUMN_DEFAULT as SQL. MySQL reports literals unquoted
+// and marks expressions DEFAULT_GENERATED; MariaDB quotes literals itself.
+func mysqlDefault(def, typ, extra string) string {
+	switch {
+	case currentTimestamp.MatchString(def):
+		return def
+	case strings.Contains(extra, "DEFAULT_GENERATED"):
+		return "(" + def + ")"
+	case strings.HasPrefix(def, "'") || strings.EqualFold(def, "NULL"):
+		return def
+	case isNumericType(typ) && def != "":
+		return def
+	}
+	return sqlString(def)
+}
+
+var numericType = regexp.MustCompile(`(?i)^(tinyint|smallint|mediumint|int|integer|bigint|decimal|numeric|float|double|real|bit)\b`)
+
+func isNumericType(typ string) bool {
+	return numericType.MatchString(typ)
+}
+
+// indexes returns the PRIMARY KEY, UNIQUE KEY and KEY clauses of the table
+func (mysqlReader) indexes(ctx context.Context, db *sql.DB, table string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT INDEX_NAME, NON_UNIQUE, INDEX_TYPE, COLUMN_NAME, SUB_PART
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+		ORDER BY INDEX_NAME = 'PRIMARY' DESC, INDEX_NAME, SEQ_IN_INDEX`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type index struct {
+		name, kind string
+		unique     bool
+		columns    []string
+		functional bool
+	}
+	var order []string
+	byName := map[string]*index{}
+
+	for rows.Next() {
+		var name, kind string
+		var nonUnique int
+		var column sql.NullString
+		var subPart sql.NullInt64
+		if err := rows.Scan(&name, &nonUnique, &kind, &column, &subPart); err != nil {
+			return nil, err
+		}
+		idx := byName[name]
+		if idx == nil {
+			idx = &index{name: name, kind: kind, unique: nonUnique == 0}
+			byName[name] = idx
+			order = append(order, name)
+		}
+		if !column.Valid {
+			// Functional key parts aren't exposed as columns
+			idx.functional = true
+			continue
+		}
+		col := quote(column.String)
+		if subPart.Valid {
+			col += fmt.Sprintf("(%d)", subPart.Int64)
+		}
+		idx.columns = append(idx.columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var clauses []string
+	for _, name := range order {
+		idx := byName[name]
+		cols := strings.Join(idx.columns, ", ")
+		switch {
+		case idx.functional:
+			continue
+		case name == "PRIMARY":
+			clauses = append(clauses, fmt.Sprintf("PRIMARY KEY (%s)", cols))
+		case idx.unique:
+			clauses = append(clauses, fmt.Sprintf("UNIQUE KEY %s (%s)", quote(name), cols))
+		case idx.kind == "FULLTEXT" || idx.kind == "SPATIAL":
+			clauses = append(clauses, fmt.Sprintf("%s KEY %s (%s)", idx.kind, quote(name), cols))
+		default:
+			clauses = append(clauses, fmt.Sprintf("KEY %s (%s)", quote(name), cols))
+		}
+	}
+	return clauses, nil
+}
+
+// foreignKeys returns the CONSTRAINT ... FOREIGN KEY clauses of the table
+func (mysqlReader) foreignKeys(ctx context.Context, db *sql.DB, table string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_NAME, k.REFERENCED_COLUMN_NAME, r.UPDATE_RULE, r.DELETE_RULE
+		FROM information_schema.KEY_COLUMN_USAGE k
+		JOIN information_schema.REFERENTIAL_CONSTRAINTS r
+		  ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME AND r.TABLE_NAME = k.TABLE_NAME
+		WHERE k.TABLE_SCHEMA = DATABASE() AND k.TABLE_NAME = ? AND k.REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type foreignKey struct {
+		refTable, onUpdate, onDelete string
+		columns, refColumns          []string
+	}
+	var order []string
+	byName := map[string]*foreignKey{}
+
+	for rows.Next() {
+		var name, column, refTable, refColumn, onUpdate, onDelete string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			return nil, err
+		}
+		fk := byName[name]
+		if fk == nil {
+			fk = &foreignKey{refTable: refTable, onUpdate: onUpdate, onDelete: onDelete}
+			byName[name] = fk
+			order = append(order, name)
+		}
+		fk.columns = append(fk.columns, column)
+		fk.refColumns = append(fk.refColumns, refColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var clauses []string
+	for _, name := range order {
+		fk := byName[name]
+		clauses = append(clauses, fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)%s%s",
+			quote(name), quoteList(fk.columns), quote(fk.refTable), quoteList(fk.refColumns),
+			referentialAction("DELETE", fk.onDelete), referentialAction("UPDATE", fk.onUpdate)))
+	}
+	return clauses, nil
+}
+
+// queryStrings runs a query returning a single string column
+func queryStrings(ctx context.Context, db *sql.DB, query string, args ...any) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// sqlString quotes s as an SQL string literal
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,234 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// sqliteReader reads sqlite_master and the table pragmas. SQLite has no
+// information_schema, but the pragmas expose the same facts.
+type sqliteReader struct{}
+
+func (sqliteReader) tables(ctx context.Context, db *sql.DB) ([]string, error) {
+	return queryStrings(ctx, db, `
+		SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+		ORDER BY name`)
+}
+
+func (r sqliteReader) define(ctx context.Context, db *sql.DB, table string) (*Definition, error) {
+	var ddl string
+	err := db.QueryRowContext(ctx, `SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&ddl)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	autoIncrement := strings.Contains(strings.ToUpper(ddl), "AUTOINCREMENT")
+
+	columns, primaryKey, generated, err := r.columns(ctx, db, table, autoIncrement)
+	if err != nil {
+		return nil, err
+	}
+	if generated {
+		// The pragmas don't expose generation expressions, so keep the
+		// statement SQLite stored
+		ddl = strings.Replace(ddl, "CREATE TABLE", "CREATE TABLE IF NOT EXISTS", 1) + ";"
+		_, indexes, err := r.indexes(ctx, db, table)
+		if err != nil {
+			return nil, err
+		}
+		return &Definition{Table: table, Statements: append([]string{ddl}, indexes...)}, nil
+	}
+
+	var constraints []string
+	if len(primaryKey) > 1 {
+		constraints = append(constraints, fmt.Sprintf("PRIMARY KEY (%s)", quoteList(primaryKey)))
+	}
+	unique, indexes, err := r.indexes(ctx, db, table)
+	if err != nil {
+		return nil, err
+	}
+	foreignKeys, err := r.foreignKeys(ctx, db, table)
+	if err != nil {
+		return nil, err
+	}
+	constraints = append(append(constraints, unique...), foreignKeys...)
+
+	return &Definition{
+		Table:      table,
+		Statements: append([]string{createTable(table, columns, constraints)}, indexes...),
+	}, nil
+}
+
+// columns returns the table's columns, its primary key and whether any
+// column is generated. A single-column key is declared inline, where SQLite
+// allows AUTOINCREMENT.
+func (sqliteReader) columns(ctx context.Context, db *sql.DB, table string, autoIncrement bool) (defs []columnDef, keyed []string, generated bool, err error) {
+	rows, err := db.QueryContext(ctx, `SELECT name, type, "notnull", dflt_value, pk, hidden FROM pragma_table_xinfo(?) ORDER BY cid`, table)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer rows.Close()
+
+	type column struct {
+		columnDef
+		pk int
+	}
+	var columns []column
+	var primaryKey []string
+	for rows.Next() {
+		var name, typ string
+		var notNull, pk, hidden int
+		var def sql.NullString
+		if err := rows.Scan(&name, &typ, &notNull, &def, &pk, &hidden); err != nil {
+			return nil, nil, false, err
+		}
+		if hidden == 1 {
+			// Hidden columns of virtual tables
+			continue
+		}
+		if typ == "" {
+			typ = "BLOB"
+		}
+
+		c := column{columnDef: columnDef{name: name, parts: []string{typ}}, pk: pk}
+		if hidden == 2 || hidden == 3 {
+			generated = true
+		}
+		if notNull == 1 {
+			c.parts = append(c.parts, "NOT NULL")
+		}
+		if def.Valid {
+			c.parts = append(c.parts, "DEFAULT "+def.String)
+		}
+		columns = append(columns, c)
+		if pk > 0 {
+			primaryKey = append(primaryKey, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, false, err
+	}
+
+	// pk holds each column's position in the key
+	keyed = make([]string, len(primaryKey))
+	defs = make([]columnDef, len(columns))
+	for i, c := range columns {
+		if c.pk > 0 {
+			keyed[c.pk-1] = c.name
+			if len(primaryKey) == 1 {
+				c.parts = append(c.parts, "PRIMARY KEY")
+				if autoIncrement {
+					c.parts = append(c.parts, "AUTOINCREMENT")
+				}
+			}
+		}
+		defs[i] = c.columnDef
+	}
+	return defs, keyed, generated, nil
+}
+
+// indexes returns UNIQUE constraints written inside CREATE TABLE, and the
+// CREATE INDEX statements of indexes created separately
+func (sqliteReader) indexes(ctx context.Context, db *sql.DB, table string) (unique, statements []string, err error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT l.name, l.origin, COALESCE(m.sql, '')
+		FROM pragma_index_list(?) l
+		LEFT JOIN sqlite_master m ON m.type = 'index' AND m.name = l.name
+		ORDER BY l.name`, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	type index struct{ name, origin, ddl string }
+	var indexes []index
+	for rows.Next() {
+		var idx index
+		if err := rows.Scan(&idx.name, &idx.origin, &idx.ddl); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		indexes = append(indexes, idx)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	for _, idx := range indexes {
+		switch idx.origin {
+		case "c":
+			// Created by CREATE INDEX, whose statement SQLite keeps verbatim
+			// (including any WHERE clause of a partial index)
+			ddl := strings.Replace(idx.ddl, "CREATE INDEX", "CREATE INDEX IF NOT EXISTS", 1)
+			ddl = strings.Replace(ddl, "CREATE UNIQUE INDEX", "CREATE UNIQUE INDEX IF NOT EXISTS", 1)
+			statements = append(statements, ddl+";")
+		case "u":
+			cols, err := queryStrings(ctx, db, `SELECT name FROM pragma_index_info(?) ORDER BY seqno`, idx.name)
+			if err != nil {
+				return nil, nil, err
+			}
+			unique = append(unique, fmt.Sprintf("UNIQUE (%s)", quoteList(cols)))
+		}
+	}
+	return unique, statements, nil
+}
+
+// foreignKeys returns the FOREIGN KEY clauses of the table
+func (sqliteReader) foreignKeys(ctx context.Context, db *sql.DB, table string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, "table", "from", "to", on_update, on_delete
+		FROM pragma_foreign_key_list(?)
+		ORDER BY id, seq`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	type foreignKey struct {
+		refTable, onUpdate, onDelete string
+		columns, refColumns          []string
+	}
+	var order []int
+	byID := map[int]*foreignKey{}
+	for rows.Next() {
+		var id int
+		var refTable, column, onUpdate, onDelete string
+		var refColumn sql.NullString
+		if err := rows.Scan(&id, &refTable, &column, &refColumn, &onUpdate, &onDelete); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		fk := byID[id]
+		if fk == nil {
+			fk = &foreignKey{refTable: refTable, onUpdate: onUpdate, onDelete: onDelete}
+			byID[id] = fk
+			order = append(order, id)
+		}
+		fk.columns = append(fk.columns, column)
+		fk.refColumns = append(fk.refColumns, refColumn.String)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var clauses []string
+	for _, id := range order {
+		fk := byID[id]
+		if fk.refColumns[0] == "" {
+			// REFERENCES parent without columns points at the parent's primary key
+			key, err := queryStrings(ctx, db, `SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk`, fk.refTable)
+			if err != nil {
+				return nil, err
+			}
+			fk.refColumns = key
+		}
+		clauses = append(clauses, fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)%s%s",
+			quoteList(fk.columns), quote(fk.refTable), quoteList(fk.refColumns),
+			referentialAction("DELETE", fk.onDelete), referentialAction("UPDATE", fk.onUpdate)))
+	}
+	return clauses, nil
+}
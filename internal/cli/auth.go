@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+	"github.com/urfave/cli/v2"
+)
+
+// AuthSubcommand creates the 'auth' subcommand
+func AuthSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:  "auth",
+		Usage: "Add authentication middleware and per-route role checks",
+		Description: "--jwt adds an auth package whose Authenticate middleware verifies bearer JWTs with the key\n" +
+			"config.Config selects: JWT_JWKS_URL (RS256), JWT_PUBLIC_KEY_FILE (RS256) or JWT_SECRET (HS256).\n" +
+			"It checks JWT_ISSUER and JWT_AUDIENCE when set, answers 401 to requests without a valid token\n" +
+			"outside JWT_PUBLIC_PATHS, and puts the auth.Principal in the request context. Routes restrict\n" +
+			"themselves with auth.RequireRoles(...), which answers 403 to principals without the role.",
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "jwt",
+				Usage: "Authenticate requests with bearer JWTs",
+			},
+		}, conflictFlags()...),
+		Action: addAuthAction,
+	}
+}
+
+// addAuthAction handles the 'add auth' command execution
+func addAuthAction(c *cli.Context) error {
+	rep, err := newReporter(c, "add auth")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(addAuth(c, rep))
+}
+
+func addAuth(c *cli.Context, rep report.Reporter) error {
+	if !c.Bool("jwt") {
+		return &errs.ValidationError{Field: "scheme", Message: "authentication scheme is required", Hint: "usage: ready-go add auth --jwt"}
+	}
+
+	common, err := commonOptions(c, rep)
+	if err != nil {
+		return err
+	}
+
+	_, err = readygo.AddAuth(c.Context, readygo.AuthOptions{
+		JWT:    true,
+		Common: common,
+	})
+	return err
+}
@@ -1,10 +1,13 @@
 package cli
 
 import (
-	"fmt"
+	"os"
+	"strings"
 
 	"github.com/muazwzxv/ready-go-cli/internal/config"
-	"github.com/muazwzxv/ready-go-cli/internal/generator"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
 	"github.com/urfave/cli/v2"
 )
 
@@ -12,10 +15,48 @@ import (
 func Commands() []*cli.Command {
 	return []*cli.Command{
 		NewCommand(),
+		InitCommand(),
 		AddCommand(),
+		GenCommand(),
+		MigrationsCommand(),
 	}
 }
 
+// GlobalFlags returns flags shared by every command
+func GlobalFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Output format: text, json or quiet",
+			Value:   string(report.FormatText),
+			EnvVars: []string{"READY_GO_OUTPUT"},
+		},
+	}
+}
+
+// layoutFlags returns the flags that override where generated directories live
+func layoutFlags() []cli.Flag {
+	defaults := config.DefaultLayout()
+	return []cli.Flag{
+		&cli.StringFlag{Name: "migrations-dir", Usage: "Goose migrations directory", DefaultText: defaults.Migrations},
+		&cli.StringFlag{Name: "queries-dir", Usage: "sqlc queries directory", DefaultText: defaults.Queries},
+		&cli.StringFlag{Name: "entity-dir", Usage: "Entity structs package directory", DefaultText: defaults.Entity},
+		&cli.StringFlag{Name: "handlers-dir", Usage: "HTTP handlers package directory", DefaultText: defaults.Handlers},
+		&cli.StringFlag{Name: "models-dir", Usage: "sqlc generated models package directory", DefaultText: defaults.Models},
+		&cli.StringFlag{Name: "api-dir", Usage: "API server main package directory", DefaultText: defaults.API},
+	}
+}
+
+// newReporter creates the reporter selected by the global --output flag
+func newReporter(c *cli.Context, command string) (report.Reporter, error) {
+	rep, err := report.New(report.Format(c.String("output")), command, os.Stdout)
+	if err != nil {
+		return nil, &errs.ValidationError{Field: "output", Message: err.Error()}
+	}
+	return rep, nil
+}
+
 // AddCommand creates the 'add' command with subcommands
 func AddCommand() *cli.Command {
 	return &cli.Command{
@@ -23,6 +64,13 @@ func AddCommand() *cli.Command {
 		Usage: "Add components to an existing project",
 		Subcommands: []*cli.Command{
 			EntitySubcommand(),
+			MigrationSubcommand(),
+			SeedSubcommand(),
+			DTOSubcommand(),
+			GRPCServiceSubcommand(),
+			EndpointSubcommand(),
+			MiddlewareSubcommand(),
+			AuthSubcommand(),
 		},
 	}
 }
@@ -33,41 +81,68 @@ func EntitySubcommand() *cli.Command {
 		Name:      "entity",
 		Usage:     "Add a new entity with migration to an existing project",
 		ArgsUsage: "<entity-name>",
-		Action:    addEntityAction,
+		Flags: append([]cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "belongs-to",
+				Usage: "Parent entity to reference with a foreign key, e.g. Order (repeatable)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "many-to-many",
+				Usage: "Entity to link through a join table, e.g. Tag (repeatable)",
+			},
+			&cli.BoolFlag{
+				Name:  "soft-delete",
+				Usage: "Add deleted_at: Delete sets it and reads skip deleted rows",
+			},
+			&cli.BoolFlag{
+				Name:  "audit",
+				Usage: "Add created_by and updated_by, filled from the request's actor",
+			},
+			&cli.BoolFlag{
+				Name:  "versioned",
+				Usage: "Add a version column for optimistic locking (409 on conflicting updates)",
+			},
+			&cli.BoolFlag{
+				Name:  "cache",
+				Usage: "Add a Get handler reading through Redis, invalidated by Update and Delete",
+			},
+		}, conflictFlags()...),
+		Action: addEntityAction,
 	}
 }
 
 // addEntityAction handles the 'add entity' command execution
 func addEntityAction(c *cli.Context) error {
+	rep, err := newReporter(c, "add entity")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(addEntity(c, rep))
+}
+
+func addEntity(c *cli.Context, rep report.Reporter) error {
 	entityName := c.Args().First()
 
 	if entityName == "" {
-		return fmt.Errorf("entity name is required\nUsage: ready-go add entity <EntityName>")
+		return &errs.ValidationError{Field: "name", Message: "entity name is required", Hint: "usage: ready-go add entity [--belongs-to Order] [--many-to-many Tag] <EntityName>"}
 	}
 
-	cfg := config.NewEntityConfig(entityName)
-	cfg.Process()
-
-	if err := cfg.Validate(); err != nil {
+	common, err := commonOptions(c, rep)
+	if err != nil {
 		return err
 	}
 
-	fmt.Printf("\n🔍 Detected project at: %s\n", cfg.ProjectPath)
-	fmt.Printf("🚀 Adding entity: %s\n\n", cfg.EntityName)
-
-	gen := generator.NewEntityGenerator(cfg)
-	if err := gen.Generate(); err != nil {
-		return fmt.Errorf("failed to generate entity: %w", err)
-	}
-
-	fmt.Printf("\n✅ Entity '%s' added successfully!\n\n", cfg.EntityName)
-	fmt.Println("Next steps:")
-	fmt.Println("  1. Edit the migration file to customize your table schema")
-	fmt.Println("  2. Add SQLC queries to database/queries/")
-	fmt.Println("  3. Run: make sqlc-generate")
-	fmt.Println("  4. Run: make migrate-up")
-
-	return nil
+	_, err = readygo.AddEntity(c.Context, readygo.EntityOptions{
+		Name:       entityName,
+		BelongsTo:  c.StringSlice("belongs-to"),
+		ManyToMany: c.StringSlice("many-to-many"),
+		SoftDelete: c.Bool("soft-delete"),
+		Audit:      c.Bool("audit"),
+		Versioned:  c.Bool("versioned"),
+		Cache:      c.Bool("cache"),
+		Common:     common,
+	})
+	return err
 }
 
 // NewCommand creates the 'new' command for scaffolding projects
@@ -76,7 +151,7 @@ func NewCommand() *cli.Command {
 		Name:      "new",
 		Usage:     "Create a new Go project",
 		ArgsUsage: "<project-name>",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "module",
 				Aliases: []string{"m"},
@@ -102,69 +177,79 @@ func NewCommand() *cli.Command {
 				Usage: "Kafka port",
 				Value: "9092",
 			},
+			&cli.StringFlag{
+				Name:  "router",
+				Usage: "HTTP router: fiber, echo, chi or stdlib (net/http)",
+				Value: readygo.RouterFiber,
+			},
+			&cli.StringFlag{
+				Name:  "transport",
+				Usage: "Servers to run, comma-separated: http or http,grpc",
+				Value: readygo.TransportHTTP,
+			},
+			&cli.StringFlag{
+				Name:  "grpc-port",
+				Usage: "gRPC server port, with --transport http,grpc",
+				Value: "9090",
+			},
 			&cli.StringFlag{
 				Name:  "sample-name",
 				Usage: "Sample entity name",
 				Value: "User",
 			},
-		},
+			&cli.StringFlag{
+				Name:  "versioning",
+				Usage: "Migration numbering: sequential (00001_) or timestamp (20060102150405_)",
+				Value: string(readygo.VersioningSequential),
+			},
+		}, append(layoutFlags(), conflictFlags()...)...),
 		Action: newProjectAction,
 	}
 }
 
 // newProjectAction handles the 'new' command execution
 func newProjectAction(c *cli.Context) error {
-	projectName := c.Args().First()
-
-	if projectName == "" {
-		return fmt.Errorf("project name is required\nUsage: ready-go new [flags] <project-name>")
-	}
-
-	cfg := config.NewProjectConfig(projectName)
-
-	// Apply flags
-	if c.IsSet("module") {
-		cfg.ModuleName = c.String("module")
-	}
-	if c.IsSet("port") {
-		cfg.ServerPort = c.String("port")
-	}
-	if c.IsSet("db-port") {
-		cfg.DBPort = c.String("db-port")
-	}
-	if c.IsSet("redis-port") {
-		cfg.RedisPort = c.String("redis-port")
-	}
-	if c.IsSet("kafka-port") {
-		cfg.KafkaPort = c.String("kafka-port")
-	}
-	if c.IsSet("sample-name") {
-		cfg.SampleAPIName = c.String("sample-name")
+	rep, err := newReporter(c, "new")
+	if err != nil {
+		return err
 	}
+	return rep.Finish(newProject(c, rep))
+}
 
-	cfg.Process()
+func newProject(c *cli.Context, rep report.Reporter) error {
+	projectName := c.Args().First()
 
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+	if projectName == "" {
+		return &errs.ValidationError{Field: "name", Message: "project name is required", Hint: "usage: ready-go new [flags] <project-name>"}
 	}
 
-	fmt.Printf("\n🚀 Creating project: %s\n", cfg.ProjectName)
-	fmt.Printf("📦 Module: %s\n", cfg.ModuleName)
-	fmt.Printf("🎯 Sample API: %s\n\n", cfg.SampleAPIName)
-
-	gen := generator.NewProjectGenerator(cfg)
-	if err := gen.Generate(); err != nil {
-		return fmt.Errorf("failed to generate project: %w", err)
+	common, err := commonOptions(c, rep)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("\n✅ Project successfully created at ./%s\n\n", cfg.ProjectName)
-	fmt.Println("Next steps:")
-	fmt.Printf("  cd %s\n", cfg.ProjectName)
-	fmt.Println("  make docker-up      # Start all services")
-	fmt.Println("  make migrate-up     # Run migrations")
-	fmt.Println("  make sqlc-generate  # Generate SQLC models")
-	fmt.Println("  make run-api        # Start the application")
-	fmt.Printf("\n🌐 Access your application at http://localhost:%s\n", cfg.ServerPort)
-
-	return nil
+	// Flags carry the CLI defaults, so they can be passed through unconditionally
+	_, err = readygo.NewProject(c.Context, readygo.ProjectOptions{
+		Name:       projectName,
+		Module:     c.String("module"),
+		ServerPort: c.String("port"),
+		DBPort:     c.String("db-port"),
+		RedisPort:  c.String("redis-port"),
+		KafkaPort:  c.String("kafka-port"),
+		GRPCPort:   c.String("grpc-port"),
+		Router:     c.String("router"),
+		Transports: strings.Split(c.String("transport"), ","),
+		SampleName: c.String("sample-name"),
+		Versioning: readygo.Versioning(c.String("versioning")),
+		Layout: readygo.Layout{
+			Migrations: c.String("migrations-dir"),
+			Queries:    c.String("queries-dir"),
+			Entity:     c.String("entity-dir"),
+			Handlers:   c.String("handlers-dir"),
+			Models:     c.String("models-dir"),
+			API:        c.String("api-dir"),
+		},
+		Common: common,
+	})
+	return err
 }
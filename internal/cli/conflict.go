@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+	"github.com/urfave/cli/v2"
+)
+
+// conflictFlags returns the flags that select how existing files are handled
+func conflictFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "force",
+			Aliases: []string{"f"},
+			Usage:   "Overwrite existing files",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-existing",
+			Usage: "Keep existing files and only generate missing ones",
+		},
+		&cli.BoolFlag{
+			Name:    "interactive",
+			Aliases: []string{"i"},
+			Usage:   "Show a diff and ask before overwriting each existing file",
+		},
+	}
+}
+
+// conflictPolicy reads the conflict flags; at most one of them may be set
+func conflictPolicy(c *cli.Context) (readygo.ConflictPolicy, readygo.ConflictResolver, error) {
+	var set []string
+	for _, name := range []string{"force", "skip-existing", "interactive"} {
+		if c.Bool(name) {
+			set = append(set, "--"+name)
+		}
+	}
+	if len(set) > 1 {
+		return "", nil, &errs.ValidationError{
+			Field:   "conflict",
+			Message: fmt.Sprintf("%s cannot be combined", strings.Join(set, " and ")),
+			Hint:    "pick one of --force, --skip-existing or --interactive",
+		}
+	}
+
+	switch {
+	case c.Bool("force"):
+		return readygo.ConflictOverwrite, nil, nil
+	case c.Bool("skip-existing"):
+		return readygo.ConflictSkip, nil, nil
+	case c.Bool("interactive"):
+		return readygo.ConflictPrompt, newTerminalResolver(os.Stdin, os.Stderr), nil
+	default:
+		return readygo.ConflictFail, nil, nil
+	}
+}
+
+// commonOptions reads the conflict flags into the options every command
+// passes to readygo, reporting to rep
+func commonOptions(c *cli.Context, rep readygo.Reporter) (readygo.Common, error) {
+	policy, resolver, err := conflictPolicy(c)
+	if err != nil {
+		return readygo.Common{}, err
+	}
+	return readygo.Common{Conflict: policy, Resolver: resolver, Reporter: rep}, nil
+}
+
+// terminalResolver shows a diff and asks on the terminal whether to overwrite each
+// conflicting file. It writes to stderr so that --output json stays parseable.
+type terminalResolver struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newTerminalResolver(in io.Reader, out io.Writer) *terminalResolver {
+	return &terminalResolver{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+func (r *terminalResolver) Resolve(path, diff string) (readygo.Resolution, error) {
+	fmt.Fprintf(r.out, "\n%s\n", diff)
+
+	for {
+		fmt.Fprintf(r.out, "Overwrite %s? [y]es, [n]o, [a]ll, [q]uit: ", path)
+
+		line, err := r.in.ReadString('\n')
+		if err != nil && line == "" {
+			// No more input (e.g. stdin closed): leave the project untouched
+			return readygo.ResolveAbort, nil
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes":
+			return readygo.ResolveOverwrite, nil
+		case "n", "no":
+			return readygo.ResolveSkip, nil
+		case "a", "all":
+			return readygo.ResolveOverwriteAll, nil
+		case "q", "quit":
+			return readygo.ResolveAbort, nil
+		}
+	}
+}
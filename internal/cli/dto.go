@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+	"github.com/urfave/cli/v2"
+)
+
+// DTOSubcommand creates the 'dto' subcommand
+func DTOSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "dto",
+		Usage:     "Add Create and Update request DTOs with validate tags for an existing table",
+		ArgsUsage: "<entity-name>",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "table",
+				Usage: "Table of the entity (default: plural of the entity name, e.g. products)",
+			},
+		}, conflictFlags()...),
+		Action: addDTOAction,
+	}
+}
+
+// addDTOAction handles the 'add dto' command execution
+func addDTOAction(c *cli.Context) error {
+	rep, err := newReporter(c, "add dto")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(addDTO(c, rep))
+}
+
+func addDTO(c *cli.Context, rep report.Reporter) error {
+	entityName := c.Args().First()
+
+	if entityName == "" {
+		return &errs.ValidationError{Field: "name", Message: "entity name is required", Hint: "usage: ready-go add dto [flags] <EntityName>"}
+	}
+
+	common, err := commonOptions(c, rep)
+	if err != nil {
+		return err
+	}
+
+	_, err = readygo.AddDTO(c.Context, readygo.DTOOptions{
+		Entity: entityName,
+		Table:  c.String("table"),
+		Common: common,
+	})
+	return err
+}
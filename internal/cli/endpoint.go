@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"flag"
+	"io"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+	"github.com/urfave/cli/v2"
+)
+
+const endpointUsage = "usage: ready-go add endpoint <METHOD> <path> [flags], e.g. ready-go add endpoint POST /v1/orders/:id/cancel --package order --name Cancel"
+
+// EndpointSubcommand creates the 'endpoint' subcommand
+func EndpointSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "endpoint",
+		Aliases:   []string{"handler"},
+		Usage:     "Add a handler with typed binding and a test for a route that is not an entity CRUD",
+		ArgsUsage: "<METHOD> <path> [flags]",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "package",
+				Usage: "Handler package (default: singular of the first path segment, e.g. order)",
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Handler name without the Handler suffix (default: last path segment, e.g. Cancel)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "query",
+				Usage: "Query parameter: name[:type], type string, int, int32, int64, float, bool or time (repeatable)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "body",
+				Usage: "JSON body field: name[:type], type string, int, int32, int64, float, bool or time (repeatable)",
+			},
+		}, conflictFlags()...),
+		Action: addEndpointAction,
+	}
+}
+
+// addEndpointAction handles the 'add endpoint' command execution
+func addEndpointAction(c *cli.Context) error {
+	rep, err := newReporter(c, "add endpoint")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(addEndpoint(c, rep))
+}
+
+func addEndpoint(c *cli.Context, rep report.Reporter) error {
+	if c.NArg() < 2 {
+		return &errs.ValidationError{Field: "route", Message: "method and path are required", Hint: endpointUsage}
+	}
+	if err := parseTrailingFlags(c, 2, endpointUsage); err != nil {
+		return err
+	}
+
+	common, err := commonOptions(c, rep)
+	if err != nil {
+		return err
+	}
+
+	_, err = readygo.AddEndpoint(c.Context, readygo.EndpointOptions{
+		Method:  c.Args().Get(0),
+		Path:    c.Args().Get(1),
+		Package: c.String("package"),
+		Name:    c.String("name"),
+		Query:   c.StringSlice("query"),
+		Body:    c.StringSlice("body"),
+		Common:  common,
+	})
+	return err
+}
+
+// parseTrailingFlags applies the flags following the first n arguments, which
+// urfave/cli leaves unparsed as it stops at the first argument
+func parseTrailingFlags(c *cli.Context, n int, usage string) error {
+	tail := c.Args().Slice()[n:]
+	if len(tail) == 0 {
+		return nil
+	}
+
+	fs := flag.NewFlagSet(c.Command.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	// A GenericFlag registers its own Value, so parsing fs already fills it
+	shared := map[string]bool{}
+	for _, f := range c.Command.Flags {
+		if err := f.Apply(fs); err != nil {
+			return err
+		}
+		if _, ok := f.(*cli.GenericFlag); ok {
+			for _, name := range f.Names() {
+				shared[name] = true
+			}
+		}
+	}
+	if err := fs.Parse(tail); err != nil {
+		return &errs.ValidationError{Field: "flags", Message: err.Error(), Hint: usage}
+	}
+	if fs.NArg() > 0 {
+		return &errs.ValidationError{Field: "args", Message: "unexpected arguments: " + strings.Join(fs.Args(), " "), Hint: usage}
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if shared[f.Name] {
+			return
+		}
+		values := []string{f.Value.String()}
+		if slice, ok := f.Value.(*cli.StringSlice); ok {
+			values = slice.Value()
+		}
+		for _, v := range values {
+			if setErr := c.Set(f.Name, v); setErr != nil && err == nil {
+				err = setErr
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/urfave/cli/v2"
+)
+
+func TestParseTrailingFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		pkg     string
+		query   []string
+		force   bool
+		wantErr bool
+	}{
+		{name: "flags first", args: []string{"--package", "order", "--query", "a,b:int", "POST", "/v1/orders"}, pkg: "order", query: []string{"a", "b:int"}},
+		{name: "flags last", args: []string{"POST", "/v1/orders", "--package", "order", "--query", "a", "--query=b:int", "--force"}, pkg: "order", query: []string{"a", "b:int"}, force: true},
+		{name: "both", args: []string{"--query", "a", "POST", "/v1/orders", "--query", "b:int"}, query: []string{"a", "b:int"}},
+		{name: "no flags", args: []string{"GET", "/v1/orders"}},
+		{name: "extra argument", args: []string{"POST", "/v1/orders", "extra"}, wantErr: true},
+		{name: "argument after flags", args: []string{"POST", "/v1/orders", "--package", "order", "extra"}, wantErr: true},
+		{name: "unknown flag", args: []string{"POST", "/v1/orders", "--nope"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := EndpointSubcommand()
+			var err error
+			cmd.Action = func(c *cli.Context) error {
+				if err = parseTrailingFlags(c, 2, endpointUsage); err != nil {
+					return nil
+				}
+				if got := c.String("package"); got != tt.pkg {
+					t.Errorf("package = %q, want %q", got, tt.pkg)
+				}
+				if got := c.StringSlice("query"); !slices.Equal(got, tt.query) {
+					t.Errorf("query = %q, want %q", got, tt.query)
+				}
+				if got := c.Bool("force"); got != tt.force {
+					t.Errorf("force = %v, want %v", got, tt.force)
+				}
+				return nil
+			}
+			app := &cli.App{Commands: []*cli.Command{cmd}}
+			if runErr := app.Run(append([]string{"ready-go", "endpoint"}, tt.args...)); runErr != nil {
+				t.Fatal(runErr)
+			}
+
+			var verr *errs.ValidationError
+			if tt.wantErr != errors.As(err, &verr) {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+	"github.com/urfave/cli/v2"
+)
+
+// GRPCServiceSubcommand creates the 'grpc-service' subcommand
+func GRPCServiceSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "grpc-service",
+		Usage:     "Add a gRPC service with its proto definition, adding the gRPC server if the project has none",
+		ArgsUsage: "<service-name>",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "grpc-port",
+				Usage: "Default GRPC_PORT when the gRPC server is added",
+				Value: "9090",
+			},
+		}, conflictFlags()...),
+		Action: addGRPCServiceAction,
+	}
+}
+
+// addGRPCServiceAction handles the 'add grpc-service' command execution
+func addGRPCServiceAction(c *cli.Context) error {
+	rep, err := newReporter(c, "add grpc-service")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(addGRPCService(c, rep))
+}
+
+func addGRPCService(c *cli.Context, rep report.Reporter) error {
+	serviceName := c.Args().First()
+
+	if serviceName == "" {
+		return &errs.ValidationError{Field: "name", Message: "service name is required", Hint: "usage: ready-go add grpc-service [flags] <ServiceName>"}
+	}
+
+	common, err := commonOptions(c, rep)
+	if err != nil {
+		return err
+	}
+
+	_, err = readygo.AddGRPCService(c.Context, readygo.GRPCServiceOptions{
+		Service:  serviceName,
+		GRPCPort: c.String("grpc-port"),
+		Common:   common,
+	})
+	return err
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+	"github.com/urfave/cli/v2"
+)
+
+// pieceDescriptions explains each optional piece when offering it
+var pieceDescriptions = map[readygo.Piece]string{
+	readygo.PieceMakefile: "Makefile targets for migrations and sqlc",
+	readygo.PieceSQLC:     "sqlc.yaml",
+	readygo.PieceUtil:     "handlers util package (error and success helpers)",
+}
+
+// InitCommand creates the 'init' command for adopting an existing project
+func InitCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "init",
+		Usage:     "Adopt an existing Go service into ready-go conventions",
+		ArgsUsage: "[project-dir]",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "module",
+				Aliases: []string{"m"},
+				Usage:   "Go module name (default: read from go.mod)",
+			},
+			&cli.StringFlag{
+				Name:  "engine",
+				Usage: "Database engine: mysql, postgresql or sqlite (default: detected)",
+			},
+			&cli.StringFlag{
+				Name:  "router",
+				Usage: "Router framework: fiber, echo, chi or stdlib (default: detected)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "with",
+				Usage: "Missing pieces to add: makefile, sqlc, util",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Add every missing piece without asking",
+			},
+		}, conflictFlags()...),
+		Action: initAction,
+	}
+}
+
+// initAction handles the 'init' command execution
+func initAction(c *cli.Context) error {
+	rep, err := newReporter(c, "init")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(initProject(c, rep))
+}
+
+func initProject(c *cli.Context, rep report.Reporter) error {
+	dir := c.Args().First()
+
+	common, err := commonOptions(c, rep)
+	if err != nil {
+		return err
+	}
+	common.ProjectDir = dir
+
+	project, err := readygo.Inspect(c.Context, dir)
+	if err != nil {
+		return err
+	}
+	reportInspection(rep, project)
+
+	var pieces []readygo.Piece
+	switch {
+	case c.Bool("yes"):
+		pieces = project.Missing()
+	case c.IsSet("with"):
+		for _, piece := range c.StringSlice("with") {
+			pieces = append(pieces, readygo.Piece(strings.TrimSpace(piece)))
+		}
+	case c.String("output") == string(report.FormatText) && isTerminal(os.Stdin):
+		pieces = askForPieces(os.Stdin, os.Stderr, project.Missing())
+	}
+
+	_, err = readygo.Init(c.Context, readygo.InitOptions{
+		Module: c.String("module"),
+		Engine: c.String("engine"),
+		Router: c.String("router"),
+		Add:    pieces,
+		Common: common,
+	})
+	return err
+}
+
+// reportInspection prints what was detected in the existing project
+func reportInspection(rep report.Reporter, p *readygo.Inspection) {
+	found := func(value, source string) string {
+		if value == "" {
+			return "not found"
+		}
+		if source != "" {
+			return fmt.Sprintf("%s (from %s)", value, source)
+		}
+		return value
+	}
+
+	rep.Info(fmt.Sprintf("\n🔍 Inspecting %s", p.Dir))
+	rep.Info(fmt.Sprintf("  Module:       %s", p.Module))
+	rep.Info(fmt.Sprintf("  Engine:       %s", found(p.Engine, p.EngineSource)))
+	migrations := found(p.Migrations, "")
+	if p.Versioning != "" {
+		migrations += fmt.Sprintf(", %s versioning", p.Versioning)
+	}
+	rep.Info(fmt.Sprintf("  Migrations:   %s", migrations))
+	rep.Info(fmt.Sprintf("  Queries:      %s", found(p.Queries, "")))
+	rep.Info(fmt.Sprintf("  Router:       %s", found(p.Router, "")))
+	if p.RouterSetup != "" {
+		rep.Info(fmt.Sprintf("  Router setup: %s (%s)", p.RouterSetup, p.RouterFunc))
+	} else {
+		rep.Info("  Router setup: not found")
+	}
+	rep.Info("")
+}
+
+// askForPieces offers each missing piece on the terminal
+func askForPieces(in io.Reader, out io.Writer, missing []readygo.Piece) []readygo.Piece {
+	reader := bufio.NewReader(in)
+
+	var pieces []readygo.Piece
+	for _, piece := range missing {
+		fmt.Fprintf(out, "Add %s? [y/N]: ", pieceDescriptions[piece])
+		line, _ := reader.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if answer == "y" || answer == "yes" {
+			pieces = append(pieces, piece)
+		}
+	}
+	return pieces
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
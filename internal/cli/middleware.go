@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+	"github.com/urfave/cli/v2"
+)
+
+const middlewareUsage = "usage: ready-go add middleware <name>..., one or more of request-id, recovery, security-headers, cors, rate-limit, gzip, body-limit, timeout"
+
+// MiddlewareSubcommand creates the 'middleware' subcommand
+func MiddlewareSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "middleware",
+		Usage:     "Add production middleware, configured from config.Config, to SetupHandler",
+		ArgsUsage: "<name>...",
+		Description: "Installs middleware in the order requests pass through them, whatever order they are given in:\n\n" +
+			"   request-id → recovery → security-headers → cors → rate-limit → gzip → body-limit → timeout\n\n" +
+			"The project's LoggingMiddleware runs after request-id. Each middleware reads its settings from\n" +
+			"config.Config, loaded from environment variables added to .env.example.",
+		Flags:  conflictFlags(),
+		Action: addMiddlewareAction,
+	}
+}
+
+// addMiddlewareAction handles the 'add middleware' command execution
+func addMiddlewareAction(c *cli.Context) error {
+	rep, err := newReporter(c, "add middleware")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(addMiddleware(c, rep))
+}
+
+func addMiddleware(c *cli.Context, rep report.Reporter) error {
+	if c.NArg() == 0 {
+		return &errs.ValidationError{Field: "name", Message: "middleware name is required", Hint: middlewareUsage}
+	}
+
+	common, err := commonOptions(c, rep)
+	if err != nil {
+		return err
+	}
+
+	_, err = readygo.AddMiddleware(c.Context, readygo.MiddlewareOptions{
+		Names:  c.Args().Slice(),
+		Common: common,
+	})
+	return err
+}
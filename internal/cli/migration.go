@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+	"github.com/urfave/cli/v2"
+)
+
+// specList collects every value of a repeatable flag. Unlike StringSliceFlag
+// it does not split on commas, so specs like "products(sku,name)" and
+// "price:decimal(12,2)" survive intact.
+type specList []string
+
+func (l *specList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func (l *specList) String() string {
+	return strings.Join(*l, " ")
+}
+
+const migrationUsage = "usage: ready-go add migration <migration-name> [flags], e.g. ready-go add migration add_sku_to_products --add-column products.sku:string:unique"
+
+// MigrationSubcommand creates the 'migration' subcommand
+func MigrationSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "migration",
+		Usage:     "Add a goose migration, optionally generating ALTER TABLE statements",
+		ArgsUsage: "<migration-name> [flags]",
+		Flags: append([]cli.Flag{
+			&cli.GenericFlag{
+				Name:  "add-column",
+				Usage: "Add a column: table.column:type[:unique][:index][:notnull][:default=value] (repeatable)",
+				Value: &specList{},
+			},
+			&cli.GenericFlag{
+				Name:  "add-index",
+				Usage: "Add an index: table(column[,column...])[:unique] (repeatable)",
+				Value: &specList{},
+			},
+			&cli.GenericFlag{
+				Name:  "drop-column",
+				Usage: "Drop a column: table.column (repeatable)",
+				Value: &specList{},
+			},
+		}, conflictFlags()...),
+		Action: addMigrationAction,
+	}
+}
+
+// addMigrationAction handles the 'add migration' command execution
+func addMigrationAction(c *cli.Context) error {
+	rep, err := newReporter(c, "add migration")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(addMigration(c, rep))
+}
+
+func addMigration(c *cli.Context, rep report.Reporter) error {
+	name := c.Args().First()
+
+	if name == "" {
+		return &errs.ValidationError{Field: "name", Message: "migration name is required", Hint: migrationUsage}
+	}
+	if err := parseTrailingFlags(c, 1, migrationUsage); err != nil {
+		return err
+	}
+
+	common, err := commonOptions(c, rep)
+	if err != nil {
+		return err
+	}
+
+	_, err = readygo.AddMigration(c.Context, readygo.MigrationOptions{
+		Name:        name,
+		AddColumns:  specs(c, "add-column"),
+		AddIndexes:  specs(c, "add-index"),
+		DropColumns: specs(c, "drop-column"),
+		Common:      common,
+	})
+	return err
+}
+
+// specs returns the values collected by a specList flag
+func specs(c *cli.Context, name string) []string {
+	if l, ok := c.Generic(name).(*specList); ok {
+		return *l
+	}
+	return nil
+}
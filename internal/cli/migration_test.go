@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/urfave/cli/v2"
+)
+
+func TestAddMigrationTrailingFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "flags after the name",
+			args: []string{"add_sku_to_products", "--add-column", "products.sku:string:unique", "--add-index", "products(sku,name)", "--drop-column", "products.legacy"},
+			want: []string{"ADD COLUMN sku", "CREATE UNIQUE INDEX", "CREATE INDEX idx_products_sku_name ON products (sku, name)", "DROP COLUMN legacy"},
+		},
+		{
+			name: "flags on both sides",
+			args: []string{"--add-column", "products.sku:string", "add_sku_to_products", "--add-column", "products.weight:int"},
+			want: []string{"ADD COLUMN sku", "ADD COLUMN weight"},
+		},
+		{
+			name:    "extra argument",
+			args:    []string{"add_sku_to_products", "products.sku:string"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			migrations := filepath.Join(dir, "database", "migrations")
+			if err := os.MkdirAll(migrations, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			files := map[string]string{
+				"go.mod": "module example.com/p\n\ngo 1.23\n",
+				"database/migrations/00001_create_products.sql": "-- +goose Up\nCREATE TABLE products (id BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, legacy INT);\n",
+			}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Chdir(dir)
+
+			app := &cli.App{Flags: GlobalFlags(), Commands: []*cli.Command{AddCommand()}, ExitErrHandler: func(*cli.Context, error) {}}
+			err := app.Run(append([]string{"ready-go", "--output", "quiet", "add", "migration"}, tt.args...))
+			var verr *errs.ValidationError
+			if tt.wantErr {
+				if !errors.As(err, &verr) {
+					t.Fatalf("err = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := os.ReadFile(filepath.Join(migrations, "00002_add_sku_to_products.sql"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("migration lacks %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
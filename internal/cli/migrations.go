@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+	"github.com/urfave/cli/v2"
+)
+
+// MigrationsCommand creates the 'migrations' command for maintaining existing migrations
+func MigrationsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrations",
+		Usage: "Check and repair migration version numbers",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "check",
+				Usage:  "Detect duplicate versions, out-of-order merges, gaps and mixed numbering",
+				Action: checkMigrationsAction,
+			},
+			{
+				Name:  "renumber",
+				Usage: "Move duplicate and out-of-order migrations after the existing ones",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Show the renames without making them",
+					},
+				},
+				Action: renumberMigrationsAction,
+			},
+		},
+	}
+}
+
+// checkMigrationsAction handles the 'migrations check' command execution
+func checkMigrationsAction(c *cli.Context) error {
+	rep, err := newReporter(c, "migrations check")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(checkMigrations(c, rep))
+}
+
+func checkMigrations(c *cli.Context, rep report.Reporter) error {
+	_, err := readygo.CheckMigrations(c.Context, readygo.MigrationsOptions{Common: readygo.Common{Reporter: rep}})
+	return err
+}
+
+// renumberMigrationsAction handles the 'migrations renumber' command execution
+func renumberMigrationsAction(c *cli.Context) error {
+	rep, err := newReporter(c, "migrations renumber")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(renumberMigrations(c, rep))
+}
+
+func renumberMigrations(c *cli.Context, rep report.Reporter) error {
+	_, err := readygo.RenumberMigrations(c.Context, readygo.MigrationsOptions{
+		DryRun: c.Bool("dry-run"),
+		Common: readygo.Common{Reporter: rep},
+	})
+	return err
+}
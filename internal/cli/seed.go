@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+	"github.com/urfave/cli/v2"
+)
+
+// SeedSubcommand creates the 'seed' subcommand
+func SeedSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:      "seed",
+		Usage:     "Add a deterministic fake-data seeder for an entity, run with make seed",
+		ArgsUsage: "<entity-name>",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "table",
+				Usage: "Table of the entity (default: plural of the entity name, e.g. products)",
+			},
+		}, conflictFlags()...),
+		Action: addSeedAction,
+	}
+}
+
+// addSeedAction handles the 'add seed' command execution
+func addSeedAction(c *cli.Context) error {
+	rep, err := newReporter(c, "add seed")
+	if err != nil {
+		return err
+	}
+	return rep.Finish(addSeed(c, rep))
+}
+
+func addSeed(c *cli.Context, rep report.Reporter) error {
+	entityName := c.Args().First()
+
+	if entityName == "" {
+		return &errs.ValidationError{Field: "name", Message: "entity name is required", Hint: "usage: ready-go add seed [flags] <EntityName>"}
+	}
+
+	common, err := commonOptions(c, rep)
+	if err != nil {
+		return err
+	}
+
+	_, err = readygo.AddSeed(c.Context, readygo.SeedOptions{
+		Entity: entityName,
+		Table:  c.String("table"),
+		Common: common,
+	})
+	return err
+}
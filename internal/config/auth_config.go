@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+)
+
+// AuthConfig holds configuration for adding authentication to a project
+type AuthConfig struct {
+	JWT         bool   // bearer JWTs, the only scheme so far
+	ProjectPath string // current working directory
+	ModuleName  string // Go module path, from the manifest or go.mod
+	Layout      Layout // from the project manifest
+	Router      Router // from the project manifest
+}
+
+// NewAuthConfig creates a new AuthConfig
+func NewAuthConfig(jwt bool) *AuthConfig {
+	m := DefaultManifest("")
+	return &AuthConfig{
+		JWT:    jwt,
+		Layout: m.Layout,
+		Router: m.Router,
+	}
+}
+
+// ApplyManifest takes the layout, module and router from the manifest in ProjectPath, if there is one
+func (c *AuthConfig) ApplyManifest() error {
+	m, _, err := LoadManifest(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	c.Layout = m.Layout
+	c.Router = m.Router
+	c.ModuleName = m.Module
+	if c.ModuleName == "" {
+		c.ModuleName = readModulePath(c.ProjectPath)
+	}
+	return nil
+}
+
+// Process fills in the project path
+func (c *AuthConfig) Process() {
+	if c.ProjectPath == "" {
+		c.ProjectPath, _ = os.Getwd()
+	}
+}
+
+// Validate checks that a scheme is chosen, and that the project has a handlers directory
+func (c *AuthConfig) Validate() error {
+	if !c.JWT {
+		return &errs.ValidationError{Field: "scheme", Message: "authentication scheme is required", Hint: "use --jwt"}
+	}
+
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, "go.mod")); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "go.mod not found in %s", c.ProjectPath)
+	}
+	if c.ModuleName == "" {
+		return errs.Validation("add a module directive to go.mod", "module path not found in %s", filepath.Join(c.ProjectPath, "go.mod"))
+	}
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, filepath.FromSlash(c.Layout.Handlers))); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "%s directory not found - is this a ready-go project?", c.Layout.Handlers)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// EndpointConfig holds configuration for adding a single handler and its route
+type EndpointConfig struct {
+	Method      string // upper case: "POST"
+	Path        string // Fiber syntax: "/v1/orders/:id/cancel"
+	Package     string // handler package: "order"
+	Name        string // PascalCase, without the Handler suffix: "Cancel"
+	ProjectPath string // current working directory
+	ModuleName  string // Go module path, from the manifest or go.mod
+	Layout      Layout // from the project manifest
+	Router      Router // from the project manifest
+
+	// Query and Body fields as given on the command line, e.g. "limit:int"
+	Query []string
+	Body  []string
+	// QueryFields and BodyFields are Query and Body parsed by Process
+	QueryFields []EndpointField
+	BodyFields  []EndpointField
+}
+
+// EndpointField is a query or body field of an endpoint
+type EndpointField struct {
+	Name string // as sent by clients: "reason"
+	Type string // as given: "string", "int", "time"
+}
+
+// EndpointFieldTypes maps the field types accepted by add endpoint to Go types
+var EndpointFieldTypes = map[string]string{
+	"string":  "string",
+	"int":     "int",
+	"int32":   "int32",
+	"int64":   "int64",
+	"float":   "float64",
+	"float64": "float64",
+	"bool":    "bool",
+	"time":    "time.Time",
+}
+
+// EndpointMethods lists the HTTP methods add endpoint registers
+var EndpointMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+var (
+	paramPattern   = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+	versionPattern = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// NewEndpointConfig creates a new EndpointConfig for method and path
+func NewEndpointConfig(method, path string) *EndpointConfig {
+	m := DefaultManifest("")
+	return &EndpointConfig{
+		Method: method,
+		Path:   path,
+		Layout: m.Layout,
+		Router: m.Router,
+	}
+}
+
+// ApplyManifest takes the layout, module and router from the manifest in ProjectPath, if there is one
+func (c *EndpointConfig) ApplyManifest() error {
+	m, _, err := LoadManifest(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	c.Layout = m.Layout
+	c.Router = m.Router
+	c.ModuleName = m.Module
+	if c.ModuleName == "" {
+		c.ModuleName = readModulePath(c.ProjectPath)
+	}
+	return nil
+}
+
+// Process normalizes the method and path, converting {id} parameters to
+// :id, and derives the package and name the path implies when they are not
+// given: POST /v1/orders/:id/cancel → package order, name Cancel
+func (c *EndpointConfig) Process() {
+	c.Method = strings.ToUpper(strings.TrimSpace(c.Method))
+
+	c.Path = strings.TrimSpace(c.Path)
+	if len(c.Path) > 1 {
+		c.Path = strings.TrimSuffix(c.Path, "/")
+	}
+	segments := strings.Split(c.Path, "/")
+	var static []string
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			segments[i] = ":" + strings.Trim(seg, "{}")
+			continue
+		}
+		if seg != "" && !strings.HasPrefix(seg, ":") && !versionPattern.MatchString(seg) && seg != "api" {
+			static = append(static, seg)
+		}
+	}
+	c.Path = strings.Join(segments, "/")
+
+	if c.Package == "" && len(static) > 0 {
+		c.Package = Singularize(static[0])
+	}
+	c.Package = strings.ToLower(strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(c.Package)))
+
+	if c.Name == "" && len(static) > 1 {
+		c.Name = schema.GoName(static[len(static)-1])
+	}
+	c.Name = upperFirst(strings.TrimSpace(c.Name))
+	if name := strings.TrimSuffix(c.Name, "Handler"); name != "" {
+		c.Name = name
+	}
+
+	c.QueryFields = endpointFields(c.Query)
+	c.BodyFields = endpointFields(c.Body)
+
+	if c.ProjectPath == "" {
+		c.ProjectPath, _ = os.Getwd()
+	}
+}
+
+// endpointFields parses name[:type] field specs, string by default
+func endpointFields(specs []string) []EndpointField {
+	var fields []EndpointField
+	for _, spec := range specs {
+		name, typ, _ := strings.Cut(strings.TrimSpace(spec), ":")
+		if typ == "" {
+			typ = "string"
+		}
+		fields = append(fields, EndpointField{Name: name, Type: strings.ToLower(typ)})
+	}
+	return fields
+}
+
+// Params returns the names of the path parameters: id for /v1/orders/:id/cancel
+func (c *EndpointConfig) Params() []string {
+	var params []string
+	for _, seg := range strings.Split(c.Path, "/") {
+		if name, ok := strings.CutPrefix(seg, ":"); ok {
+			params = append(params, name)
+		}
+	}
+	return params
+}
+
+// Validate checks the route, names and fields, and that the project has a handlers directory
+func (c *EndpointConfig) Validate() error {
+	if !slices.Contains(EndpointMethods, c.Method) {
+		return &errs.ValidationError{Field: "method", Message: "unsupported method " + strings.TrimSpace(c.Method), Hint: "use one of " + strings.Join(EndpointMethods, ", ")}
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		return &errs.ValidationError{Field: "path", Message: "path must start with /", Hint: "e.g. /v1/orders/:id/cancel"}
+	}
+	params := c.Params()
+	for i, param := range params {
+		if !paramPattern.MatchString(param) {
+			return &errs.ValidationError{Field: "path", Message: "path parameter :" + param + " must be snake_case (e.g., :id, :order_id)"}
+		}
+		if slices.Contains(params[:i], param) {
+			return &errs.ValidationError{Field: "path", Message: "path parameter :" + param + " appears twice"}
+		}
+	}
+
+	if c.Package == "" {
+		return &errs.ValidationError{Field: "package", Message: "handler package cannot be derived from " + c.Path, Hint: "pass --package, e.g. --package order"}
+	}
+	if !regexp.MustCompile(`^[a-z][a-z0-9]*$`).MatchString(c.Package) {
+		return &errs.ValidationError{Field: "package", Message: "package must be a lower-case Go package name (e.g., order)"}
+	}
+	if token.IsKeyword(c.Package) || c.Package == "util" || c.Package == "cmd" {
+		return &errs.ValidationError{Field: "package", Message: "package name " + c.Package + " is reserved", Hint: "choose a different package, e.g. --package " + c.Package + "s"}
+	}
+	if c.Name == "" {
+		return &errs.ValidationError{Field: "name", Message: "handler name cannot be derived from " + c.Path, Hint: "pass --name, e.g. --name Cancel"}
+	}
+	if !regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`).MatchString(c.Name) {
+		return &errs.ValidationError{Field: "name", Message: "handler name must be PascalCase (e.g., Cancel, ExportCSV)"}
+	}
+
+	for _, f := range append(slices.Clone(c.QueryFields), c.BodyFields...) {
+		if !paramPattern.MatchString(f.Name) {
+			return &errs.ValidationError{Field: "field", Message: "field " + f.Name + " must be snake_case (e.g., reason, order_id)"}
+		}
+		if _, ok := EndpointFieldTypes[f.Type]; !ok {
+			return &errs.ValidationError{Field: "field", Message: "field " + f.Name + " has unsupported type " + f.Type, Hint: "use string, int, int32, int64, float, bool or time"}
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, "go.mod")); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "go.mod not found in %s", c.ProjectPath)
+	}
+	if c.ModuleName == "" {
+		return errs.Validation("add a module directive to go.mod", "module path not found in %s", filepath.Join(c.ProjectPath, "go.mod"))
+	}
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, filepath.FromSlash(c.Layout.Handlers))); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "%s directory not found - is this a ready-go project?", c.Layout.Handlers)
+	}
+
+	return nil
+}
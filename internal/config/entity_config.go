@@ -5,32 +5,102 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
 )
 
+const projectRootHint = "run this command from the root of a ready-go project, or run `ready-go init` to adopt an existing service"
+
 // EntityConfig holds configuration for generating a new entity
 type EntityConfig struct {
 	EntityName      string // PascalCase: "Product"
 	EntityNameLower string // lowercase: "product"
 	TableName       string // plural: "products"
 	ProjectPath     string // current working directory
+	ModuleName      string // Go module path, from the manifest or go.mod
+	Engine          string // from the project manifest
+	Layout          Layout // from the project manifest
+	// Versioning numbers new migrations; empty follows the existing files
+	Versioning schema.Versioning
+	Router     Router // from the project manifest
+	// BelongsTo lists parent entities, each getting a <parent>_id foreign key
+	BelongsTo []string
+	// ManyToMany lists entities linked through a join table
+	ManyToMany []string
+	// SoftDelete adds deleted_at: Delete sets it, and reads skip deleted rows
+	SoftDelete bool
+	// Audit adds created_by and updated_by, filled from the request's actor
+	Audit bool
+	// Versioned adds a version column checked by Update (optimistic locking)
+	Versioned bool
+	// Cache adds a Get handler reading through Redis, invalidated by Update and Delete
+	Cache bool
 }
 
 // NewEntityConfig creates a new EntityConfig with the given entity name
 func NewEntityConfig(entityName string) *EntityConfig {
 	return &EntityConfig{
 		EntityName: entityName,
+		Layout:     DefaultLayout(),
+	}
+}
+
+// NewTableEntityConfig creates an EntityConfig for an existing table. The
+// entity name defaults to the singular of the table name: order_items → OrderItem.
+func NewTableEntityConfig(tableName, entityName string) *EntityConfig {
+	if entityName == "" {
+		entityName = schema.GoName(Singularize(tableName))
 	}
+	cfg := NewEntityConfig(entityName)
+	cfg.TableName = tableName
+	return cfg
+}
+
+// ApplyManifest takes the project layout, engine, module, router and migration versioning from the manifest in ProjectPath, if there is one
+func (c *EntityConfig) ApplyManifest() error {
+	m, _, err := LoadManifest(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	c.Layout = m.Layout
+	c.Versioning = m.Migrations.Versioning
+	c.Engine = m.Engine
+	c.Router = m.Router
+	c.ModuleName = m.Module
+	if c.ModuleName == "" {
+		// Projects without a manifest; a missing go.mod is reported by Validate
+		c.ModuleName = readModulePath(c.ProjectPath)
+	}
+	return nil
+}
+
+// readModulePath returns the module path declared in dir/go.mod, or "" if there is none
+func readModulePath(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
 }
 
 // Process calculates derived fields from the configuration
 func (c *EntityConfig) Process() {
 	// Ensure first letter is uppercase (PascalCase)
-	if len(c.EntityName) > 0 {
-		c.EntityName = strings.ToUpper(c.EntityName[:1]) + c.EntityName[1:]
-	}
+	c.EntityName = upperFirst(c.EntityName)
+	c.BelongsTo = relationNames(c.BelongsTo)
+	c.ManyToMany = relationNames(c.ManyToMany)
 	c.EntityNameLower = strings.ToLower(c.EntityName)
-	c.TableName = pluralize(c.EntityNameLower)
+	if c.TableName == "" {
+		c.TableName = pluralize(c.EntityNameLower)
+	}
 
 	if c.ProjectPath == "" {
 		c.ProjectPath, _ = os.Getwd()
@@ -41,44 +111,94 @@ func (c *EntityConfig) Process() {
 func (c *EntityConfig) Validate() error {
 	// Check entity name is provided
 	if c.EntityName == "" {
-		return fmt.Errorf("entity name cannot be empty")
+		return &errs.ValidationError{Field: "name", Message: "entity name cannot be empty", Hint: "pass a name, e.g. ready-go add entity Product"}
 	}
 
 	// Check PascalCase (starts with uppercase, alphanumeric only)
 	if !regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`).MatchString(c.EntityName) {
-		return fmt.Errorf("entity name must be PascalCase (e.g., Product, OrderItem)")
+		return &errs.ValidationError{Field: "name", Message: "entity name must be PascalCase (e.g., Product, OrderItem)"}
+	}
+
+	// Check related entities
+	for _, rel := range []struct {
+		field string
+		names []string
+	}{{"belongs-to", c.BelongsTo}, {"many-to-many", c.ManyToMany}} {
+		for _, name := range rel.names {
+			if !regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`).MatchString(name) {
+				return &errs.ValidationError{Field: rel.field, Message: fmt.Sprintf("related entity %q must be PascalCase (e.g., Order, Tag)", name)}
+			}
+			if name == c.EntityName {
+				return &errs.ValidationError{Field: rel.field, Message: fmt.Sprintf("%s cannot be related to itself", name), Hint: "add a parent_id column with ready-go add migration instead"}
+			}
+		}
+	}
+	for _, name := range c.BelongsTo {
+		if slices.Contains(c.ManyToMany, name) {
+			return &errs.ValidationError{Field: "many-to-many", Message: fmt.Sprintf("%s is given both as --belongs-to and --many-to-many", name)}
+		}
 	}
 
 	// Check project structure exists
 	if _, err := os.Stat(filepath.Join(c.ProjectPath, "go.mod")); os.IsNotExist(err) {
-		return fmt.Errorf("go.mod not found - run this command from a Go project root")
+		return errs.Validation(projectRootHint, "go.mod not found in %s", c.ProjectPath)
 	}
 
-	// Check database directories exist (simplified structure)
-	migrationsDir := filepath.Join(c.ProjectPath, "database", "migrations")
-	if _, err := os.Stat(migrationsDir); os.IsNotExist(err) {
-		return fmt.Errorf("database/migrations directory not found - is this a ready-go project?")
+	// Check database directories exist
+	for _, dir := range []string{c.Layout.Migrations, c.Layout.Queries} {
+		if _, err := os.Stat(filepath.Join(c.ProjectPath, filepath.FromSlash(dir))); os.IsNotExist(err) {
+			return errs.Validation(projectRootHint, "%s directory not found - is this a ready-go project?", dir)
+		}
 	}
 
-	queriesDir := filepath.Join(c.ProjectPath, "database", "queries")
-	if _, err := os.Stat(queriesDir); os.IsNotExist(err) {
-		return fmt.Errorf("database/queries directory not found - is this a ready-go project?")
+	return nil
+}
+
+// ValidateEngine checks that add entity can write the project's SQL: its
+// migration and queries use MySQL's AUTO_INCREMENT, ENUM, INSERT IGNORE, NOW()
+// and ? placeholders. Projects without an engine are MySQL.
+func (c *EntityConfig) ValidateEngine() error {
+	if c.Engine != "" && c.Engine != "mysql" {
+		return &errs.ValidationError{
+			Field:   "engine",
+			Message: fmt.Sprintf("add entity only supports mysql projects, not %s", c.Engine),
+			Hint:    "write the table with ready-go add migration, then generate its struct with ready-go gen entity",
+		}
 	}
+	return nil
+}
 
-	// Check entity file doesn't already exist
-	entityFile := filepath.Join(c.ProjectPath, "internal", "entity", c.EntityNameLower+".go")
-	if _, err := os.Stat(entityFile); err == nil {
-		return fmt.Errorf("entity %s already exists at %s", c.EntityName, entityFile)
+// NestedPath returns the route segment listing this entity under a parent:
+// OrderItem under order → "items", Comment under post → "comments"
+func (c *EntityConfig) NestedPath(parentLower string) string {
+	if rest := strings.TrimPrefix(c.EntityNameLower, parentLower); rest != c.EntityNameLower && rest != "" {
+		return pluralize(rest)
 	}
+	return c.TableName
+}
 
-	return nil
+// WriteHandlers reports whether the entity gets create, update and delete
+// handlers, which the soft delete, audit, versioning and cache options need
+func (c *EntityConfig) WriteHandlers() bool {
+	return c.SoftDelete || c.Audit || c.Versioned || c.Cache
+}
+
+// upperFirst upper-cases the first letter of name
+func upperFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
 }
 
-// TemplateData returns a map compatible with existing templates
-func (c *EntityConfig) TemplateData() map[string]any {
-	return map[string]any{
-		"EntityName":      c.EntityName,
-		"EntityNameLower": c.EntityNameLower,
-		"TableName":       c.TableName,
+// relationNames trims, capitalises and dedupes related entity names
+func relationNames(names []string) []string {
+	var out []string
+	for _, name := range names {
+		name = upperFirst(strings.TrimSpace(name))
+		if name != "" && !slices.Contains(out, name) {
+			out = append(out, name)
+		}
 	}
+	return out
 }
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+)
+
+// FromOpenAPIConfig holds configuration for generating handlers from an
+// OpenAPI document
+type FromOpenAPIConfig struct {
+	Spec        string // path of the OpenAPI document
+	ProjectPath string // current working directory
+	ModuleName  string // Go module path, from the manifest or go.mod
+	Layout      Layout // from the project manifest
+	Router      Router // from the project manifest
+}
+
+// NewFromOpenAPIConfig creates a new FromOpenAPIConfig reading spec
+func NewFromOpenAPIConfig(spec string) *FromOpenAPIConfig {
+	m := DefaultManifest("")
+	return &FromOpenAPIConfig{
+		Spec:   spec,
+		Layout: m.Layout,
+		Router: m.Router,
+	}
+}
+
+// ApplyManifest takes the layout, module and router from the manifest in ProjectPath, if there is one
+func (c *FromOpenAPIConfig) ApplyManifest() error {
+	m, _, err := LoadManifest(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	c.Layout = m.Layout
+	c.Router = m.Router
+	c.ModuleName = m.Module
+	if c.ModuleName == "" {
+		c.ModuleName = readModulePath(c.ProjectPath)
+	}
+	return nil
+}
+
+// Process defaults the project path
+func (c *FromOpenAPIConfig) Process() {
+	if c.ProjectPath == "" {
+		c.ProjectPath, _ = os.Getwd()
+	}
+}
+
+// Validate checks that the document exists and the project has a handlers directory
+func (c *FromOpenAPIConfig) Validate() error {
+	if c.Spec == "" {
+		return &errs.ValidationError{Field: "spec", Message: "OpenAPI document is required", Hint: "usage: ready-go gen from-openapi api.yaml"}
+	}
+	if info, err := os.Stat(c.Spec); err != nil || info.IsDir() {
+		return &errs.ValidationError{Field: "spec", Message: "OpenAPI document " + c.Spec + " not found"}
+	}
+
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, "go.mod")); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "go.mod not found in %s", c.ProjectPath)
+	}
+	if c.ModuleName == "" {
+		return errs.Validation("add a module directive to go.mod", "module path not found in %s", filepath.Join(c.ProjectPath, "go.mod"))
+	}
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, filepath.FromSlash(c.Layout.Handlers))); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "%s directory not found - is this a ready-go project?", c.Layout.Handlers)
+	}
+
+	return nil
+}
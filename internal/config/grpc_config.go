@@ -0,0 +1,92 @@
+package config
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+)
+
+// GRPCServiceConfig holds configuration for adding a gRPC service
+type GRPCServiceConfig struct {
+	ServiceName string // PascalCase, without the Service suffix: "Orders"
+	Package     string // proto and Go package name: "orders"
+	Resource    string // singular of ServiceName: "Order"
+	ProjectPath string // current working directory
+	ModuleName  string // Go module path, from the manifest or go.mod
+	Layout      Layout // from the project manifest
+	// GRPC is from the project manifest; nil when the project has no gRPC
+	// server yet, which the first service then adds
+	GRPC *GRPC
+	// GRPCPort is the default GRPC_PORT when the gRPC server is added
+	GRPCPort string
+	// Manifest is the project manifest, updated when the gRPC server is added
+	Manifest *Manifest
+}
+
+// NewGRPCServiceConfig creates a new GRPCServiceConfig for the service name
+func NewGRPCServiceConfig(serviceName string) *GRPCServiceConfig {
+	return &GRPCServiceConfig{
+		ServiceName: serviceName,
+		Layout:      DefaultLayout(),
+		GRPCPort:    "9090",
+	}
+}
+
+// ApplyManifest takes the layout, module and gRPC settings from the manifest in ProjectPath, if there is one
+func (c *GRPCServiceConfig) ApplyManifest() error {
+	m, _, err := LoadManifest(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	c.Manifest = m
+	c.Layout = m.Layout
+	c.GRPC = m.GRPC
+	c.ModuleName = m.Module
+	if c.ModuleName == "" {
+		c.ModuleName = readModulePath(c.ProjectPath)
+	}
+	return nil
+}
+
+// Process calculates derived fields from the configuration
+func (c *GRPCServiceConfig) Process() {
+	c.ServiceName = upperFirst(strings.TrimSpace(c.ServiceName))
+	if name := strings.TrimSuffix(c.ServiceName, "Service"); name != "" {
+		c.ServiceName = name
+	}
+	c.Package = strings.ToLower(c.ServiceName)
+	c.Resource = Singularize(c.ServiceName)
+
+	if c.ProjectPath == "" {
+		c.ProjectPath, _ = os.Getwd()
+	}
+}
+
+// Validate checks the service name and that the project exists
+func (c *GRPCServiceConfig) Validate() error {
+	if c.ServiceName == "" {
+		return &errs.ValidationError{Field: "name", Message: "service name cannot be empty", Hint: "pass a name, e.g. ready-go add grpc-service Orders"}
+	}
+	if !regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`).MatchString(c.ServiceName) {
+		return &errs.ValidationError{Field: "name", Message: "service name must be PascalCase (e.g., Orders, Payments)"}
+	}
+	if token.IsKeyword(c.Package) || c.Package == "cmd" {
+		return &errs.ValidationError{Field: "name", Message: "service name " + c.ServiceName + " makes the reserved package name " + c.Package, Hint: "choose a different name, e.g. " + c.ServiceName + "s"}
+	}
+
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, "go.mod")); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "go.mod not found in %s", c.ProjectPath)
+	}
+	if c.ModuleName == "" {
+		return errs.Validation("add a module directive to go.mod", "module path not found in %s", filepath.Join(c.ProjectPath, "go.mod"))
+	}
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, "cmd", "service.go")); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "cmd/service.go not found - gRPC services share the APIService of a ready-go project")
+	}
+
+	return nil
+}
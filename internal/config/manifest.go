@@ -0,0 +1,315 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the project manifest written at the project root by `new` and `init`
+const ManifestFile = "ready-go.yaml"
+
+// ManifestVersion is the manifest schema version written by this release
+const ManifestVersion = 1
+
+// Manifest records how a project is laid out so that `add` commands work on
+// projects that were not scaffolded with the default structure
+type Manifest struct {
+	Version    int        `yaml:"version"`
+	Module     string     `yaml:"module"`
+	Engine     string     `yaml:"engine"`
+	Layout     Layout     `yaml:"layout"`
+	Migrations Migrations `yaml:"migrations"`
+	Router     Router     `yaml:"router"`
+	// GRPC is nil until the project serves gRPC
+	GRPC *GRPC `yaml:"grpc,omitempty"`
+}
+
+// Layout holds project-relative, slash-separated directory paths. Directories
+// holding Go packages use their last element as the package name.
+type Layout struct {
+	Migrations string `yaml:"migrations"`
+	Queries    string `yaml:"queries"`
+	Entity     string `yaml:"entity"`
+	Handlers   string `yaml:"handlers"`
+	Models     string `yaml:"models"`
+	// API is the directory of the HTTP server's main package
+	API string `yaml:"api"`
+}
+
+// Migrations holds settings shared by every migration generator
+type Migrations struct {
+	// Versioning is sequential or timestamp. When empty, new migrations
+	// follow the numbering of the latest existing one.
+	Versioning schema.Versioning `yaml:"versioning,omitempty"`
+}
+
+// Validate checks the versioning scheme
+func (m Migrations) Validate() error {
+	if m.Versioning != "" && !slices.Contains(schema.Versionings, m.Versioning) {
+		return &errs.ValidationError{
+			Field:   "versioning",
+			Message: fmt.Sprintf("unknown migration versioning %q", m.Versioning),
+			Hint:    "use sequential or timestamp",
+		}
+	}
+	return nil
+}
+
+// Router frameworks a project can be scaffolded with
+const (
+	FrameworkFiber  = "fiber"
+	FrameworkEcho   = "echo"
+	FrameworkChi    = "chi"
+	FrameworkStdlib = "stdlib"
+)
+
+// Frameworks lists the supported router frameworks; stdlib is the Go 1.22
+// net/http ServeMux
+var Frameworks = []string{FrameworkFiber, FrameworkEcho, FrameworkChi, FrameworkStdlib}
+
+// Router describes where HTTP routes are registered
+type Router struct {
+	Framework string `yaml:"framework"`
+	// Setup is the file containing the route setup function
+	Setup string `yaml:"setup"`
+	// Func is the name of the route setup function
+	Func string `yaml:"func"`
+}
+
+// GRPC describes where a project keeps its protobuf definitions and registers
+// its gRPC services
+type GRPC struct {
+	// Proto is the buf module holding the .proto files
+	Proto string `yaml:"proto"`
+	// Gen is where buf writes the generated Go code
+	Gen string `yaml:"gen"`
+	// Server is the package directory of the gRPC server
+	Server string `yaml:"server"`
+	// Setup is the file containing the service registration function
+	Setup string `yaml:"setup"`
+	// Func is the name of the service registration function
+	Func string `yaml:"func"`
+}
+
+// DefaultGRPC returns the gRPC settings scaffolded by `new --transport http,grpc`
+func DefaultGRPC() *GRPC {
+	return &GRPC{
+		Proto:  "proto",
+		Gen:    "gen/proto",
+		Server: "internal/grpcserver",
+		Setup:  "internal/grpcserver/services.go",
+		Func:   "RegisterServices",
+	}
+}
+
+// ServerPackage returns the package name of the gRPC server directory
+func (g *GRPC) ServerPackage() string {
+	return path.Base(g.Server)
+}
+
+// Validate checks that every path is a clean relative path inside the project
+func (g *GRPC) Validate() error {
+	for _, dir := range []struct {
+		field, value string
+		goPackage    bool
+	}{
+		{"grpc.proto", g.Proto, false},
+		{"grpc.gen", g.Gen, false},
+		{"grpc.server", g.Server, true},
+		{"grpc.setup", g.Setup, false},
+	} {
+		if err := validateDir(dir.field, dir.value, dir.goPackage); err != nil {
+			return err
+		}
+	}
+	if !token.IsIdentifier(g.Func) {
+		return &errs.ValidationError{Field: "grpc.func", Message: fmt.Sprintf("grpc.func %q is not a Go function name", g.Func)}
+	}
+	return nil
+}
+
+// DefaultLayout returns the layout scaffolded by `ready-go new`
+func DefaultLayout() Layout {
+	return Layout{
+		Migrations: "database/migrations",
+		Queries:    "database/queries",
+		Entity:     "internal/entity",
+		Handlers:   "internal/handlers",
+		Models:     "internal/models",
+		API:        "cmd/api",
+	}
+}
+
+// EntityPackage returns the package name of the entity directory
+func (l Layout) EntityPackage() string {
+	return path.Base(l.Entity)
+}
+
+// HandlersPackage returns the package name of the handlers directory
+func (l Layout) HandlersPackage() string {
+	return path.Base(l.Handlers)
+}
+
+// ModelsPackage returns the package name of the sqlc models directory
+func (l Layout) ModelsPackage() string {
+	return path.Base(l.Models)
+}
+
+var packageName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// MigrateCmd returns the directory of the generated migrate command, a
+// sibling of the API command: cmd/api → cmd/migrate
+func (l Layout) MigrateCmd() string {
+	return path.Join(path.Dir(l.API), "migrate")
+}
+
+// GRPCCmd returns the directory of the gRPC server command: cmd/api → cmd/grpc
+func (l Layout) GRPCCmd() string {
+	return path.Join(path.Dir(l.API), "grpc")
+}
+
+// SeedCmd returns the directory of the generated seed command: cmd/api → cmd/seed
+func (l Layout) SeedCmd() string {
+	return path.Join(path.Dir(l.API), "seed")
+}
+
+// Seeds returns the seeds package directory, a sibling of the migrations:
+// database/migrations → database/seeds
+func (l Layout) Seeds() string {
+	return path.Join(path.Dir(l.Migrations), "seeds")
+}
+
+// Cache returns the cache package directory, a sibling of the handlers:
+// internal/handlers → internal/cache
+func (l Layout) Cache() string {
+	return path.Join(path.Dir(l.Handlers), "cache")
+}
+
+// Validate checks that every path is a clean relative path inside the project,
+// and that Go package directories end in a valid package name
+func (l Layout) Validate() error {
+	dirs := []struct {
+		field, value string
+		goPackage    bool
+	}{
+		{"migrations", l.Migrations, false},
+		{"queries", l.Queries, false},
+		{"entity", l.Entity, true},
+		{"handlers", l.Handlers, true},
+		{"models", l.Models, true},
+		{"api", l.API, false},
+	}
+
+	for _, dir := range dirs {
+		if err := validateDir(dir.field, dir.value, dir.goPackage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateDir checks that value is a clean relative path inside the project,
+// ending in a valid package name when it holds a Go package
+func validateDir(field, value string, goPackage bool) error {
+	if value == "" {
+		return &errs.ValidationError{Field: field, Message: fmt.Sprintf("layout path %q cannot be empty", field)}
+	}
+	if path.IsAbs(value) || path.Clean(value) != value || value == "." || strings.HasPrefix(value, "../") || value == ".." {
+		return &errs.ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("layout path %s=%q must be a clean path relative to the project root", field, value),
+			Hint:    "use forward slashes and no leading ./ or ../, e.g. db/migrations",
+		}
+	}
+	if goPackage && !packageName.MatchString(path.Base(value)) {
+		return &errs.ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("layout path %s=%q must end in a valid Go package name", field, value),
+			Hint:    "use a lowercase final element, e.g. pkg/handlers",
+		}
+	}
+	return nil
+}
+
+// DefaultManifest returns the manifest of a freshly scaffolded project
+func DefaultManifest(module string) *Manifest {
+	layout := DefaultLayout()
+	return &Manifest{
+		Version: ManifestVersion,
+		Module:  module,
+		Engine:  "mysql",
+		Layout:  layout,
+		Migrations: Migrations{
+			Versioning: schema.VersioningSequential,
+		},
+		Router: Router{
+			Framework: FrameworkFiber,
+			Setup:     layout.Handlers + "/handler.go",
+			Func:      "SetupHandler",
+		},
+	}
+}
+
+// LoadManifest reads the manifest in projectPath. Projects created before the
+// manifest existed get the default manifest, and found is false.
+func LoadManifest(projectPath string) (m *Manifest, found bool, err error) {
+	// Projects predating a setting keep their existing behaviour, so
+	// versioning follows the migration files unless the manifest sets it
+	m = DefaultManifest("")
+	m.Migrations.Versioning = ""
+
+	data, err := os.ReadFile(filepath.Join(projectPath, ManifestFile))
+	if os.IsNotExist(err) {
+		return m, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %w", ManifestFile, err)
+	}
+
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, false, errs.Validation("fix the YAML syntax in "+ManifestFile, "failed to parse %s: %v", ManifestFile, err)
+	}
+	if err := m.Layout.Validate(); err != nil {
+		return nil, false, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	if err := m.Migrations.Validate(); err != nil {
+		return nil, false, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	if m.GRPC != nil {
+		if err := m.GRPC.Validate(); err != nil {
+			return nil, false, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+		}
+	}
+
+	return m, true, nil
+}
+
+// Marshal encodes the manifest as YAML
+func (m *Manifest) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# ready-go project manifest. `ready-go add` commands read paths from here.\n")
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", ManifestFile, err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", ManifestFile, err)
+	}
+
+	return buf.Bytes(), nil
+}
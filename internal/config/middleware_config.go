@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+)
+
+// MiddlewareConfig holds configuration for adding middleware to a project
+type MiddlewareConfig struct {
+	Names       []string // from Middlewares: "request-id", "cors"
+	ProjectPath string   // current working directory
+	ModuleName  string   // Go module path, from the manifest or go.mod
+	Layout      Layout   // from the project manifest
+	Router      Router   // from the project manifest
+}
+
+// Middlewares lists the middleware add middleware installs, in the order
+// requests pass through them: the request ID first, so everything after it
+// logs it, and the timeout last, so it covers the handler alone
+var Middlewares = []string{"request-id", "recovery", "security-headers", "cors", "rate-limit", "gzip", "body-limit", "timeout"}
+
+// NewMiddlewareConfig creates a new MiddlewareConfig for names
+func NewMiddlewareConfig(names []string) *MiddlewareConfig {
+	m := DefaultManifest("")
+	return &MiddlewareConfig{
+		Names:  names,
+		Layout: m.Layout,
+		Router: m.Router,
+	}
+}
+
+// ApplyManifest takes the layout, module and router from the manifest in ProjectPath, if there is one
+func (c *MiddlewareConfig) ApplyManifest() error {
+	m, _, err := LoadManifest(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	c.Layout = m.Layout
+	c.Router = m.Router
+	c.ModuleName = m.Module
+	if c.ModuleName == "" {
+		c.ModuleName = readModulePath(c.ProjectPath)
+	}
+	return nil
+}
+
+// Process normalizes the names, accepting underscores (body_limit), drops
+// duplicates and sorts them in the order of Middlewares
+func (c *MiddlewareConfig) Process() {
+	var names []string
+	for _, name := range c.Names {
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-")
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	// Unknown names sort first, for Validate to report
+	slices.SortStableFunc(names, func(a, b string) int {
+		return slices.Index(Middlewares, a) - slices.Index(Middlewares, b)
+	})
+	c.Names = names
+
+	if c.ProjectPath == "" {
+		c.ProjectPath, _ = os.Getwd()
+	}
+}
+
+// Validate checks the names against Middlewares, and that the project has a handlers directory
+func (c *MiddlewareConfig) Validate() error {
+	if len(c.Names) == 0 {
+		return &errs.ValidationError{Field: "name", Message: "middleware name is required", Hint: "use one or more of " + strings.Join(Middlewares, ", ")}
+	}
+	for _, name := range c.Names {
+		if !slices.Contains(Middlewares, name) {
+			return &errs.ValidationError{Field: "name", Message: "unknown middleware " + name, Hint: "use one or more of " + strings.Join(Middlewares, ", ")}
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, "go.mod")); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "go.mod not found in %s", c.ProjectPath)
+	}
+	if c.ModuleName == "" {
+		return errs.Validation("add a module directive to go.mod", "module path not found in %s", filepath.Join(c.ProjectPath, "go.mod"))
+	}
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, filepath.FromSlash(c.Layout.Handlers))); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "%s directory not found - is this a ready-go project?", c.Layout.Handlers)
+	}
+
+	return nil
+}
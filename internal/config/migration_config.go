@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// MigrationConfig holds configuration for generating a standalone migration
+type MigrationConfig struct {
+	Name        string // snake_case: "add_sku_to_products"
+	ProjectPath string // current working directory
+	Engine      string // from the project manifest
+	Layout      Layout // from the project manifest
+	// Versioning numbers the migration; empty follows the existing files
+	Versioning schema.Versioning
+
+	// Changes as given on the command line, e.g. "products.sku:string:unique"
+	AddColumns  []string
+	AddIndexes  []string
+	DropColumns []string
+}
+
+// NewMigrationConfig creates a new MigrationConfig with the given migration name
+func NewMigrationConfig(name string) *MigrationConfig {
+	m := DefaultManifest("")
+	return &MigrationConfig{
+		Name:   name,
+		Engine: m.Engine,
+		Layout: m.Layout,
+	}
+}
+
+// ApplyManifest takes the engine, layout and migration versioning from the manifest in ProjectPath, if there is one
+func (c *MigrationConfig) ApplyManifest() error {
+	m, _, err := LoadManifest(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	c.Engine = m.Engine
+	c.Layout = m.Layout
+	c.Versioning = m.Migrations.Versioning
+	return nil
+}
+
+// Process calculates derived fields from the configuration
+func (c *MigrationConfig) Process() {
+	if c.ProjectPath == "" {
+		c.ProjectPath, _ = os.Getwd()
+	}
+}
+
+// Validate checks if the configuration is valid and the migrations directory exists
+func (c *MigrationConfig) Validate() error {
+	if c.Name == "" {
+		return &errs.ValidationError{Field: "name", Message: "migration name cannot be empty", Hint: "pass a name, e.g. ready-go add migration add_sku_to_products"}
+	}
+
+	if !regexp.MustCompile(`^[a-z][a-z0-9_]*$`).MatchString(c.Name) {
+		return &errs.ValidationError{Field: "name", Message: "migration name must be snake_case (e.g., add_sku_to_products)"}
+	}
+
+	return c.ValidateProject()
+}
+
+// ValidateProject checks the engine and that the migrations directory exists.
+// Commands that work on existing migrations need no name.
+func (c *MigrationConfig) ValidateProject() error {
+	switch c.Engine {
+	case "mysql", "postgresql", "sqlite":
+	default:
+		return errs.Validation("set engine to mysql, postgresql or sqlite in "+ManifestFile, "unsupported engine %q", c.Engine)
+	}
+
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, "go.mod")); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "go.mod not found in %s", c.ProjectPath)
+	}
+
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, filepath.FromSlash(c.Layout.Migrations))); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "%s directory not found - is this a ready-go project?", c.Layout.Migrations)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+)
+
+// DefaultOpenAPIOutput is where `gen openapi` writes the document
+const DefaultOpenAPIOutput = "api/openapi.yaml"
+
+// OpenAPIConfig holds configuration for generating an OpenAPI document from
+// the routes of a project
+type OpenAPIConfig struct {
+	ProjectPath string // current working directory
+	ModuleName  string // Go module path, from the manifest or go.mod
+	Layout      Layout // from the project manifest
+	Router      Router // from the project manifest
+	// Output is the project-relative, slash-separated path of the document
+	Output string
+	// Docs embeds the document and serves it with Swagger UI at /docs
+	Docs bool
+}
+
+// NewOpenAPIConfig creates a new OpenAPIConfig writing to output
+func NewOpenAPIConfig(output string, docs bool) *OpenAPIConfig {
+	m := DefaultManifest("")
+	return &OpenAPIConfig{
+		Layout: m.Layout,
+		Router: m.Router,
+		Output: output,
+		Docs:   docs,
+	}
+}
+
+// ApplyManifest takes the layout, module and router from the manifest in ProjectPath, if there is one
+func (c *OpenAPIConfig) ApplyManifest() error {
+	m, _, err := LoadManifest(c.ProjectPath)
+	if err != nil {
+		return err
+	}
+	c.Layout = m.Layout
+	c.Router = m.Router
+	c.ModuleName = m.Module
+	if c.ModuleName == "" {
+		c.ModuleName = readModulePath(c.ProjectPath)
+	}
+	return nil
+}
+
+// Process defaults the output path and normalizes it to slashes
+func (c *OpenAPIConfig) Process() {
+	c.Output = strings.TrimSpace(c.Output)
+	if c.Output == "" {
+		c.Output = DefaultOpenAPIOutput
+	}
+	c.Output = path.Clean(filepath.ToSlash(c.Output))
+
+	if c.ProjectPath == "" {
+		c.ProjectPath, _ = os.Getwd()
+	}
+}
+
+// Validate checks the output path and that the project has handlers to scan
+func (c *OpenAPIConfig) Validate() error {
+	ext := path.Ext(c.Output)
+	if ext != ".yaml" && ext != ".yml" {
+		return &errs.ValidationError{Field: "out", Message: "output must be a .yaml or .yml file", Hint: "e.g. --out " + DefaultOpenAPIOutput}
+	}
+	if path.IsAbs(c.Output) || filepath.IsAbs(c.Output) || c.Output == ".." || strings.HasPrefix(c.Output, "../") {
+		return &errs.ValidationError{Field: "out", Message: "output must be inside the project", Hint: "pass a path relative to the project root, e.g. --out " + DefaultOpenAPIOutput}
+	}
+	if dir := path.Dir(c.Output); c.Docs && (dir == "." || !token.IsIdentifier(path.Base(dir))) {
+		return &errs.ValidationError{Field: "out", Message: "--docs needs the document in a directory named like a Go package, which embeds it", Hint: "e.g. --out " + DefaultOpenAPIOutput}
+	}
+
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, "go.mod")); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "go.mod not found in %s", c.ProjectPath)
+	}
+	if c.ModuleName == "" {
+		return errs.Validation("add a module directive to go.mod", "module path not found in %s", filepath.Join(c.ProjectPath, "go.mod"))
+	}
+	if _, err := os.Stat(filepath.Join(c.ProjectPath, filepath.FromSlash(c.Layout.Handlers))); os.IsNotExist(err) {
+		return errs.Validation(projectRootHint, "%s directory not found - is this a ready-go project?", c.Layout.Handlers)
+	}
+
+	return nil
+}
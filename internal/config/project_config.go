@@ -3,7 +3,17 @@ package config
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// Transports a project can serve
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
 )
 
 // ProjectConfig holds all configuration for generating a new project
@@ -20,51 +30,112 @@ type ProjectConfig struct {
 	DBPort             string
 	RedisPort          string
 	KafkaPort          string
+	GRPCPort           string
+	Engine             string
+	Layout             Layout
+	Router             Router
+	Versioning         schema.Versioning
+	// EmbeddedMigrations is true when the project has the generated migrate
+	// command; adopted projects run migrations with the goose CLI instead
+	EmbeddedMigrations bool
+	// Transports lists the servers the project runs: http, and optionally grpc
+	Transports []string
+	// GRPC is set by Process when Transports includes grpc
+	GRPC *GRPC
 }
 
 // NewProjectConfig creates a new ProjectConfig with default values
 func NewProjectConfig(projectName string) *ProjectConfig {
 	return &ProjectConfig{
-		ProjectName:   projectName,
-		ModuleName:    fmt.Sprintf("github.com/username/%s", projectName),
-		GoVersion:     "1.21",
-		OutputDir:     ".",
-		SampleAPIName: "User",
-		ServerPort:    "8080",
-		DBPort:        "3306",
-		RedisPort:     "6379",
-		KafkaPort:     "9092",
+		ProjectName:        projectName,
+		ModuleName:         fmt.Sprintf("github.com/username/%s", projectName),
+		GoVersion:          "1.21",
+		OutputDir:          ".",
+		SampleAPIName:      "User",
+		ServerPort:         "8080",
+		DBPort:             "3306",
+		RedisPort:          "6379",
+		KafkaPort:          "9092",
+		GRPCPort:           "9090",
+		Transports:         []string{TransportHTTP},
+		Engine:             "mysql",
+		Layout:             DefaultLayout(),
+		Router:             Router{Framework: FrameworkFiber},
+		Versioning:         schema.VersioningSequential,
+		EmbeddedMigrations: true,
 	}
 }
 
 // Validate checks if the configuration is valid
 func (c *ProjectConfig) Validate() error {
 	if c.ProjectName == "" {
-		return fmt.Errorf("project name cannot be empty")
+		return &errs.ValidationError{Field: "name", Message: "project name cannot be empty"}
 	}
 
 	matched, _ := regexp.MatchString(`^[a-zA-Z0-9_-]+$`, c.ProjectName)
 	if !matched {
-		return fmt.Errorf("project name can only contain letters, numbers, hyphens, and underscores")
+		return &errs.ValidationError{Field: "name", Message: "project name can only contain letters, numbers, hyphens, and underscores"}
 	}
 
 	if c.ModuleName == "" {
-		return fmt.Errorf("module name cannot be empty")
+		return &errs.ValidationError{Field: "module", Message: "module name cannot be empty", Hint: "pass --module, e.g. --module github.com/acme/my-api"}
 	}
 
 	if c.SampleAPIName == "" {
-		return fmt.Errorf("sample API name cannot be empty")
+		return &errs.ValidationError{Field: "sample-name", Message: "sample API name cannot be empty"}
+	}
+
+	if !slices.Contains(Frameworks, c.Router.Framework) {
+		return &errs.ValidationError{Field: "router", Message: fmt.Sprintf("unknown router %q", c.Router.Framework), Hint: "use " + strings.Join(Frameworks, ", ")}
+	}
+
+	for _, t := range c.Transports {
+		if t != TransportHTTP && t != TransportGRPC {
+			return &errs.ValidationError{Field: "transport", Message: fmt.Sprintf("unknown transport %q", t), Hint: "use http or http,grpc"}
+		}
+	}
+	if !slices.Contains(c.Transports, TransportHTTP) {
+		return &errs.ValidationError{Field: "transport", Message: "projects always serve HTTP", Hint: "use --transport http,grpc to add a gRPC server"}
+	}
+
+	if err := c.Layout.Validate(); err != nil {
+		return err
+	}
+
+	if err := (Migrations{Versioning: c.Versioning}).Validate(); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// Manifest returns the project manifest describing this configuration
+func (c *ProjectConfig) Manifest() *Manifest {
+	m := DefaultManifest(c.ModuleName)
+	m.Engine = c.Engine
+	m.Layout = c.Layout
+	m.Migrations.Versioning = c.Versioning
+	m.Router.Framework = c.Router.Framework
+	m.Router.Setup = c.Layout.Handlers + "/handler.go"
+	m.GRPC = c.GRPC
+	return m
+}
+
 // Process calculates derived fields from the configuration
 func (c *ProjectConfig) Process() {
 	c.SampleAPIName = strings.Title(c.SampleAPIName)
 	c.SampleAPINameLower = strings.ToLower(c.SampleAPIName)
 	c.SampleAPINameUpper = strings.ToUpper(c.SampleAPIName)
 	c.SampleTableName = pluralize(c.SampleAPINameLower)
+
+	c.Router.Framework = strings.ToLower(strings.TrimSpace(c.Router.Framework))
+
+	for i, t := range c.Transports {
+		c.Transports[i] = strings.ToLower(strings.TrimSpace(t))
+	}
+	if slices.Contains(c.Transports, TransportGRPC) {
+		c.GRPC = DefaultGRPC()
+	}
 }
 
 func pluralize(word string) string {
@@ -76,3 +147,17 @@ func pluralize(word string) string {
 	}
 	return word + "s"
 }
+
+// Singularize reverses pluralize on the last word of a table name
+func Singularize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), !strings.HasSuffix(word, "s"):
+		return word
+	default:
+		return strings.TrimSuffix(word, "s")
+	}
+}
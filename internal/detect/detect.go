@@ -0,0 +1,471 @@
+// Package detect inspects an existing Go service to work out how it maps onto
+// ready-go conventions: module path, database engine, migration and query
+// directories, and which router (Fiber, Echo, chi or net/http) is set up where.
+package detect
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Piece is an optional part of a ready-go project that `init` can add
+type Piece string
+
+const (
+	PieceMakefile Piece = "makefile"
+	PieceSQLC     Piece = "sqlc"
+	PieceUtil     Piece = "util"
+)
+
+// Pieces lists every piece `init` knows how to add
+var Pieces = []Piece{PieceMakefile, PieceSQLC, PieceUtil}
+
+// MakeTargets are the Makefile targets ready-go projects rely on
+var MakeTargets = []string{"migrate-up", "migrate-down", "migrate-create", "sqlc-generate"}
+
+// Project is what Inspect found. Paths are relative to Dir and slash-separated;
+// empty strings mean "not found".
+type Project struct {
+	Dir       string
+	Module    string
+	GoVersion string
+
+	// Engine is the sqlc engine (mysql, postgresql or sqlite)
+	Engine string
+	// EngineSource explains where Engine came from, e.g. "sqlc.yaml"
+	EngineSource string
+	SQLCConfig   string
+
+	Migrations string
+	Queries    string
+	// Versioning is the numbering scheme of the migrations found, or "" when
+	// there are none or the schemes are mixed
+	Versioning schema.Versioning
+
+	// Router is the router framework: fiber, echo, chi or stdlib
+	Router string
+	// RouterSetup is the file declaring the function that registers routes
+	RouterSetup string
+	RouterFunc  string
+	// ServerMain is the file creating the router, e.g. calling fiber.New
+	ServerMain string
+
+	Makefile string
+	// MissingMakeTargets lists MakeTargets absent from the Makefile
+	MissingMakeTargets []string
+	// Util is the handlers util package directory, if present
+	Util string
+
+	// routers records where each framework's router was found while scanning
+	routers map[string]*routerMatch
+}
+
+// Missing returns the pieces that are absent and could be added
+func (p *Project) Missing() []Piece {
+	var missing []Piece
+	if len(p.MissingMakeTargets) > 0 {
+		missing = append(missing, PieceMakefile)
+	}
+	if p.SQLCConfig == "" {
+		missing = append(missing, PieceSQLC)
+	}
+	if p.Util == "" {
+		missing = append(missing, PieceUtil)
+	}
+	return missing
+}
+
+// HandlersDir returns the directory containing the router setup, if found
+func (p *Project) HandlersDir() string {
+	if p.RouterSetup == "" {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Dir(p.RouterSetup))
+}
+
+// Inspect examines the Go project rooted at dir
+func Inspect(dir string) (*Project, error) {
+	p := &Project{Dir: dir}
+
+	if err := p.readGoMod(); err != nil {
+		return nil, err
+	}
+	if err := p.readSQLCConfig(); err != nil {
+		return nil, err
+	}
+	if err := p.scan(); err != nil {
+		return nil, err
+	}
+	p.pickRouter()
+	if err := p.readMakefile(); err != nil {
+		return nil, err
+	}
+
+	if p.Migrations != "" {
+		migrations, err := schema.Migrations(filepath.Join(dir, filepath.FromSlash(p.Migrations)))
+		if err != nil {
+			return nil, err
+		}
+		p.Versioning = schema.DetectVersioning(migrations)
+	}
+
+	if handlers := p.HandlersDir(); handlers != "" && isDir(filepath.Join(dir, handlers, "util")) {
+		p.Util = handlers + "/util"
+	}
+
+	return p, nil
+}
+
+// readGoMod reads the module path and Go version, and guesses the engine from the SQL driver
+func (p *Project) readGoMod() error {
+	data, err := os.ReadFile(filepath.Join(p.Dir, "go.mod"))
+	if os.IsNotExist(err) {
+		return errs.Validation("run this command from the root of a Go module", "go.mod not found in %s", p.Dir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read go.mod: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			p.Module = strings.Trim(fields[1], `"`)
+		case "go":
+			p.GoVersion = fields[1]
+		}
+	}
+	if p.Module == "" {
+		return errs.Validation("", "go.mod in %s has no module directive", p.Dir)
+	}
+
+	drivers := []struct{ path, engine string }{
+		{"github.com/go-sql-driver/mysql", "mysql"},
+		{"github.com/jackc/pgx", "postgresql"},
+		{"github.com/lib/pq", "postgresql"},
+		{"github.com/mattn/go-sqlite3", "sqlite"},
+		{"modernc.org/sqlite", "sqlite"},
+	}
+	for _, driver := range drivers {
+		if bytes.Contains(data, []byte(driver.path)) {
+			p.Engine = driver.engine
+			p.EngineSource = "go.mod (" + driver.path + ")"
+			break
+		}
+	}
+
+	return nil
+}
+
+// sqlcPackage covers the parts of a sqlc.yaml entry (v1 "packages" or v2 "sql") that matter here
+type sqlcPackage struct {
+	Engine  string    `yaml:"engine"`
+	Schema  yaml.Node `yaml:"schema"`
+	Queries yaml.Node `yaml:"queries"`
+}
+
+type sqlcConfig struct {
+	SQL      []sqlcPackage `yaml:"sql"`
+	Packages []sqlcPackage `yaml:"packages"`
+}
+
+// readSQLCConfig takes the engine, schema and queries paths from sqlc.yaml when present
+func (p *Project) readSQLCConfig() error {
+	for _, name := range []string{"sqlc.yaml", "sqlc.yml", "sqlc.json"} {
+		data, err := os.ReadFile(filepath.Join(p.Dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		var cfg sqlcConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return errs.Validation("fix the sqlc configuration or remove it to let ready-go generate one", "failed to parse %s: %v", name, err)
+		}
+		p.SQLCConfig = name
+
+		packages := append(cfg.SQL, cfg.Packages...)
+		if len(packages) == 0 {
+			return nil
+		}
+		pkg := packages[0]
+
+		if pkg.Engine != "" {
+			p.Engine = pkg.Engine
+			p.EngineSource = name
+		}
+		if dir := firstPath(pkg.Schema); dir != "" && isDir(filepath.Join(p.Dir, dir)) {
+			p.Migrations = cleanRel(dir)
+		}
+		if dir := firstPath(pkg.Queries); dir != "" && isDir(filepath.Join(p.Dir, dir)) {
+			p.Queries = cleanRel(dir)
+		}
+		return nil
+	}
+	return nil
+}
+
+var (
+	gooseMarker = regexp.MustCompile(`(?m)^--\s*\+goose\s+Up`)
+	sqlcMarker  = regexp.MustCompile(`(?m)^--\s*name:\s*\w+\s+:\w+`)
+)
+
+// scan walks the source tree looking for migrations, queries and the router
+func (p *Project) scan() error {
+	fset := token.NewFileSet()
+
+	return filepath.WalkDir(p.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != p.Dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(p.Dir, path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case strings.HasSuffix(path, ".sql"):
+			return p.scanSQL(path, rel)
+		case strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go"):
+			p.scanGo(fset, path, rel)
+		}
+		return nil
+	})
+}
+
+// scanSQL classifies a SQL file as a goose migration or a sqlc query file
+func (p *Project) scanSQL(path, rel string) error {
+	if p.Migrations != "" && p.Queries != "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+
+	dir := filepath.ToSlash(filepath.Dir(rel))
+	if p.Migrations == "" && gooseMarker.Match(data) {
+		p.Migrations = dir
+	} else if p.Queries == "" && sqlcMarker.Match(data) {
+		p.Queries = dir
+	}
+	return nil
+}
+
+// routerKind describes how a router framework appears in source
+type routerKind struct {
+	framework string
+	imports   []string
+	// name is the package name the imports are used under by default
+	name string
+	// types are the router types a route setup function takes
+	types []string
+	// constructor creates the router, e.g. fiber.New
+	constructor string
+}
+
+// routerKinds in order of preference: services on any framework import
+// net/http, so it only counts when no other router is found
+var routerKinds = []routerKind{
+	{config.FrameworkFiber, []string{"github.com/gofiber/fiber/v3", "github.com/gofiber/fiber/v2"}, "fiber", []string{"App", "Router"}, "New"},
+	{config.FrameworkEcho, []string{"github.com/labstack/echo/v4"}, "echo", []string{"Echo", "Group"}, "New"},
+	{config.FrameworkChi, []string{"github.com/go-chi/chi/v5", "github.com/go-chi/chi"}, "chi", []string{"Router", "Mux"}, "NewRouter"},
+	{config.FrameworkStdlib, []string{"net/http"}, "http", []string{"ServeMux"}, "NewServeMux"},
+}
+
+// routerMatch is where a framework's route setup function and constructor call were found
+type routerMatch struct {
+	setup, fn, main string
+}
+
+// scanGo looks for route setup functions and router constructor calls.
+// Files that don't parse are ignored; the compiler will report them.
+func (p *Project) scanGo(fset *token.FileSet, path, rel string) {
+	file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return
+	}
+
+	for _, kind := range routerKinds {
+		name := importName(file, kind)
+		if name == "" {
+			continue
+		}
+		if p.routers == nil {
+			p.routers = map[string]*routerMatch{}
+		}
+		match := p.routers[kind.framework]
+		if match == nil {
+			match = &routerMatch{}
+			p.routers[kind.framework] = match
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+
+			// Prefer the conventional SetupHandler over any other function taking a router
+			if takesRouter(fn, name, kind.types) && (match.setup == "" || fn.Name.Name == "SetupHandler") {
+				match.setup = filepath.ToSlash(rel)
+				match.fn = fn.Name.Name
+			}
+
+			if match.main == "" && callsConstructor(fn, name, kind.constructor) {
+				match.main = filepath.ToSlash(rel)
+			}
+		}
+	}
+}
+
+// pickRouter settles on the first framework in routerKinds with a route
+// setup function or, failing that, the first whose router is created
+func (p *Project) pickRouter() {
+	pick := ""
+	for _, kind := range routerKinds {
+		match := p.routers[kind.framework]
+		if match == nil {
+			continue
+		}
+		if match.setup != "" {
+			pick = kind.framework
+			break
+		}
+		if match.main != "" && pick == "" {
+			pick = kind.framework
+		}
+	}
+	if match := p.routers[pick]; match != nil {
+		p.Router = pick
+		p.RouterSetup, p.RouterFunc, p.ServerMain = match.setup, match.fn, match.main
+	}
+}
+
+// importName returns the local name of the kind's package in file, or ""
+func importName(file *ast.File, kind routerKind) string {
+	for _, imp := range file.Imports {
+		path := strings.Trim(imp.Path.Value, `"`)
+		if !slices.Contains(kind.imports, path) {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return kind.name
+	}
+	return ""
+}
+
+// takesRouter reports whether fn has a parameter of one of the router types
+// of package pkg, e.g. *fiber.App or chi.Router
+func takesRouter(fn *ast.FuncDecl, pkg string, types []string) bool {
+	for _, field := range fn.Type.Params.List {
+		typ := field.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		sel, ok := typ.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == pkg && slices.Contains(types, sel.Sel.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// callsConstructor reports whether fn calls pkg.constructor, e.g. fiber.New
+func callsConstructor(fn *ast.FuncDecl, pkg, constructor string) bool {
+	found := false
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+				if x, ok := sel.X.(*ast.Ident); ok && x.Name == pkg && sel.Sel.Name == constructor {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+var makeTarget = regexp.MustCompile(`(?m)^([A-Za-z0-9_.-]+)\s*:[^=]`)
+
+// readMakefile records which of the ready-go Makefile targets are missing
+func (p *Project) readMakefile() error {
+	data, err := os.ReadFile(filepath.Join(p.Dir, "Makefile"))
+	if os.IsNotExist(err) {
+		p.MissingMakeTargets = slices.Clone(MakeTargets)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read Makefile: %w", err)
+	}
+	p.Makefile = "Makefile"
+
+	var defined []string
+	for _, match := range makeTarget.FindAllSubmatch(data, -1) {
+		defined = append(defined, string(match[1]))
+	}
+	for _, target := range MakeTargets {
+		if !slices.Contains(defined, target) {
+			p.MissingMakeTargets = append(p.MissingMakeTargets, target)
+		}
+	}
+	return nil
+}
+
+// firstPath returns the first path of a sqlc schema/queries value, which may be a string or a list
+func firstPath(node yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.SequenceNode:
+		if len(node.Content) > 0 {
+			return node.Content[0].Value
+		}
+	}
+	return ""
+}
+
+func cleanRel(path string) string {
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
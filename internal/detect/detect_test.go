@@ -0,0 +1,128 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeProject writes files, keyed by slash-separated path, into a temporary
+// directory with a go.mod and returns it
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/svc\n\ngo 1.23\n"
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestInspectRouter(t *testing.T) {
+	// A net/http handler, as chi and stdlib services have alongside their router
+	handler := `package user
+import "net/http"
+func Get(w http.ResponseWriter, r *http.Request) {}
+`
+	tests := []struct {
+		name                          string
+		files                         map[string]string
+		router, setup, fn, serverMain string
+	}{
+		{
+			name: "fiber",
+			files: map[string]string{
+				"cmd/api/main.go": `package main
+import "github.com/gofiber/fiber/v3"
+func main() { app := fiber.New(); _ = app }
+`,
+				"internal/handlers/handler.go": `package handlers
+import "github.com/gofiber/fiber/v3"
+func SetupHandler(router *fiber.App) {}
+`,
+			},
+			router: "fiber", setup: "internal/handlers/handler.go", fn: "SetupHandler", serverMain: "cmd/api/main.go",
+		},
+		{
+			name: "echo",
+			files: map[string]string{
+				"cmd/api/main.go": `package main
+import "github.com/labstack/echo/v4"
+func main() { e := echo.New(); _ = e }
+`,
+				"internal/handlers/handler.go": `package handlers
+import "github.com/labstack/echo/v4"
+func Routes(g *echo.Group) {}
+func SetupHandler(e *echo.Echo) {}
+`,
+			},
+			router: "echo", setup: "internal/handlers/handler.go", fn: "SetupHandler", serverMain: "cmd/api/main.go",
+		},
+		{
+			name: "chi",
+			files: map[string]string{
+				"cmd/api/main.go": `package main
+import ("net/http"; "github.com/go-chi/chi/v5")
+func main() { r := chi.NewRouter(); _ = http.ListenAndServe(":8080", r) }
+`,
+				"internal/http/routes.go": `package http
+import "github.com/go-chi/chi/v5"
+func Register(r chi.Router) {}
+`,
+				"internal/http/user/get.go": handler,
+			},
+			router: "chi", setup: "internal/http/routes.go", fn: "Register", serverMain: "cmd/api/main.go",
+		},
+		{
+			name: "stdlib",
+			files: map[string]string{
+				"cmd/api/main.go": `package main
+import "net/http"
+func main() { mux := http.NewServeMux(); _ = http.ListenAndServe(":8080", mux) }
+`,
+				"internal/handlers/handler.go": `package handlers
+import "net/http"
+func SetupHandler(mux *http.ServeMux) {}
+`,
+				"internal/handlers/user/get.go": handler,
+			},
+			router: "stdlib", setup: "internal/handlers/handler.go", fn: "SetupHandler", serverMain: "cmd/api/main.go",
+		},
+		{
+			// A router created in main with routes registered inline
+			name: "aliased chi without a setup function",
+			files: map[string]string{
+				"main.go": `package main
+import ("net/http"; router "github.com/go-chi/chi/v5")
+func main() { r := router.NewRouter(); _ = http.ListenAndServe(":8080", r) }
+`,
+			},
+			router: "chi", serverMain: "main.go",
+		},
+		{
+			name: "no router",
+			files: map[string]string{
+				"main.go":     "package main\nfunc main() {}\n",
+				"user/get.go": handler,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Inspect(writeProject(t, tt.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Router != tt.router || p.RouterSetup != tt.setup || p.RouterFunc != tt.fn || p.ServerMain != tt.serverMain {
+				t.Errorf("router = %q, setup = %q (%q), main = %q; want %q, %q (%q), %q",
+					p.Router, p.RouterSetup, p.RouterFunc, p.ServerMain, tt.router, tt.setup, tt.fn, tt.serverMain)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Package errs defines the typed errors returned by ready-go. Each kind maps to
+// a stable process exit code so scripts can branch on the failure, and may
+// carry a hint telling the user how to recover.
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes returned by the CLI. They are part of the public contract and
+// must not be renumbered.
+const (
+	ExitOK         = 0
+	ExitError      = 1 // unclassified failure
+	ExitValidation = 2 // bad arguments, flags or project layout
+	ExitConflict   = 3 // the target already exists
+	ExitTemplate   = 4 // a template failed to parse or render
+	ExitToolchain  = 5 // an external tool (go, git) failed or is missing
+)
+
+// ValidationError reports invalid input or a project that doesn't look like a ready-go project
+type ValidationError struct {
+	Field   string
+	Message string
+	Hint    string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// ConflictError reports that a file or directory the generator wants to create already exists
+type ConflictError struct {
+	Path    string
+	Message string
+	Hint    string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// TemplateError reports a template that could not be read, parsed or executed
+type TemplateError struct {
+	Template string
+	Err      error
+	Hint     string
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("template %s: %v", e.Template, e.Err)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// ToolchainError reports an external command that failed or could not be started
+type ToolchainError struct {
+	Command string
+	Output  string
+	Err     error
+	Hint    string
+}
+
+func (e *ToolchainError) Error() string {
+	if e.Output == "" {
+		return fmt.Sprintf("%s failed: %v", e.Command, e.Err)
+	}
+	return fmt.Sprintf("%s failed: %v\n%s", e.Command, e.Err, e.Output)
+}
+
+func (e *ToolchainError) Unwrap() error {
+	return e.Err
+}
+
+// Validation creates a ValidationError with a formatted message
+func Validation(hint, format string, args ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...), Hint: hint}
+}
+
+// Conflict creates a ConflictError for path with a formatted message
+func Conflict(path, hint, format string, args ...any) error {
+	return &ConflictError{Path: path, Message: fmt.Sprintf(format, args...), Hint: hint}
+}
+
+// ExitCode returns the process exit code for err
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var validationErr *ValidationError
+	var conflictErr *ConflictError
+	var templateErr *TemplateError
+	var toolchainErr *ToolchainError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return ExitValidation
+	case errors.As(err, &conflictErr):
+		return ExitConflict
+	case errors.As(err, &templateErr):
+		return ExitTemplate
+	case errors.As(err, &toolchainErr):
+		return ExitToolchain
+	default:
+		return ExitError
+	}
+}
+
+// Hint returns the recovery hint attached to err, if any
+func Hint(err error) string {
+	var validationErr *ValidationError
+	var conflictErr *ConflictError
+	var templateErr *TemplateError
+	var toolchainErr *ToolchainError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return validationErr.Hint
+	case errors.As(err, &conflictErr):
+		return conflictErr.Hint
+	case errors.As(err, &templateErr):
+		return templateErr.Hint
+	case errors.As(err, &toolchainErr):
+		return toolchainErr.Hint
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+)
+
+// AuthGenerator adds JWT authentication to a project: the auth package next
+// to the handlers, its settings in config.Config and .env.example, and
+// Authenticate installed in SetupHandler like the middleware of add middleware
+type AuthGenerator struct {
+	config *config.AuthConfig
+	env    *Env
+}
+
+// NewAuthGenerator creates a new AuthGenerator
+func NewAuthGenerator(cfg *config.AuthConfig, env *Env) *AuthGenerator {
+	return &AuthGenerator{
+		config: cfg,
+		env:    env,
+	}
+}
+
+// authFields are the settings of Authenticate
+var authFields = configGroup("JWT authentication (JWT_JWKS_URL, JWT_PUBLIC_KEY_FILE or JWT_SECRET selects the key)",
+	configField{Name: "JWTJWKSURL", Type: "string", Env: "JWT_JWKS_URL", Default: ""},
+	configField{Name: "JWTJWKSRefresh", Type: "time.Duration", Env: "JWT_JWKS_REFRESH", Default: "1h"},
+	configField{Name: "JWTPublicKeyFile", Type: "string", Env: "JWT_PUBLIC_KEY_FILE", Default: ""},
+	configField{Name: "JWTSecret", Type: "string", Env: "JWT_SECRET", Default: ""},
+	configField{Name: "JWTIssuer", Type: "string", Env: "JWT_ISSUER", Default: ""},
+	configField{Name: "JWTAudience", Type: "string", Env: "JWT_AUDIENCE", Default: ""},
+	configField{Name: "JWTRolesClaim", Type: "string", Env: "JWT_ROLES_CLAIM", Default: "roles"},
+	configField{Name: "JWTLeeway", Type: "time.Duration", Env: "JWT_LEEWAY", Default: "30s"},
+	configField{Name: "JWTPublicPaths", Type: "[]string", Env: "JWT_PUBLIC_PATHS", Default: "/docs"},
+)
+
+// Generate renders the auth package unless the project has it, adds its
+// settings and installs Authenticate
+func (g *AuthGenerator) Generate(ctx context.Context) error {
+	cfg := g.config
+	data := middlewareData{ModuleName: cfg.ModuleName, Layout: cfg.Layout, Router: cfg.Router}
+	dir := joinPath(cfg.ProjectPath, cfg.Layout.Handlers, "auth")
+
+	g.env.Reporter.Step("📝 Generating JWT authentication...")
+	var files []File
+	for _, file := range []string{"principal.go", "jwt.go", "jwt_test.go"} {
+		if _, err := os.Stat(joinPath(dir, file)); err == nil {
+			g.env.Reporter.Info(fmt.Sprintf("%s/auth/%s exists; keeping it", cfg.Layout.Handlers, file))
+			continue
+		}
+		f, err := g.env.renderGo("auth/"+file+".tmpl", joinPath(dir, file), data)
+		if err != nil {
+			return fmt.Errorf("generate %s: %w", file, err)
+		}
+		files = append(files, f)
+	}
+
+	// Authenticate records the subject as the actor of the request
+	actor, err := actorFiles(g.env, cfg.ProjectPath, cfg.Layout, cfg.Router)
+	if err != nil {
+		return err
+	}
+	files = append(files, actor...)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	patch, err := configPatch(joinPath(cfg.ProjectPath, "internal", "config", "config.go"), authFields)
+	if err != nil {
+		g.env.Reporter.Warn(err.Error())
+		g.env.Reporter.NextStep("Add the JWT settings to config.Config; see the cfg fields auth.Authenticate uses")
+	}
+	files = append(files, patch...)
+
+	envExample, err := envExamplePatch(joinPath(cfg.ProjectPath, ".env.example"), authFields)
+	if err != nil {
+		return err
+	}
+	files = append(files, envExample...)
+
+	files = append(files, serviceConfigFiles(g.env, cfg.ProjectPath, cfg.ModuleName, cfg.Layout)...)
+
+	// The middleware installer does the same edits for Authenticate
+	mw := NewMiddlewareGenerator(&config.MiddlewareConfig{
+		ProjectPath: cfg.ProjectPath,
+		ModuleName:  cfg.ModuleName,
+		Layout:      cfg.Layout,
+		Router:      cfg.Router,
+	}, g.env)
+	installed, err := mw.install([]middlewareUse{{
+		Name:   "auth",
+		Func:   "Authenticate",
+		Import: cfg.ModuleName + "/" + cfg.Layout.Handlers + "/auth",
+		Call:   "auth.Authenticate(%s)",
+	}})
+	if err != nil {
+		return err
+	}
+	files = append(files, installed...)
+
+	g.env.Reporter.NextStep("go mod tidy         # The auth package uses github.com/golang-jwt/jwt/v5")
+	return g.env.Writer.WriteAll(files)
+}
@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"os"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+)
+
+// cacheFields are the settings of the read-through cache of add entity --cache
+var cacheFields = configGroup("Read-through cache",
+	configField{Name: "CacheTTL", Type: "time.Duration", Env: "CACHE_TTL", Default: "5m"},
+)
+
+// cacheFiles renders the files of the cache package, shared by the cached
+// entity handlers and the rate-limit middleware, that are missing
+func cacheFiles(env *Env, projectPath string, layout config.Layout) ([]File, error) {
+	var files []File
+	for _, name := range []string{"cache.go", "rate_limit.go", "cache_test.go"} {
+		path := joinPath(projectPath, layout.Cache(), name)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		file, err := env.renderGo("internal/cache/"+name+".tmpl", path, nil)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if len(files) > 0 {
+		env.Reporter.NextStep("go mod tidy         # The " + layout.Cache() + " tests use github.com/alicebob/miniredis/v2")
+	}
+	return files, nil
+}
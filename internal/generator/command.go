@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+)
+
+// runCommand runs an external command in dir and records its exit status
+func runCommand(ctx context.Context, r report.Reporter, dir, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+
+	result := report.CommandResult{
+		Command: name,
+		Args:    args,
+		Dir:     dir,
+	}
+	if err != nil {
+		result.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		result.Output = strings.TrimSpace(string(output))
+	}
+	r.Command(result)
+
+	if err != nil {
+		toolchainErr := &errs.ToolchainError{
+			Command: strings.TrimSpace(name + " " + strings.Join(args, " ")),
+			Output:  result.Output,
+			Err:     err,
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			toolchainErr.Hint = fmt.Sprintf("install %s and make sure it is on your PATH", name)
+		}
+		return toolchainErr
+	}
+	return nil
+}
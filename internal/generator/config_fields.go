@@ -0,0 +1,214 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// configField is a field of the project's config.Config, loaded from the
+// environment variable Env by Load
+type configField struct {
+	// Group is the comment above a run of fields in config.go and .env.example
+	Group string
+	Name  string
+	// Type is string, bool, int, []string (comma-separated) or time.Duration
+	Type string
+	Env  string
+	// Default is the default as it would be written in .env.example
+	Default string
+}
+
+// configGroup sets the Group of fields to group
+func configGroup(group string, fields ...configField) []configField {
+	for i := range fields {
+		fields[i].Group = group
+	}
+	return fields
+}
+
+// load is the expression loading the field in the Config literal of Load
+func (f configField) load() string {
+	switch f.Type {
+	case "bool":
+		return fmt.Sprintf("getEnvBool(%q, %s)", f.Env, f.Default)
+	case "int":
+		return fmt.Sprintf("getEnvInt(%q, %s)", f.Env, f.Default)
+	case "[]string":
+		return fmt.Sprintf("getEnvList(%q, %q)", f.Env, f.Default)
+	case "time.Duration":
+		return fmt.Sprintf("parseDuration(getEnv(%q, %q))", f.Env, f.Default)
+	}
+	return fmt.Sprintf("getEnv(%q, %q)", f.Env, f.Default)
+}
+
+// configHelpers are the functions load calls besides getEnv, which every
+// config.go declares, with the imports they need
+var configHelpers = []struct {
+	name    string
+	typ     string
+	imports []string
+	decl    string
+}{
+	{"getEnvBool", "bool", []string{"strconv"}, `
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+`},
+	{"getEnvInt", "int", []string{"strconv"}, `
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+`},
+	{"getEnvList", "[]string", []string{"strings"}, `
+// getEnvList splits a comma-separated value, e.g. CORS_ALLOW_ORIGINS=https://a.example,https://b.example
+func getEnvList(key, defaultValue string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+`},
+	{"parseDuration", "time.Duration", []string{"time"}, `
+func parseDuration(s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+`},
+}
+
+// configPatch adds the fields missing from the Config struct of config.go at
+// path, loaded by the Config literal in Load, with the helpers loading them.
+// It returns nil when every field is there already.
+func configPatch(path string, fields []configField) ([]File, error) {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	manual := strings.Join(names, ", ") + " not added"
+
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s not found; %s", path, manual)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	r, err := parseFile(path, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cfg      *ast.StructType
+		literal  *ast.CompositeLit
+		declared = map[string]bool{}
+	)
+	ast.Inspect(r.file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.TypeSpec:
+			if n.Name.Name == "Config" {
+				cfg, _ = n.Type.(*ast.StructType)
+			}
+		case *ast.FuncDecl:
+			declared[n.Name.Name] = true
+			return n.Name.Name == "Load"
+		case *ast.CompositeLit:
+			if ident, ok := n.Type.(*ast.Ident); ok && ident.Name == "Config" && literal == nil {
+				literal = n
+			}
+		}
+		return true
+	})
+	if cfg == nil || literal == nil || !declared["getEnv"] {
+		return nil, fmt.Errorf("the Config struct, its literal in Load or getEnv was not found in %s; %s", path, manual)
+	}
+	for _, field := range cfg.Fields.List {
+		for _, name := range field.Names {
+			declared[name.Name] = true
+		}
+	}
+
+	var (
+		structText, literalText, helpers strings.Builder
+		imports                          []string
+		group                            string
+	)
+	for _, f := range fields {
+		if declared[f.Name] {
+			continue
+		}
+		if f.Group != group {
+			group = f.Group
+			fmt.Fprintf(&structText, "\n\t// %s\n", group)
+			fmt.Fprintf(&literalText, "\n\t\t// %s\n", group)
+		}
+		fmt.Fprintf(&structText, "\t%s %s\n", f.Name, f.Type)
+		fmt.Fprintf(&literalText, "\t\t%s: %s,\n", f.Name, f.load())
+
+		for _, h := range configHelpers {
+			if h.typ == f.Type && !declared[h.name] {
+				declared[h.name] = true
+				helpers.WriteString(h.decl)
+				imports = append(imports, h.imports...)
+			}
+		}
+	}
+	if structText.Len() == 0 {
+		return nil, nil
+	}
+
+	file, err := r.apply([]edit{
+		{at: r.offset(cfg.Fields.Closing), text: structText.String()},
+		{at: r.offset(literal.Rbrace), text: literalText.String()},
+		{at: len(r.src), text: helpers.String()},
+	}, imports)
+	if err != nil {
+		return nil, err
+	}
+	return []File{file}, nil
+}
+
+// envExamplePatch appends the variables of fields missing from the
+// .env.example at path, under their group comments. A project without the
+// file gets none.
+func envExamplePatch(path string, fields []configField) ([]File, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil
+	}
+
+	var content strings.Builder
+	group := ""
+	for _, f := range fields {
+		if regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(f.Env) + `=`).Match(existing) {
+			continue
+		}
+		if f.Group != group {
+			if content.Len() > 0 {
+				content.WriteString("\n")
+			}
+			group = f.Group
+			fmt.Fprintf(&content, "# %s\n", group)
+		}
+		fmt.Fprintf(&content, "%s=%s\n", f.Env, f.Default)
+	}
+	if content.Len() == 0 {
+		return nil, nil
+	}
+	return appendFile(path, []byte(content.String()))
+}
@@ -0,0 +1,243 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// DTOGenerator writes the Create and Update request DTOs of an existing
+// table, with validate tags derived from its columns
+type DTOGenerator struct {
+	config *config.EntityConfig
+	env    *Env
+}
+
+// NewDTOGenerator creates a new DTOGenerator
+func NewDTOGenerator(cfg *config.EntityConfig, env *Env) *DTOGenerator {
+	return &DTOGenerator{
+		config: cfg,
+		env:    env,
+	}
+}
+
+// requestField is a JSON request field written to one column
+type requestField struct {
+	Name   string // sqlc field name: OrderID
+	Column string // column and JSON name: order_id
+	Type   string // Go type of the request field
+	// Value converts req.<Name> to the sqlc parameter type
+	Value string
+	// Validate is the validate tag derived from the column, e.g. required,max=255
+	Validate string
+}
+
+// lifecycleColumns are maintained by the queries rather than sent by clients
+var lifecycleColumns = []string{"created_at", "updated_at", "deleted_at", "created_by", "updated_by", "version"}
+
+// Generate reads the table from the migrations and renders its DTOs
+func (g *DTOGenerator) Generate(ctx context.Context) error {
+	g.env.Reporter.Step("🔍 Reading migrations...")
+	s, err := schema.Load(joinPath(g.config.ProjectPath, g.config.Layout.Migrations))
+	if err != nil {
+		return errs.Validation("fix the migration, or write the DTOs by hand", "failed to replay migrations in %s: %v", g.config.Layout.Migrations, err)
+	}
+	table, err := lookupTable(s, g.config.TableName, "table")
+	if err != nil {
+		return err
+	}
+
+	var columns []string
+	for _, c := range table.Columns {
+		if !c.AutoIncrement && !c.Generated && !slices.Contains(lifecycleColumns, c.Name) {
+			columns = append(columns, c.Name)
+		}
+	}
+	if len(columns) == 0 {
+		return &errs.ValidationError{Field: "table", Message: fmt.Sprintf("table %s has no columns clients can write", table.Name)}
+	}
+
+	data := &entityData{
+		ModuleName:      g.config.ModuleName,
+		Layout:          g.config.Layout,
+		Router:          g.config.Router,
+		EntityName:      g.config.EntityName,
+		EntityNameLower: g.config.EntityNameLower,
+		TableName:       table.Name,
+		Fields:          requestFields(g.config.Layout.ModelsPackage(), table, columns),
+		Versioned:       table.Column("version") != nil,
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	outputPath := joinPath(g.config.ProjectPath, g.config.Layout.Handlers, g.config.EntityNameLower, "dto.go")
+	file, err := g.env.renderGo("entity/dto.go.tmpl", outputPath, data)
+	if err != nil {
+		return fmt.Errorf("generate DTOs: %w", err)
+	}
+	shared, err := validationFiles(g.env, g.config.ProjectPath, g.config.Layout, g.config.Router)
+	if err != nil {
+		return err
+	}
+
+	return g.env.Writer.WriteAll(append(shared, file))
+}
+
+// requestFields maps the writable columns of t to request fields. ENUM
+// columns are received as strings and converted to the sqlc enum type, and
+// nullable columns are pointers so that omitting them sends NULL.
+func requestFields(models string, t *schema.Table, columns []string) []requestField {
+	fields := make([]requestField, 0, len(columns))
+	for _, name := range columns {
+		c := t.Column(name)
+		if c == nil {
+			continue
+		}
+		f := requestField{Name: sqlcName(c.Name), Column: c.Name, Validate: validateTag(t, c)}
+		f.Value = "req." + f.Name
+		nullType := ""
+		if c.IsEnum() {
+			f.Type, nullType = "string", "sql.NullString"
+			f.Value = fmt.Sprintf("%s.%s(req.%s)", models, sqlcName(t.Name+"_"+c.Name), f.Name)
+		} else {
+			f.Type, nullType, _ = goType(c)
+		}
+		if c.Nullable && nullType != "" {
+			f.Type = "*" + f.Type
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// validateTag derives the validate rules of a request field from its column:
+// NOT NULL strings, times and foreign keys are required (zero numbers and
+// false are valid values), and sizes and ENUM values are enforced before the
+// database rejects them
+func validateTag(t *schema.Table, c *schema.Column) string {
+	typ, _, _ := goType(c)
+	var rules []string
+	switch {
+	case c.Nullable:
+		rules = append(rules, "omitempty")
+	case typ == "string" || typ == "time.Time" || references(t, c.Name) != "":
+		rules = append(rules, "required")
+	}
+
+	switch strings.TrimSuffix(c.Type, "[]") {
+	case "ENUM":
+		if !slices.ContainsFunc(c.Args, func(v string) bool { return v == "" || strings.ContainsAny(v, " '\"") }) {
+			rules = append(rules, "oneof="+strings.Join(c.Args, " "))
+		}
+	case "CHAR", "VARCHAR":
+		if len(c.Args) == 1 {
+			rules = append(rules, "max="+c.Args[0])
+		}
+	case "DECIMAL", "NUMERIC", "DEC", "FIXED":
+		rules = append(rules, "numeric")
+	}
+	if typ == "string" && (c.Name == "email" || strings.HasSuffix(c.Name, "_email")) {
+		rules = append(rules, "email")
+	}
+
+	if len(rules) == 1 && rules[0] == "omitempty" {
+		return ""
+	}
+	return strings.Join(rules, ",")
+}
+
+// validationFiles renders the shared validator, skipped when it already
+// exists. ErrorResponse gets the Errors field it fills when util.go predates it.
+func validationFiles(env *Env, projectPath string, layout config.Layout, router config.Router) ([]File, error) {
+	utilDir := joinPath(projectPath, layout.Handlers, "util")
+	validatePath := filepath.Join(utilDir, "validate.go")
+	if _, err := os.Stat(validatePath); err == nil {
+		return nil, nil
+	}
+
+	file, err := env.renderGo("internal/handlers/util/validate.go.tmpl", validatePath, map[string]any{"Router": router})
+	if err != nil {
+		return nil, err
+	}
+	files := []File{file}
+	env.Reporter.NextStep("go mod tidy         # util.Validate uses github.com/go-playground/validator/v10")
+
+	patch, err := fieldErrorsPatch(filepath.Join(utilDir, "util.go"))
+	if err != nil {
+		env.Reporter.Warn(err.Error())
+		env.Reporter.NextStep(fmt.Sprintf("Add an Errors []FieldError field to ErrorResponse in %s/util", layout.Handlers))
+		return files, nil
+	}
+	return append(files, patch...), nil
+}
+
+// fieldErrorsPatch adds the Errors field and the FieldError type to the
+// ErrorResponse of a util.go written before validation errors existed
+func fieldErrorsPatch(path string) ([]File, error) {
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	var errorResponse *ast.StructType
+	hasFieldError := false
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			switch ts.Name.Name {
+			case "ErrorResponse":
+				errorResponse, _ = ts.Type.(*ast.StructType)
+			case "FieldError":
+				hasFieldError = true
+			}
+		}
+	}
+	if errorResponse == nil {
+		return nil, fmt.Errorf("struct ErrorResponse not found in %s", path)
+	}
+	for _, field := range errorResponse.Fields.List {
+		for _, name := range field.Names {
+			if name.Name == "Errors" {
+				return nil, nil
+			}
+		}
+	}
+
+	out := slices.Clone(src)
+	if !hasFieldError {
+		out = append(out, "\ntype FieldError struct {\n\tField string `json:\"field\"`\n\tRule string `json:\"rule\"`\n\tMessage string `json:\"message\"`\n}\n"...)
+	}
+	at := fset.Position(errorResponse.Fields.Closing).Offset
+	out = slices.Insert(out, at, []byte("\tErrors []FieldError `json:\"errors,omitempty\"`\n")...)
+
+	formatted, err := format.Source(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add Errors to ErrorResponse in %s: %w", path, err)
+	}
+	return []File{{Path: path, Content: formatted, Edit: true}}, nil
+}
@@ -0,0 +1,262 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// EndpointGenerator writes a handler for a route that is not an entity CRUD,
+// e.g. POST /v1/orders/:id/cancel, with a test skeleton, and registers it in
+// the project's route setup function
+type EndpointGenerator struct {
+	config *config.EndpointConfig
+	env    *Env
+}
+
+// NewEndpointGenerator creates a new EndpointGenerator
+func NewEndpointGenerator(cfg *config.EndpointConfig, env *Env) *EndpointGenerator {
+	return &EndpointGenerator{
+		config: cfg,
+		env:    env,
+	}
+}
+
+// endpointData is the data rendered by the endpoint templates
+type endpointData struct {
+	ModuleName string
+	Layout     config.Layout
+	Router     config.Router
+	Package    string // order
+	Method     string // POST
+	MethodName string // Post, as in http.MethodPost
+	Path       string // /v1/orders/:id/cancel
+	Name       string // CancelHandler
+	// StdImports are the standard library imports of the handler
+	StdImports []string
+
+	// Params are the path parameters, bound into an anonymous struct
+	Params        []endpointField
+	ParamsMessage string
+	ParamsCode    string
+	// Query and Body name the bound types, empty when there are no fields
+	Query, Body string
+	QueryFields []endpointField
+	BodyFields  []endpointField
+	NoContent   bool
+	Response    string // expression the handler responds with
+
+	// TestPath is Path with example values, and BadPath has an invalid
+	// integer parameter; it is empty when no parameter is an integer
+	TestPath string
+	BadPath  string
+
+	// Func is the route registration function, and Setup the file declaring it
+	Func  string
+	Setup *routeSetup
+}
+
+// endpointField is a bound field: Name is the Go name, Tag the name in the
+// request and Type the Go type
+type endpointField struct {
+	Name, Tag, Type string
+}
+
+// Generate renders the handler and its test, then registers the route
+func (g *EndpointGenerator) Generate(ctx context.Context) error {
+	data := g.data()
+	dir := joinPath(g.config.ProjectPath, g.config.Layout.Handlers, g.config.Package)
+	file := snakeCase(g.config.Name)
+
+	declared, err := declaredTypes(dir, file+".go")
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{data.Name, data.Query, data.Body} {
+		if name != "" && declared[name] {
+			return errs.Validation("choose another --name", "%s is declared in %s/%s already", name, g.config.Layout.Handlers, g.config.Package)
+		}
+	}
+
+	g.env.Reporter.Step("📝 Generating handler...")
+	handler, err := g.env.renderGo("endpoint/handler.go.tmpl", filepath.Join(dir, file+".go"), data)
+	if err != nil {
+		return fmt.Errorf("generate handler: %w", err)
+	}
+	test, err := g.env.renderGo("endpoint/handler_test.go.tmpl", filepath.Join(dir, file+"_test.go"), data)
+	if err != nil {
+		return fmt.Errorf("generate test: %w", err)
+	}
+	files := []File{handler, test}
+
+	if data.Body != "" {
+		validation, err := validationFiles(g.env, g.config.ProjectPath, g.config.Layout, g.config.Router)
+		if err != nil {
+			return err
+		}
+		files = append(files, validation...)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	registration, err := g.registration(data)
+	if err != nil {
+		return err
+	}
+	if registration != nil {
+		files = append(files, *registration)
+	}
+
+	return g.env.Writer.WriteAll(files)
+}
+
+// data derives the handler, its bound types and the test requests from the config
+func (g *EndpointGenerator) data() *endpointData {
+	cfg := g.config
+	h := newHTTPSyntax(cfg.Router.Framework)
+	data := &endpointData{
+		ModuleName: cfg.ModuleName,
+		Layout:     cfg.Layout,
+		Router:     cfg.Router,
+		Package:    cfg.Package,
+		Method:     cfg.Method,
+		MethodName: cfg.Method[:1] + strings.ToLower(cfg.Method[1:]),
+		Path:       cfg.Path,
+		Name:       cfg.Name + "Handler",
+		StdImports: append([]string{"database/sql"}, h.StdImports()...),
+		NoContent:  cfg.Method == "DELETE",
+		Func:       "setup" + schema.GoName(cfg.Package) + cfg.Name + "Handler",
+	}
+
+	test, bad := strings.Split(cfg.Path, "/"), strings.Split(cfg.Path, "/")
+	invalid := false
+	for i, seg := range test {
+		param, ok := strings.CutPrefix(seg, ":")
+		if !ok {
+			continue
+		}
+		field := endpointField{Name: schema.GoName(param), Tag: param, Type: "string"}
+		test[i] = "x"
+		if param == "id" || strings.HasSuffix(param, "_id") {
+			field.Type = "int"
+			test[i], bad[i] = "1", "abc"
+			invalid = true
+		}
+		data.Params = append(data.Params, field)
+	}
+	data.TestPath = strings.Join(test, "/")
+	if invalid {
+		data.BadPath = strings.Join(bad, "/")
+	}
+	if len(data.Params) > 0 {
+		data.ParamsMessage, data.ParamsCode = "Invalid path parameters", "INVALID_PARAMS"
+		if len(data.Params) == 1 && data.Params[0].Tag == "id" {
+			data.ParamsMessage, data.ParamsCode = "Invalid ID format", "INVALID_ID_FORMAT"
+		}
+	}
+
+	data.QueryFields = endpointFields(cfg.QueryFields)
+	data.BodyFields = endpointFields(cfg.BodyFields)
+	if len(data.QueryFields) > 0 {
+		data.Query = cfg.Name + "Query"
+	}
+	if len(data.BodyFields) > 0 {
+		data.Body = cfg.Name + "Request"
+	}
+	for _, f := range append(slices.Clone(data.QueryFields), data.BodyFields...) {
+		if f.Type == "time.Time" && !slices.Contains(data.StdImports, "time") {
+			data.StdImports = append(data.StdImports, "time")
+		}
+	}
+
+	// The stub responds with what it bound, like GetByIDHandler
+	switch {
+	case data.Body != "":
+		data.Response = "util.SuccessResponse{Data: req}"
+	case len(data.Params) > 0:
+		data.Response = "util.SuccessResponse{Data: params}"
+	case data.Query != "":
+		data.Response = "util.SuccessResponse{Data: query}"
+	default:
+		data.Response = "util.SuccessResponse{}"
+	}
+	return data
+}
+
+// endpointFields maps query or body fields to Go fields
+func endpointFields(fields []config.EndpointField) []endpointField {
+	var out []endpointField
+	for _, f := range fields {
+		out = append(out, endpointField{Name: schema.GoName(f.Name), Tag: f.Name, Type: config.EndpointFieldTypes[f.Type]})
+	}
+	return out
+}
+
+// declaredTypes returns the types declared by the Go files of the package in
+// dir, except skip and the tests; a missing directory declares none
+func declaredTypes(dir, skip string) (map[string]bool, error) {
+	declared := map[string]bool{}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		if e.IsDir() || e.Name() == skip || !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, e.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, e.Name()), err)
+		}
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+				for _, spec := range gen.Specs {
+					declared[spec.(*ast.TypeSpec).Name.Name] = true
+				}
+			}
+		}
+	}
+	return declared, nil
+}
+
+// registration adds the registration function of the route to the route
+// setup function. It returns nil when the route is registered already, or
+// when the setup can't be edited, which is reported as a next step.
+func (g *EndpointGenerator) registration(data *endpointData) (*File, error) {
+	setup, err := loadRouteSetup(g.config.ProjectPath, g.config.Router)
+	if err != nil {
+		g.env.Reporter.Warn(fmt.Sprintf("the route was not registered: %v", err))
+		g.env.Reporter.NextStep(fmt.Sprintf("Register %s.%s for %s %s by hand", data.Package, data.Name, data.Method, data.Path))
+		return nil, nil
+	}
+	if setup.defines(data.Func) {
+		g.env.Reporter.Info(fmt.Sprintf("Route already registered by %s in %s", data.Func, g.config.Router.Setup))
+		return nil, nil
+	}
+
+	data.Setup = setup
+	decl, err := g.env.Templates.Render("endpoint/routes.go.tmpl", data)
+	if err != nil {
+		return nil, err
+	}
+	imports := append([]string{"log/slog", g.config.ModuleName + "/" + g.config.Layout.Handlers + "/" + data.Package},
+		setup.http.routeImports(g.config.ModuleName+"/"+g.config.Layout.Handlers+"/util")...)
+	file, err := setup.register(data.Func, decl, imports...)
+	if err != nil {
+		return nil, &errs.TemplateError{Template: "endpoint/routes.go.tmpl", Err: err, Hint: templateHint}
+	}
+	return &file, nil
+}
@@ -1,119 +1,400 @@
 package generator
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
-	"time"
+	"slices"
 
 	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
 )
 
 // EntityGenerator handles generation of new entities in existing projects
 type EntityGenerator struct {
 	config *config.EntityConfig
+	env    *Env
 }
 
 // NewEntityGenerator creates a new EntityGenerator
-func NewEntityGenerator(cfg *config.EntityConfig) *EntityGenerator {
+func NewEntityGenerator(cfg *config.EntityConfig, env *Env) *EntityGenerator {
 	return &EntityGenerator{
 		config: cfg,
+		env:    env,
 	}
 }
 
-// Generate creates the entity file, migration, and queries
-func (g *EntityGenerator) Generate() error {
-	// Generate entity file
-	if err := g.generateEntityFile(); err != nil {
-		return fmt.Errorf("generate entity file: %w", err)
+// entityData is the template data of the entity templates
+type entityData struct {
+	ModuleName      string
+	Layout          config.Layout
+	Router          config.Router
+	EntityName      string
+	EntityNameLower string
+	TableName       string
+	EntityPackage   string
+	// ModelName is the struct sqlc generates for the table, named after its
+	// singular: orderitems → Orderitem
+	ModelName string
+	// Columns are the writable columns of the entity's table
+	Columns []string
+	// Fields are the request fields of the write handlers, one per column
+	Fields []requestField
+	// OwnColumn references the entity in join tables, e.g. post_id, and
+	// OwnField is its sqlc field name
+	OwnColumn  string
+	OwnField   string
+	BelongsTo  []relation
+	ManyToMany []relation
+
+	// ListColumns are selected by the list endpoints and scanned into
+	// ScanFields; Filters and Sorts are the columns clients may use
+	ListColumns []string
+	ScanFields  []string
+	Filters     []string
+	Sorts       []string
+
+	SoftDelete    bool
+	Audit         bool
+	Versioned     bool
+	Cache         bool
+	WriteHandlers bool
+
+	// Relation is the relation a handler template is rendered for
+	Relation relation
+	// Setup is the project's route setup function
+	Setup *routeSetup
+}
+
+// Generate creates the entity file, migration, and queries, stopping early if ctx is cancelled.
+// Every file is rendered before anything is written, so conflicts are resolved up front.
+func (g *EntityGenerator) Generate(ctx context.Context) error {
+	data, err := g.templateData()
+	if err != nil {
+		return err
 	}
 
 	// Generate migration file
-	if err := g.generateMigrationFile(); err != nil {
+	migration, err := g.generateMigrationFile(data)
+	if err != nil {
 		return fmt.Errorf("generate migration file: %w", err)
 	}
 
+	// Generate entity file from the table the migration creates
+	table, err := g.migrationTable(migration)
+	if err != nil {
+		return fmt.Errorf("generate entity file: %w", err)
+	}
+	entity, err := g.generateEntityFile(table)
+	if err != nil {
+		return fmt.Errorf("generate entity file: %w", err)
+	}
+
 	// Generate queries file
-	if err := g.generateQueriesFile(); err != nil {
+	queries, err := g.generateQueriesFile(data)
+	if err != nil {
 		return fmt.Errorf("generate queries file: %w", err)
 	}
 
-	return nil
-}
+	// Generate the list handler, the DTOs, the handlers of the write options
+	// and the nested routes of relations
+	data.Fields = requestFields(g.config.Layout.ModelsPackage(), table, data.Columns)
+	data.listColumns(table)
+	handlers, err := g.handlerFiles(data)
+	if err != nil {
+		return fmt.Errorf("generate handlers: %w", err)
+	}
 
-// generateEntityFile creates the entity Go file
-func (g *EntityGenerator) generateEntityFile() error {
-	// Create internal/entity directory if it doesn't exist
-	entityDir := filepath.Join(g.config.ProjectPath, "internal", "entity")
-	if err := createDirIfNotExists(entityDir); err != nil {
+	if data.Cache {
+		handlers = append(handlers, g.cacheSettings()...)
+	}
+
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 
-	outputPath := filepath.Join(entityDir, g.config.EntityNameLower+".go")
+	return g.env.Writer.WriteAll(append([]File{entity, migration, queries}, handlers...))
+}
+
+// templateData resolves the entity's relations against the tables created
+// by the existing migrations
+func (g *EntityGenerator) templateData() (*entityData, error) {
+	data := &entityData{
+		ModuleName:      g.config.ModuleName,
+		Layout:          g.config.Layout,
+		Router:          g.config.Router,
+		EntityName:      g.config.EntityName,
+		EntityNameLower: g.config.EntityNameLower,
+		TableName:       g.config.TableName,
+		EntityPackage:   g.config.Layout.EntityPackage(),
+		ModelName:       sqlcName(config.Singularize(g.config.TableName)),
+		OwnColumn:       g.config.EntityNameLower + "_id",
+		OwnField:        sqlcName(g.config.EntityNameLower + "_id"),
+		SoftDelete:      g.config.SoftDelete,
+		Audit:           g.config.Audit,
+		Versioned:       g.config.Versioned,
+		Cache:           g.config.Cache,
+		WriteHandlers:   g.config.WriteHandlers(),
+	}
+	if len(g.config.BelongsTo) == 0 && len(g.config.ManyToMany) == 0 {
+		data.Columns = []string{"name", "status"}
+		return data, nil
+	}
 
-	err := RenderTemplateToFile(
-		"entity/entity.go.tmpl",
-		outputPath,
-		g.config.TemplateData(),
-	)
+	s, err := schema.Load(joinPath(g.config.ProjectPath, g.config.Layout.Migrations))
 	if err != nil {
-		return err
+		return nil, errs.Validation("fix the migration, or add the relation by hand", "failed to replay migrations in %s: %v", g.config.Layout.Migrations, err)
+	}
+
+	for _, name := range g.config.BelongsTo {
+		rel, err := g.resolveRelation(s, name, "belongs-to")
+		if err != nil {
+			return nil, err
+		}
+		rel.Path = g.config.NestedPath(rel.EntityLower)
+		data.BelongsTo = append(data.BelongsTo, rel)
+		data.Columns = append(data.Columns, rel.Column)
+	}
+	data.Columns = append(data.Columns, "name", "status")
+
+	for _, name := range g.config.ManyToMany {
+		rel, err := g.resolveRelation(s, name, "many-to-many")
+		if err != nil {
+			return nil, err
+		}
+		rel.JoinTable = g.config.EntityNameLower + "_" + rel.Table
+		rel.Path = rel.Table
+		data.ManyToMany = append(data.ManyToMany, rel)
 	}
 
-	fmt.Printf("  ✓ Created %s\n", outputPath)
-	return nil
+	return data, nil
+}
+
+// migrationTable returns the entity's table as created by migration
+func (g *EntityGenerator) migrationTable(migration File) (*schema.Table, error) {
+	s := &schema.Schema{}
+	if err := s.ApplyMigration(string(migration.Content)); err != nil {
+		return nil, &errs.TemplateError{Template: "entity/migration.sql.tmpl", Err: err, Hint: templateHint}
+	}
+	table := s.Table(g.config.TableName)
+	if table == nil {
+		return nil, &errs.TemplateError{
+			Template: "entity/migration.sql.tmpl",
+			Err:      fmt.Errorf("migration does not create table %s", g.config.TableName),
+			Hint:     templateHint,
+		}
+	}
+	return table, nil
 }
 
-// generateMigrationFile creates the goose migration SQL file
-func (g *EntityGenerator) generateMigrationFile() error {
-	timestamp := time.Now().Format("20060102150405")
-	filename := fmt.Sprintf("%s_create_%s.sql", timestamp, g.config.TableName)
+// generateEntityFile renders the entity Go file for the table created by
+// the migration, so the struct always matches the generated columns
+func (g *EntityGenerator) generateEntityFile(table *schema.Table) (File, error) {
+	outputPath := joinPath(g.config.ProjectPath, g.config.Layout.Entity, g.config.EntityNameLower+".go")
+	return g.env.renderEntityStruct(outputPath, newEntityStruct(g.config.Layout.EntityPackage(), g.config.EntityName, table, NullSQL))
+}
 
-	outputPath := filepath.Join(
-		g.config.ProjectPath,
-		"database",
-		"migrations",
-		filename,
-	)
+// generateMigrationFile renders the goose migration SQL file. An existing
+// create migration for the same table is reused so that re-running the
+// command goes through the conflict policy instead of adding a duplicate.
+func (g *EntityGenerator) generateMigrationFile(data *entityData) (File, error) {
+	migrationsDir := joinPath(g.config.ProjectPath, g.config.Layout.Migrations)
+	suffix := fmt.Sprintf("_create_%s.sql", g.config.TableName)
 
-	err := RenderTemplateToFile(
-		"entity/migration.sql.tmpl",
-		outputPath,
-		g.config.TemplateData(),
-	)
+	outputPath, err := findMigration(migrationsDir, suffix)
 	if err != nil {
-		return err
+		return File{}, err
+	}
+	if outputPath == "" {
+		version, err := nextMigrationVersion(migrationsDir, g.config.Versioning)
+		if err != nil {
+			return File{}, err
+		}
+		outputPath = filepath.Join(migrationsDir, version+suffix)
+	}
+
+	return g.env.render("entity/migration.sql.tmpl", outputPath, data)
+}
+
+// generateQueriesFile renders the SQLC queries file
+func (g *EntityGenerator) generateQueriesFile(data *entityData) (File, error) {
+	outputPath := joinPath(g.config.ProjectPath, g.config.Layout.Queries, g.config.EntityNameLower+".sql")
+
+	return g.env.render("entity/queries.sql.tmpl", outputPath, data)
+}
+
+// listParams are the query parameters of util.ParseListQuery, which can't
+// double as filters
+var listParams = []string{"limit", "cursor", "offset", "sort"}
+
+// listColumns derives the list endpoint from the columns of t. Every column
+// is selected; scalar columns can be sorted by, and those compared by
+// equality can be filtered on.
+func (d *entityData) listColumns(t *schema.Table) {
+	for _, c := range t.Columns {
+		d.ListColumns = append(d.ListColumns, c.Name)
+		d.ScanFields = append(d.ScanFields, schema.GoName(c.Name))
+
+		typ, _, _ := goType(c)
+		if typ == "json.RawMessage" || typ == "[]byte" {
+			continue
+		}
+		switch c.Name {
+		case "deleted_at", "created_by", "updated_by", "version":
+		default:
+			d.Sorts = append(d.Sorts, c.Name)
+		}
+		if typ != "time.Time" && c.Name != "id" && c.Name != "version" && !slices.Contains(listParams, c.Name) {
+			d.Filters = append(d.Filters, c.Name)
+		}
+	}
+}
+
+// handlerFiles renders the list handler, the request DTOs, the write handlers
+// requested by the entity options and the handlers serving the nested routes
+// of each relation, then registers their routes in the project's route setup
+// function
+func (g *EntityGenerator) handlerFiles(data *entityData) ([]File, error) {
+	handlerDir := joinPath(g.config.ProjectPath, g.config.Layout.Handlers, g.config.EntityNameLower)
+	list, err := g.env.renderGo("entity/list_handler.go.tmpl", joinPath(handlerDir, "list.go"), data)
+	if err != nil {
+		return nil, err
+	}
+	dto, err := g.env.renderGo("entity/dto.go.tmpl", joinPath(handlerDir, "dto.go"), data)
+	if err != nil {
+		return nil, err
+	}
+	files := []File{list, dto}
+
+	// The pagination helpers are shared by every entity, and missing from
+	// projects created before they were added
+	paginationPath := joinPath(g.config.ProjectPath, g.config.Layout.Handlers, "util", "pagination.go")
+	if _, err := os.Stat(paginationPath); os.IsNotExist(err) {
+		file, err := g.env.renderGo("internal/handlers/util/pagination.go.tmpl", paginationPath, data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	validation, err := validationFiles(g.env, g.config.ProjectPath, g.config.Layout, g.config.Router)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, validation...)
+
+	if data.WriteHandlers {
+		for _, name := range []string{"create", "update", "delete"} {
+			file, err := g.env.renderGo("entity/"+name+"_handler.go.tmpl", joinPath(handlerDir, name+".go"), data)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+	}
+	if data.Cache {
+		file, err := g.env.renderGo("entity/get_handler.go.tmpl", joinPath(handlerDir, "get.go"), data)
+		if err != nil {
+			return nil, err
+		}
+		shared, err := cacheFiles(g.env, g.config.ProjectPath, g.config.Layout)
+		if err != nil {
+			return nil, err
+		}
+		files = append(append(files, file), shared...)
+	}
+	if data.Audit {
+		actor, err := actorFiles(g.env, g.config.ProjectPath, g.config.Layout, g.config.Router)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, actor...)
+	}
+	for _, rel := range data.BelongsTo {
+		d := *data
+		d.Relation = rel
+		file, err := g.env.renderGo("entity/belongs_to_handler.go.tmpl", joinPath(handlerDir, "list_by_"+rel.EntityLower+".go"), d)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	for _, rel := range data.ManyToMany {
+		d := *data
+		d.Relation = rel
+		file, err := g.env.renderGo("entity/many_to_many_handler.go.tmpl", joinPath(handlerDir, rel.Table+".go"), d)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	setup, err := loadRouteSetup(g.config.ProjectPath, g.config.Router)
+	if err != nil {
+		g.env.Reporter.Warn(fmt.Sprintf("routes were not registered: %v", err))
+		g.env.Reporter.NextStep(fmt.Sprintf("Register the handlers in %s/%s by hand", g.config.Layout.Handlers, g.config.EntityNameLower))
+		return files, nil
+	}
+	fn := fmt.Sprintf("setup%sHandlers", g.config.EntityName)
+	if setup.defines(fn) {
+		g.env.Reporter.Info(fmt.Sprintf("Routes already registered by %s in %s", fn, g.config.Router.Setup))
+		return files, nil
 	}
 
-	fmt.Printf("  ✓ Created %s\n", outputPath)
-	return nil
+	data.Setup = setup
+	decl, err := g.env.Templates.Render("entity/routes.go.tmpl", data)
+	if err != nil {
+		return nil, err
+	}
+	imports := append([]string{"log/slog", g.config.ModuleName + "/" + g.config.Layout.Handlers + "/" + g.config.EntityNameLower},
+		setup.http.routeImports(g.config.ModuleName+"/"+g.config.Layout.Handlers+"/util")...)
+	registration, err := setup.register(fn, decl, imports...)
+	if err != nil {
+		return nil, &errs.TemplateError{Template: "entity/routes.go.tmpl", Err: err, Hint: templateHint}
+	}
+	return append(files, registration), nil
 }
 
-// generateQueriesFile creates the SQLC queries file
-func (g *EntityGenerator) generateQueriesFile() error {
-	outputPath := filepath.Join(
-		g.config.ProjectPath,
-		"database",
-		"queries",
-		g.config.EntityNameLower+".sql",
-	)
-
-	err := RenderTemplateToFile(
-		"entity/queries.sql.tmpl",
-		outputPath,
-		g.config.TemplateData(),
-	)
+// cacheSettings adds CACHE_TTL to config.Config and .env.example, and the
+// Config the routes read it from to cmd.APIService. Failures are reported
+// as next steps.
+func (g *EntityGenerator) cacheSettings() []File {
+	files, err := configPatch(joinPath(g.config.ProjectPath, "internal", "config", "config.go"), cacheFields)
 	if err != nil {
-		return err
+		g.env.Reporter.Warn(err.Error())
+		g.env.Reporter.NextStep("Add CacheTTL time.Duration, loaded from CACHE_TTL, to config.Config")
 	}
+	envExample, err := envExamplePatch(joinPath(g.config.ProjectPath, ".env.example"), cacheFields)
+	if err != nil {
+		g.env.Reporter.Warn(err.Error())
+	}
+	files = append(files, envExample...)
+	return append(files, serviceConfigFiles(g.env, g.config.ProjectPath, g.config.ModuleName, g.config.Layout)...)
+}
 
-	fmt.Printf("  ✓ Created %s\n", outputPath)
-	return nil
+// actorFiles renders the actor helpers, shared by audited entities and the
+// middleware recording or reading who makes a request, unless they exist
+func actorFiles(env *Env, projectPath string, layout config.Layout, router config.Router) ([]File, error) {
+	actorPath := joinPath(projectPath, layout.Handlers, "util", "actor.go")
+	if _, err := os.Stat(actorPath); err == nil {
+		return nil, nil
+	}
+	file, err := env.renderGo("internal/handlers/util/actor.go.tmpl", actorPath, map[string]any{"Router": router})
+	if err != nil {
+		return nil, err
+	}
+	return []File{file}, nil
 }
 
-func createDirIfNotExists(dir string) error {
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return os.MkdirAll(dir, 0755)
+// findMigration returns the first migration in dir whose name ends with suffix, or ""
+func findMigration(dir, suffix string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+suffix))
+	if err != nil {
+		return "", fmt.Errorf("failed to search migrations: %w", err)
+	}
+	if len(matches) == 0 {
+		return "", nil
 	}
-	return nil
+	return matches[0], nil
 }
@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// NullStyle selects how nullable columns are represented in entity structs
+type NullStyle string
+
+const (
+	// NullSQL uses the database/sql Null* types, e.g. sql.NullString
+	NullSQL NullStyle = "sql"
+	// NullPointer uses pointers, e.g. *string
+	NullPointer NullStyle = "pointer"
+)
+
+// NullStyles lists every supported NullStyle
+var NullStyles = []NullStyle{NullSQL, NullPointer}
+
+// entityStruct is the data rendered by entity/entity.go.tmpl
+type entityStruct struct {
+	Package string
+	Name    string
+	Table   string
+	Imports []string
+	Fields  []structField
+	Enums   []enumType
+}
+
+type structField struct {
+	Name    string
+	Type    string
+	Column  string
+	Comment string
+	// References names the column a foreign key points at, e.g. customers.id
+	References string
+}
+
+type enumType struct {
+	Name   string
+	Column string
+	Values []enumValue
+	// Nullable enums also get a Null<Name> wrapper implementing sql.Scanner
+	Nullable bool
+}
+
+type enumValue struct {
+	Name  string
+	Value string
+}
+
+// newEntityStruct maps the columns of t onto a Go struct called name
+func newEntityStruct(pkg, name string, t *schema.Table, nulls NullStyle) entityStruct {
+	s := entityStruct{Package: pkg, Name: name, Table: t.Name}
+	imports := map[string]bool{}
+
+	for _, c := range t.Columns {
+		field := structField{Name: schema.GoName(c.Name), Column: c.Name, Comment: c.Comment, References: references(t, c.Name)}
+
+		if c.IsEnum() {
+			enum := enumType{Name: name + field.Name, Column: c.Name, Nullable: c.Nullable && nulls == NullSQL}
+			for _, v := range c.Args {
+				enum.Values = append(enum.Values, enumValue{Name: enum.Name + enumValueName(v), Value: v})
+			}
+			s.Enums = append(s.Enums, enum)
+
+			field.Type = enum.Name
+			if c.Nullable {
+				if nulls == NullPointer {
+					field.Type = "*" + enum.Name
+				} else {
+					field.Type = "Null" + enum.Name
+					imports["database/sql/driver"] = true
+					imports["fmt"] = true
+				}
+			}
+		} else {
+			typ, nullType, pkg := goType(c)
+			field.Type = typ
+			switch {
+			case !c.Nullable || nullType == "":
+			case nulls == NullPointer:
+				field.Type = "*" + typ
+			default:
+				field.Type = nullType
+				pkg = "database/sql"
+			}
+			if pkg != "" {
+				imports[pkg] = true
+			}
+		}
+
+		s.Fields = append(s.Fields, field)
+	}
+
+	for p := range imports {
+		s.Imports = append(s.Imports, p)
+	}
+	slices.Sort(s.Imports)
+	return s
+}
+
+// references returns "table.column" for the column a foreign key on column
+// points at, or "" when column isn't part of one
+func references(t *schema.Table, column string) string {
+	for _, fk := range t.ForeignKeys {
+		if i := slices.Index(fk.Columns, column); i >= 0 && i < len(fk.RefColumns) {
+			return fk.RefTable + "." + fk.RefColumns[i]
+		}
+	}
+	return ""
+}
+
+// goType returns the Go type for a column, the database/sql type used when it
+// is nullable (empty when the type already admits NULL, like []byte), and the
+// package the Go type needs.
+func goType(c *schema.Column) (typ, nullType, pkg string) {
+	unsigned := func(signed, unsigned string) string {
+		if c.Unsigned {
+			return unsigned
+		}
+		return signed
+	}
+
+	switch strings.TrimSuffix(c.Type, "[]") {
+	case "BOOL", "BOOLEAN":
+		return "bool", "sql.NullBool", ""
+	case "TINYINT":
+		if len(c.Args) == 1 && c.Args[0] == "1" && !c.Unsigned {
+			return "bool", "sql.NullBool", ""
+		}
+		return unsigned("int8", "uint8"), "sql.NullInt16", ""
+	case "SMALLINT", "SMALLSERIAL", "INT2", "YEAR":
+		return unsigned("int16", "uint16"), "sql.NullInt16", ""
+	case "MEDIUMINT", "INT", "INTEGER", "SERIAL", "INT4":
+		return unsigned("int32", "uint32"), "sql.NullInt32", ""
+	case "BIGINT", "BIGSERIAL", "INT8":
+		return unsigned("int64", "uint64"), "sql.NullInt64", ""
+	case "FLOAT", "REAL", "FLOAT4":
+		return "float32", "sql.NullFloat64", ""
+	case "DOUBLE", "FLOAT8":
+		return "float64", "sql.NullFloat64", ""
+	case "DECIMAL", "NUMERIC", "DEC", "FIXED":
+		// decimals are kept as strings to avoid losing precision
+		return "string", "sql.NullString", ""
+	case "DATE", "DATETIME", "TIMESTAMP", "TIMESTAMPTZ":
+		return "time.Time", "sql.NullTime", "time"
+	case "JSON", "JSONB":
+		return "json.RawMessage", "", "encoding/json"
+	case "BINARY", "VARBINARY", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BYTEA", "BIT":
+		return "[]byte", "", ""
+	default:
+		// CHAR, VARCHAR, TEXT, SET, TIME, UUID and anything unknown
+		return "string", "sql.NullString", ""
+	}
+}
+
+// enumValueName turns an ENUM value into a constant suffix: "on-hold" → "OnHold"
+func enumValueName(v string) string {
+	name := schema.GoName(strings.ToLower(v))
+	if name == "X" {
+		return "Empty"
+	}
+	return name
+}
+
+// renderEntityStruct renders an entity struct and gofmts the result
+func (e *Env) renderEntityStruct(outputPath string, s entityStruct) (File, error) {
+	return e.renderGo("entity/entity.go.tmpl", outputPath, s)
+}
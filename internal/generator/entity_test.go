@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"context"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/templates"
+)
+
+// stubImporter type-checks the project packages a generated file imports from
+// stub sources, and the standard library from GOROOT
+type stubImporter struct {
+	fset  *token.FileSet
+	std   types.Importer
+	stubs map[string]string
+	done  map[string]*types.Package
+}
+
+func (im *stubImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := im.done[path]; ok {
+		return pkg, nil
+	}
+	src, ok := im.stubs[path]
+	if !ok {
+		return im.std.Import(path)
+	}
+	f, err := parser.ParseFile(im.fset, path+"/stub.go", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := (&types.Config{Importer: im}).Check(path, im.fset, []*ast.File{f}, nil)
+	if err != nil {
+		return nil, err
+	}
+	im.done[path] = pkg
+	return pkg, nil
+}
+
+// TestCachedEntityCompiles type-checks the cached Get handler of a multi-word
+// entity against stubs of the packages it uses. sqlc names the model of the
+// orderitems table Orderitem, not OrderItem.
+func TestCachedEntityCompiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"database/migrations", "database/queries"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/p\n\ngo 1.23\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.NewEntityConfig("OrderItem")
+	cfg.ProjectPath = dir
+	cfg.ModuleName = "example.com/p"
+	cfg.Engine = "mysql"
+	cfg.Router = config.Router{Framework: config.FrameworkStdlib}
+	cfg.Layout.Handlers = "pkg/http/handlers"
+	cfg.Cache = true
+	cfg.Process()
+	env, err := NewEnv(templates.FS, ConflictFail, nil, report.Discard("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewEntityGenerator(cfg, env).Generate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cache package follows the handlers directory
+	if _, err := os.Stat(filepath.Join(dir, "pkg", "http", "cache", "cache.go")); err != nil {
+		t.Errorf("cache package not written next to the handlers: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	get, err := parser.ParseFile(fset, "get.go", mustRead(t, filepath.Join(dir, "pkg", "http", "handlers", "orderitem", "get.go")), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	im := &stubImporter{
+		fset: fset,
+		std:  importer.ForCompiler(fset, "source", nil),
+		done: map[string]*types.Package{},
+		stubs: map[string]string{
+			"github.com/redis/go-redis/v9": `package redis
+type Client struct{}`,
+			"example.com/p/pkg/http/cache": `package cache
+import ("context"; "time"; "github.com/redis/go-redis/v9")
+func Key(parts ...any) string { return "" }
+func GetOrLoad[T any](ctx context.Context, rdb *redis.Client, key string, ttl time.Duration, load func(context.Context) (T, error)) (T, error) { return load(ctx) }`,
+			"example.com/p/pkg/http/handlers/util": `package util
+import "net/http"
+type SuccessResponse struct { Data any }
+func BindURI(r *http.Request, target any) error { return nil }
+func BuildErrorWithCode(status int, message, code string) error { return nil }
+func HandleError(w http.ResponseWriter, err error) error { return err }
+func JSON(w http.ResponseWriter, status int, v any) error { return nil }`,
+			// What sqlc generates for the orderitems table and GetOrderItem
+			"example.com/p/internal/models": `package models
+import ("context"; "database/sql")
+type Orderitem struct { ID int32 }
+type Queries struct{}
+func (q *Queries) GetOrderItem(ctx context.Context, db *sql.DB, id int32) (Orderitem, error) { return Orderitem{}, nil }`,
+		},
+	}
+	if _, err := (&types.Config{Importer: im}).Check("example.com/p/pkg/http/handlers/orderitem", fset, []*ast.File{get}, nil); err != nil {
+		t.Errorf("get.go does not compile: %v", err)
+	}
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
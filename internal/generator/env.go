@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+)
+
+// Env carries the collaborators shared by every generator
+type Env struct {
+	Templates *Templates
+	Writer    *Writer
+	Reporter  report.Reporter
+}
+
+// NewEnv creates an Env that renders templates from fsys, writes files under
+// policy and reports progress to r
+func NewEnv(fsys fs.FS, policy ConflictPolicy, resolver ConflictResolver, r report.Reporter) (*Env, error) {
+	writer, err := NewWriter(policy, resolver, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Env{
+		Templates: NewTemplates(fsys),
+		Writer:    writer,
+		Reporter:  r,
+	}, nil
+}
+
+// render renders a template into a File destined for outputPath
+func (e *Env) render(templateName, outputPath string, data any) (File, error) {
+	content, err := e.Templates.Render(templateName, data)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: outputPath, Content: content}, nil
+}
+
+// renderGo renders a Go template and gofmts the result, so that templates
+// can build imports and literals without caring about alignment
+func (e *Env) renderGo(templateName, outputPath string, data any) (File, error) {
+	file, err := e.render(templateName, outputPath, data)
+	if err != nil {
+		return File{}, err
+	}
+
+	formatted, err := format.Source(file.Content)
+	if err != nil {
+		return File{}, &errs.TemplateError{
+			Template: templateName,
+			Err:      fmt.Errorf("generated invalid Go for %s: %w", filepath.Base(outputPath), err),
+			Hint:     templateHint,
+		}
+	}
+	file.Content = formatted
+	return file, nil
+}
+
+// joinPath joins slash-separated, project-relative path elements (as stored in
+// the manifest layout) onto root
+func joinPath(root string, elem ...string) string {
+	parts := []string{root}
+	for _, e := range elem {
+		parts = append(parts, filepath.FromSlash(e))
+	}
+	return filepath.Join(parts...)
+}
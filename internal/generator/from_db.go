@@ -0,0 +1,293 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muazwzxv/ready-go-cli/internal/catalog"
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// FromDBGenerator brings tables from a live database under ready-go: for each
+// table it writes a baseline migration, an entity struct and CRUD queries
+type FromDBGenerator struct {
+	config *config.FromDBConfig
+	nulls  NullStyle
+	env    *Env
+}
+
+// NewFromDBGenerator creates a new FromDBGenerator
+func NewFromDBGenerator(cfg *config.FromDBConfig, nulls NullStyle, env *Env) *FromDBGenerator {
+	return &FromDBGenerator{
+		config: cfg,
+		nulls:  nulls,
+		env:    env,
+	}
+}
+
+// crudQueries is the data rendered by fromdb/queries.sql.tmpl. Conditions and
+// lists are rendered here so that placeholders are numbered for PostgreSQL.
+type crudQueries struct {
+	Entity string
+	Plural string
+	Table  string
+	// Key matches one row by primary key, e.g. "id = ?"; empty without a key
+	Key       string
+	UpdateKey string
+	OrderBy   string
+	// Limit pages List, e.g. "LIMIT ? OFFSET ?"
+	Limit     string
+	Insert    []string
+	Values    []string
+	Set       []string
+	Relations []relationQuery
+}
+
+// relationQuery lists the rows pointing at one parent row through a foreign key
+type relationQuery struct {
+	Name  string
+	Where string
+	Limit string
+}
+
+// Generate reads every table before writing anything, so a missing table or
+// an unparseable definition leaves the project untouched
+func (g *FromDBGenerator) Generate(ctx context.Context) error {
+	g.env.Reporter.Step("🔌 Reading the database catalog...")
+	cat, err := catalog.Open(ctx, g.config.DSN)
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+
+	// The migrations are the source database's DDL, which goose would run
+	// against the project's database
+	if cat.Engine != g.config.Engine {
+		return errs.Validation("import from a "+g.config.Engine+" database, or set engine in "+config.ManifestFile,
+			"the database is %s but the project uses %s", cat.Engine, g.config.Engine)
+	}
+
+	tables := g.config.Tables
+	if len(tables) == 0 {
+		if tables, err = cat.Tables(ctx); err != nil {
+			return err
+		}
+		if len(tables) == 0 {
+			return errs.Validation("", "the database has no tables")
+		}
+	}
+
+	s := &schema.Schema{}
+	statements := map[string][]string{}
+	for _, name := range tables {
+		def, err := cat.Define(ctx, name)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range def.Statements {
+			if err := s.Apply(stmt); err != nil {
+				return fmt.Errorf("failed to parse the definition of %s: %w\n%s", name, err, stmt)
+			}
+		}
+		statements[name] = def.Statements
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ordered := parentsFirst(s, tables)
+	for _, name := range ordered {
+		for _, fk := range s.Table(name).ForeignKeys {
+			g.env.Reporter.Info(fmt.Sprintf("🔗 %s(%s) → %s(%s)", name, strings.Join(fk.Columns, ", "), fk.RefTable, strings.Join(fk.RefColumns, ", ")))
+		}
+	}
+
+	files, err := g.generateFiles(s, ordered, statements, schema.Dialect{Engine: cat.Engine})
+	if err != nil {
+		return err
+	}
+	return g.env.Writer.WriteAll(files)
+}
+
+// generateFiles renders the migration, entity and queries of each table.
+// Migrations are written in the source database's dialect.
+func (g *FromDBGenerator) generateFiles(s *schema.Schema, tables []string, statements map[string][]string, source schema.Dialect) ([]File, error) {
+	migrationsDir := joinPath(g.config.ProjectPath, g.config.Layout.Migrations)
+	existing, err := schema.Migrations(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	var files []File
+	for _, name := range tables {
+		t := s.Table(name)
+		entity := config.NewTableEntityConfig(name, "")
+		entity.Process()
+
+		// Re-running reuses the table's create migration, like add entity does
+		suffix := fmt.Sprintf("_create_%s.sql", name)
+		migrationPath, err := findMigration(migrationsDir, suffix)
+		if err != nil {
+			return nil, err
+		}
+		if migrationPath == "" {
+			version := schema.NextVersion(existing, g.config.Versioning, now)
+			existing = append(existing, schema.Migration{Version: mustVersion(version), Name: "create_" + name})
+			migrationPath = filepath.Join(migrationsDir, version+suffix)
+		}
+		migration, err := g.env.render("migration/migration.sql.tmpl", migrationPath, migrationData{
+			Name: "create_" + name,
+			Up:   statements[name],
+			Down: []string{fmt.Sprintf("DROP TABLE IF EXISTS %s;", source.Quote(name))},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		entityPath := joinPath(g.config.ProjectPath, g.config.Layout.Entity, entity.EntityNameLower+".go")
+		entityFile, err := g.env.renderEntityStruct(entityPath, newEntityStruct(g.config.Layout.EntityPackage(), entity.EntityName, t, g.nulls))
+		if err != nil {
+			return nil, err
+		}
+
+		queriesPath := joinPath(g.config.ProjectPath, g.config.Layout.Queries, entity.EntityNameLower+".sql")
+		queries, err := g.env.render("fromdb/queries.sql.tmpl", queriesPath, g.crudQueries(entity.EntityName, t))
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, migration, entityFile, queries)
+	}
+	return files, nil
+}
+
+// mustVersion parses a version produced by schema.NextVersion
+func mustVersion(version string) int64 {
+	v, _ := strconv.ParseInt(version, 10, 64)
+	return v
+}
+
+// crudQueries derives the standard queries for t in the project's dialect.
+// Columns the database assigns (see schema.Dialect.AutoIncrement) and
+// generated columns are never written, and created_at and
+// updated_at are set by the database.
+func (g *FromDBGenerator) crudQueries(entity string, t *schema.Table) crudQueries {
+	d := schema.Dialect{Engine: g.config.Engine}
+	q := crudQueries{Entity: entity, Plural: schema.GoName(t.Name), Table: d.Quote(t.Name)}
+
+	now := "NOW()"
+	if g.config.Engine == "sqlite" {
+		now = "CURRENT_TIMESTAMP"
+	}
+
+	var param int
+	placeholder := func() string {
+		param++
+		if g.config.Engine == "postgresql" {
+			return fmt.Sprintf("$%d", param)
+		}
+		return "?"
+	}
+	match := func(columns []string) string {
+		conds := make([]string, len(columns))
+		for i, c := range columns {
+			conds[i] = d.Quote(c) + " = " + placeholder()
+		}
+		return strings.Join(conds, " AND ")
+	}
+	limit := func() string {
+		return "LIMIT " + placeholder() + " OFFSET " + placeholder()
+	}
+
+	if len(t.PrimaryKey) > 0 {
+		param = 0
+		q.Key = match(t.PrimaryKey)
+	}
+
+	// The key breaks ties so that pages don't overlap
+	var order []string
+	if t.Column("created_at") != nil {
+		order = append(order, "created_at DESC")
+	}
+	for _, k := range t.PrimaryKey {
+		order = append(order, d.Quote(k))
+	}
+	q.OrderBy = strings.Join(order, ", ")
+
+	param = 0
+	q.Limit = limit()
+
+	for _, fk := range t.ForeignKeys {
+		if len(fk.Columns) != 1 {
+			continue
+		}
+		param = 0
+		where := match(fk.Columns)
+		q.Relations = append(q.Relations, relationQuery{Name: schema.GoName(fk.Columns[0]), Where: where, Limit: limit()})
+	}
+
+	param = 0
+	for _, c := range t.Columns {
+		switch {
+		case d.AutoIncrement(t, c) || c.Generated:
+		case c.Name == "created_at" || c.Name == "updated_at":
+			q.Insert = append(q.Insert, d.Quote(c.Name))
+			q.Values = append(q.Values, now)
+		default:
+			q.Insert = append(q.Insert, d.Quote(c.Name))
+			q.Values = append(q.Values, placeholder())
+		}
+	}
+
+	param = 0
+	for _, c := range t.Columns {
+		switch {
+		case d.AutoIncrement(t, c) || c.Generated || c.Name == "created_at" || slices.Contains(t.PrimaryKey, c.Name):
+		case c.Name == "updated_at":
+			q.Set = append(q.Set, d.Quote(c.Name)+" = "+now)
+		default:
+			q.Set = append(q.Set, d.Quote(c.Name)+" = "+placeholder())
+		}
+	}
+	if len(t.PrimaryKey) > 0 {
+		q.UpdateKey = match(t.PrimaryKey)
+	}
+
+	return q
+}
+
+// parentsFirst orders tables so that those referenced by foreign keys come
+// before the tables referencing them, keeping the given order otherwise
+func parentsFirst(s *schema.Schema, tables []string) []string {
+	var sorted []string
+	visiting := map[string]bool{}
+
+	var visit func(name string)
+	visit = func(name string) {
+		if slices.Contains(sorted, name) || visiting[name] {
+			// Already placed, or a foreign key cycle
+			return
+		}
+		visiting[name] = true
+		for _, fk := range s.Table(name).ForeignKeys {
+			if fk.RefTable != name && slices.Contains(tables, fk.RefTable) {
+				visit(fk.RefTable)
+			}
+		}
+		sorted = append(sorted, name)
+	}
+
+	for _, name := range tables {
+		visit(name)
+	}
+	return sorted
+}
@@ -0,0 +1,1000 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/openapi"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// typesHeader starts the types.go files written by FromOpenAPIGenerator.
+// Files starting with it are regenerated without a conflict.
+const typesHeader = "// Code generated by `ready-go gen from-openapi`"
+
+// FromOpenAPIGenerator writes a handler package per tag of an OpenAPI
+// document: a types.go holding the parameter, request and response types,
+// one handler stub per operation and a route setup function. Re-runs rewrite
+// types.go and the route setup functions, and only add the missing handlers,
+// so handler bodies are never overwritten.
+type FromOpenAPIGenerator struct {
+	config *config.FromOpenAPIConfig
+	env    *Env
+}
+
+// NewFromOpenAPIGenerator creates a new FromOpenAPIGenerator
+func NewFromOpenAPIGenerator(cfg *config.FromOpenAPIConfig, env *Env) *FromOpenAPIGenerator {
+	return &FromOpenAPIGenerator{
+		config: cfg,
+		env:    env,
+	}
+}
+
+// apiPackage is the handler package of one tag
+type apiPackage struct {
+	ModuleName string
+	Layout     config.Layout
+	Router     config.Router
+	Spec       string // file name of the document
+	Package    string // order
+	Tag        string
+	Handlers   []*apiHandler
+
+	// Rendered by fromopenapi/types.go.tmpl
+	Imports []string
+	Types   []*apiType
+
+	// Func is the route setup function, and Setup the file declaring it
+	Func  string
+	Setup *routeSetup
+
+	dir string
+	doc *openapi.Document
+	// declared lists the types declared outside types.go
+	declared map[string]bool
+	// uses lists the identifiers each existing handler's Handle method refers to
+	uses map[string]map[string]bool
+	// used lists the names of the types in types.go
+	used map[string]bool
+	// components maps component names to their Go type, pending the ones still to render
+	components map[string]string
+	pending    []string
+	structs    map[string]bool
+	imports    map[string]bool
+}
+
+// apiHandler is the handler of one operation
+type apiHandler struct {
+	*apiPackage
+
+	Name   string // CreateHandler
+	File   string // create.go
+	Var    string // createHandler
+	Doc    []string
+	Route  string // /v1/orders/:id
+	Method string // POST
+	// Params, Query and Body are the bound types, empty when the operation has none
+	Params, Query, Body string
+	ParamsMessage       string
+	ParamsCode          string
+	Validate            bool
+	ValidateQuery       bool
+	Status              string // StatusCreated, or the code when it has no constant
+	NoContent           bool
+	// Data is the type of the response, or of the SuccessResponse data
+	Data     string
+	Response string // expression passed to c.JSON
+	UsesUtil bool
+	// Errors lists the other error responses, e.g. "404 NOT_FOUND"
+	Errors []string
+	// Exists is set when the handler is declared already
+	Exists bool
+}
+
+// apiType is a type declaration of types.go
+type apiType struct {
+	Name       string
+	Doc        []string
+	Struct     bool
+	Fields     []apiField
+	Underlying string
+	Enum       []apiEnum
+}
+
+type apiField struct {
+	Name     string
+	Type     string
+	Tag      string
+	Doc      []string
+	Embedded bool
+}
+
+type apiEnum struct {
+	Name  string
+	Value string
+}
+
+// envelopes are the util types a document may reference by name
+var envelopes = map[string]string{
+	"SuccessResponse":   "util.SuccessResponse",
+	"PaginatedResponse": "util.PaginatedResponse",
+	"ErrorResponse":     "util.ErrorResponse",
+	"FieldError":        "util.FieldError",
+}
+
+// bindingCodes are the error codes returned by the binding code of the stubs
+var bindingCodes = []string{"INVALID_ID_FORMAT", "INVALID_PARAMS", "INVALID_QUERY", "INVALID_BODY", "VALIDATION_FAILED", "INTERNAL_ERROR"}
+
+// Generate reads the document and writes the handler packages
+func (g *FromOpenAPIGenerator) Generate(ctx context.Context) error {
+	g.env.Reporter.Step("📖 Reading the OpenAPI document...")
+	doc, err := openapi.Load(g.config.Spec)
+	if err != nil {
+		return errs.Validation("pass an OpenAPI 3.0 or 3.1 document in YAML or JSON", "%v", err)
+	}
+	endpoints, err := doc.Endpoints()
+	if err != nil {
+		return errs.Validation("", "%v", err)
+	}
+	if len(endpoints) == 0 {
+		return errs.Validation("", "%s has no operations", g.config.Spec)
+	}
+
+	var packages []*apiPackage
+	byName := map[string]*apiPackage{}
+	for _, e := range endpoints {
+		name, tag := packageName(e)
+		pkg := byName[name]
+		if pkg == nil {
+			pkg, err = g.newPackage(doc, name, tag)
+			if err != nil {
+				return err
+			}
+			byName[name] = pkg
+			packages = append(packages, pkg)
+		}
+		if err := pkg.addHandler(e); err != nil {
+			return &errs.ValidationError{Field: "spec", Message: fmt.Sprintf("%s %s: %v", e.Method, e.Path, err)}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var files []File
+	validation := false
+	for _, pkg := range packages {
+		pkgFiles, err := g.packageFiles(pkg)
+		if err != nil {
+			return err
+		}
+		files = append(files, pkgFiles...)
+		validation = validation || slices.ContainsFunc(pkg.Handlers, func(h *apiHandler) bool { return (h.Validate || h.ValidateQuery) && !h.Exists })
+	}
+	if validation {
+		shared, err := validationFiles(g.env, g.config.ProjectPath, g.config.Layout, g.config.Router)
+		if err != nil {
+			return err
+		}
+		files = append(files, shared...)
+	}
+
+	registrations, err := g.routeFiles(packages)
+	if err != nil {
+		return err
+	}
+	files = append(files, registrations...)
+
+	return g.env.Writer.WriteAll(files)
+}
+
+// newPackage prepares the package of a tag, reading the types declared in
+// its existing files
+func (g *FromOpenAPIGenerator) newPackage(doc *openapi.Document, name, tag string) (*apiPackage, error) {
+	pkg := &apiPackage{
+		ModuleName: g.config.ModuleName,
+		Layout:     g.config.Layout,
+		Router:     g.config.Router,
+		Spec:       filepath.Base(g.config.Spec),
+		Package:    name,
+		Tag:        tag,
+		Func:       "setup" + schema.GoName(name) + "Handlers",
+		dir:        joinPath(g.config.ProjectPath, g.config.Layout.Handlers, name),
+		doc:        doc,
+		declared:   map[string]bool{},
+		uses:       map[string]map[string]bool{},
+		used:       map[string]bool{},
+		components: map[string]string{},
+		structs:    map[string]bool{},
+		imports:    map[string]bool{},
+	}
+
+	entries, err := os.ReadDir(pkg.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", pkg.dir, err)
+	}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		if e.IsDir() || e.Name() == "types.go" || !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(pkg.dir, e.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(pkg.dir, e.Name()), err)
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				if decl.Tok == token.TYPE {
+					for _, spec := range decl.Specs {
+						pkg.declared[spec.(*ast.TypeSpec).Name.Name] = true
+					}
+				}
+			case *ast.FuncDecl:
+				if recv := receiverName(decl); recv != "" && decl.Name.Name == "Handle" && decl.Body != nil {
+					uses := map[string]bool{}
+					ast.Inspect(decl.Body, func(n ast.Node) bool {
+						if id, ok := n.(*ast.Ident); ok {
+							uses[id.Name] = true
+						}
+						return true
+					})
+					pkg.uses[recv] = uses
+				}
+			}
+		}
+	}
+	return pkg, nil
+}
+
+// receiverName returns the type name of a method's receiver, or "" for a function
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// packageName returns the package of an operation, named after its first tag
+// or else after the first path segment: /v1/orders → orders
+func packageName(e openapi.Endpoint) (name, tag string) {
+	if len(e.Operation.Tags) > 0 {
+		tag = e.Operation.Tags[0]
+	} else {
+		for _, seg := range strings.Split(e.Path, "/") {
+			if seg != "" && !strings.HasPrefix(seg, "{") && !isVersion(seg) {
+				tag = seg
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(tag) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	name = b.String()
+	switch {
+	case name == "":
+		name = "api"
+	case unicode.IsDigit(rune(name[0])), token.IsKeyword(name), name == "util", name == "docs":
+		name += "api"
+	}
+	if tag == "" {
+		tag = name
+	}
+	return name, tag
+}
+
+// isVersion reports whether a path segment is an API version, e.g. v1
+func isVersion(seg string) bool {
+	_, err := strconv.Atoi(strings.TrimPrefix(seg, "v"))
+	return strings.HasPrefix(seg, "v") && err == nil
+}
+
+// handlerName names the handler of an operation after its operationId,
+// without the package name: customerCreate → CreateHandler. Operations
+// without an id are named after their method and path.
+func handlerName(pkg string, e openapi.Endpoint) string {
+	name := schema.GoName(e.Operation.OperationID)
+	if e.Operation.OperationID == "" {
+		verbs := map[string]string{"GET": "Get", "POST": "Create", "PUT": "Update", "PATCH": "Patch", "DELETE": "Delete", "HEAD": "Head", "OPTIONS": "Options"}
+		name = verbs[e.Method]
+		var by []string
+		for _, seg := range strings.Split(e.Path, "/") {
+			switch {
+			case seg == "" || isVersion(seg):
+			case strings.HasPrefix(seg, "{"):
+				by = append(by, schema.GoName(strings.Trim(seg, "{}")))
+			default:
+				name += schema.GoName(seg)
+			}
+		}
+		if len(by) > 0 {
+			name += "By" + strings.Join(by, "And")
+		}
+	}
+	if rest, ok := strings.CutPrefix(name, schema.GoName(pkg)); ok && rest != "" && unicode.IsUpper(rune(rest[0])) {
+		name = rest
+	}
+	return name + "Handler"
+}
+
+// addHandler maps an operation to a handler and the types it binds
+func (p *apiPackage) addHandler(e openapi.Endpoint) error {
+	name := handlerName(p.Package, e)
+	base := strings.TrimSuffix(name, "Handler")
+	if slices.ContainsFunc(p.Handlers, func(h *apiHandler) bool { return h.Name == name }) {
+		return fmt.Errorf("another operation of tag %s is also named %s; set distinct operationIds", p.Tag, base)
+	}
+
+	h := &apiHandler{
+		apiPackage: p,
+		Name:       name,
+		File:       snakeCase(base) + ".go",
+		Var:        strings.ToLower(name[:1]) + name[1:],
+		Route:      fiberPath(e.Path),
+		Method:     strings.ToUpper(e.Method),
+		Exists:     p.declared[name],
+	}
+	if h.File == "types.go" {
+		h.File = "types_handler.go"
+	}
+	h.Doc = []string{fmt.Sprintf("%s serves %s %s", name, e.Method, h.Route)}
+	for _, text := range []string{e.Operation.Summary, e.Operation.Description} {
+		if text = strings.TrimSpace(text); text != "" && !slices.Contains(h.Doc, text) {
+			h.Doc = append(h.Doc, "")
+			h.Doc = append(h.Doc, strings.Split(text, "\n")...)
+		}
+	}
+
+	var path, query []*openapi.Parameter
+	for _, param := range e.Parameters {
+		switch param.In {
+		case "path":
+			path = append(path, param)
+		case "query":
+			query = append(query, param)
+		}
+	}
+	var err error
+	if len(path) > 0 {
+		if h.Params, err = p.paramsType(base+"Params", "uri", path); err != nil {
+			return err
+		}
+		h.ParamsMessage, h.ParamsCode = "Invalid path parameters", "INVALID_PARAMS"
+		if len(path) == 1 && path[0].Name == "id" {
+			h.ParamsMessage, h.ParamsCode = "Invalid ID format", "INVALID_ID_FORMAT"
+		}
+	}
+	if len(query) > 0 {
+		if h.Query, err = p.paramsType(base+"Query", "query", query); err != nil {
+			return err
+		}
+		fields := p.Types[slices.IndexFunc(p.Types, func(t *apiType) bool { return t.Name == h.Query })].Fields
+		h.ValidateQuery = slices.ContainsFunc(fields, func(f apiField) bool { return strings.Contains(f.Tag, "validate:") })
+	}
+
+	body, err := p.doc.RequestBody(e.Operation.RequestBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		if s := openapi.JSON(body.Content); s != nil {
+			if h.Body, err = p.typeOf(s, base+"Request"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := p.responses(h, e.Operation.Responses); err != nil {
+		return err
+	}
+	// chi and net/http handlers also respond with util.JSON or util.NoContent
+	h.UsesUtil = h.Params != "" || h.Query != "" || h.Body != "" || strings.Contains(h.Response, "util.") || newHTTPSyntax(p.Router.Framework).NetHTTP()
+
+	p.Handlers = append(p.Handlers, h)
+	return nil
+}
+
+// responses sets the success status and data of h from the first 2xx
+// response, and lists the error responses
+func (p *apiPackage) responses(h *apiHandler, responses map[string]*openapi.Response) error {
+	var success string
+	for _, code := range slices.Sorted(maps.Keys(responses)) {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			continue
+		}
+		resp, err := p.doc.Response(responses[code])
+		if err != nil {
+			return err
+		}
+		switch {
+		case status >= 200 && status < 300 && success == "":
+			success = code
+			h.Status = statusName(status)
+			if err := p.successData(h, openapi.JSON(resp.Content)); err != nil {
+				return err
+			}
+		case status >= 400:
+			codes := errorCodes(openapi.JSON(resp.Content))
+			codes = slices.DeleteFunc(codes, func(c string) bool { return slices.Contains(bindingCodes, c) })
+			if len(codes) > 0 {
+				h.Errors = append(h.Errors, code+" "+strings.Join(codes, ", "))
+			} else if status != http.StatusBadRequest && status != http.StatusUnprocessableEntity && status != http.StatusInternalServerError {
+				h.Errors = append(h.Errors, code+" "+http.StatusText(status))
+			}
+		}
+	}
+	if success == "" {
+		h.Status = "StatusOK"
+		h.NoContent = true
+	}
+	return nil
+}
+
+// successData sets the response of h. SuccessResponse and PaginatedResponse
+// envelopes are recognised, whether referenced or extended with allOf.
+func (p *apiPackage) successData(h *apiHandler, s *openapi.Schema) error {
+	if s == nil {
+		h.NoContent = true
+		return nil
+	}
+
+	envelope, data := "", (*openapi.Schema)(nil)
+	parts := s.AllOf
+	if s.Ref != "" {
+		parts = []*openapi.Schema{s}
+	}
+	for _, part := range parts {
+		if name, err := openapi.SchemaName(part.Ref); err == nil && (name == "SuccessResponse" || name == "PaginatedResponse") {
+			envelope = name
+			continue
+		}
+		for _, prop := range part.Properties {
+			if prop.Name == "data" {
+				data = prop.Schema
+			}
+		}
+	}
+
+	var err error
+	base := strings.TrimSuffix(h.Name, "Handler")
+	switch envelope {
+	case "SuccessResponse":
+		h.Response = "util.SuccessResponse{}"
+		if data != nil {
+			if h.Data, err = p.typeOf(data, base+"Data"); err != nil {
+				return err
+			}
+			h.Response = "util.SuccessResponse{Data: resp}"
+		}
+	case "PaginatedResponse":
+		h.Data = "[]any"
+		if data != nil && data.Items != nil {
+			item, err := p.typeOf(data.Items, base+"Item")
+			if err != nil {
+				return err
+			}
+			h.Data = "[]" + item
+		}
+		h.Response = "util.PaginatedResponse{Data: resp}"
+	default:
+		if h.Data, err = p.typeOf(s, base+"Response"); err != nil {
+			return err
+		}
+		h.Response = "resp"
+	}
+	return nil
+}
+
+// errorCodes returns the code enum of an ErrorResponse extended with allOf
+func errorCodes(s *openapi.Schema) []string {
+	if s == nil {
+		return nil
+	}
+	var codes []string
+	for _, part := range append([]*openapi.Schema{s}, s.AllOf...) {
+		for _, prop := range part.Properties {
+			if prop.Name == "code" {
+				codes = append(codes, prop.Schema.Enum...)
+			}
+		}
+	}
+	return codes
+}
+
+// paramsType declares a struct binding parameters with a uri or query tag.
+// Query parameters get validate tags; numbers and booleans are pointers even
+// when required, as an absent parameter would otherwise bind as zero.
+func (p *apiPackage) paramsType(name, tag string, params []*openapi.Parameter) (string, error) {
+	name = p.typeName(name)
+	t := &apiType{Name: name, Struct: true}
+	for _, param := range params {
+		s := param.Schema
+		if s == nil {
+			s = &openapi.Schema{Type: "string"}
+		}
+		typ, err := p.typeOf(s, name+schema.GoName(param.Name))
+		if err != nil {
+			return "", err
+		}
+		field := apiField{
+			Name: schema.GoName(param.Name),
+			Type: typ,
+			Tag:  fmt.Sprintf("%s:%q", tag, param.Name),
+			Doc:  docLines(param.Description),
+		}
+		if tag == "query" {
+			constraints := s
+			if name, err := openapi.SchemaName(s.Ref); err == nil && p.doc.Components.Schemas[name] != nil {
+				constraints = p.doc.Components.Schemas[name]
+			}
+			scalar := constraints.Type == "integer" || constraints.Type == "number" || constraints.Type == "boolean"
+			if (!param.Required || scalar) && pointable(typ) {
+				field.Type = "*" + typ
+			}
+			if rules := validateRules(constraints, field.Type, param.Required); rules != "" {
+				field.Tag += fmt.Sprintf(" validate:%q", rules)
+			}
+		}
+		t.Fields = append(t.Fields, field)
+	}
+	p.add(t)
+	return name, nil
+}
+
+// typeOf returns the Go type of s, declaring inline objects as name
+func (p *apiPackage) typeOf(s *openapi.Schema, name string) (string, error) {
+	if s == nil {
+		return "any", nil
+	}
+	if s.Ref != "" {
+		return p.component(s.Ref)
+	}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return p.typeOf(s.AllOf[0], name)
+	}
+	if len(s.AllOf) > 0 || len(s.Properties) > 0 {
+		return p.object(s, p.typeName(name))
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return "any", nil
+	}
+
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			p.imports["time"] = true
+			return "time.Time", nil
+		case "byte", "binary":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "integer":
+		switch s.Format {
+		case "int32":
+			return "int32", nil
+		case "int64":
+			return "int64", nil
+		}
+		return "int", nil
+	case "number":
+		if s.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		item, err := p.typeOf(s.Items, name+"Item")
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object":
+		if s.AdditionalProperties != nil {
+			value, err := p.typeOf(s.AdditionalProperties, name+"Value")
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + value, nil
+		}
+		return "map[string]any", nil
+	}
+	return "any", nil
+}
+
+// component returns the Go type of a referenced component, declaring it
+// when it is first used
+func (p *apiPackage) component(ref string) (string, error) {
+	name, err := openapi.SchemaName(ref)
+	if err != nil {
+		return "", err
+	}
+	if envelope, ok := envelopes[name]; ok {
+		return envelope, nil
+	}
+	if typ, ok := p.components[name]; ok {
+		return typ, nil
+	}
+	s := p.doc.Components.Schemas[name]
+	if s == nil {
+		return "", fmt.Errorf("reference %s not found", ref)
+	}
+
+	typ := schema.GoName(name)
+	if p.declared[typ] {
+		// Taken over by the package
+		p.components[name] = typ
+		return typ, nil
+	}
+	typ = p.typeName(typ)
+	p.components[name] = typ
+	p.pending = append(p.pending, name)
+	return typ, nil
+}
+
+// declareComponents declares the components referenced so far, including
+// the ones they reference in turn
+func (p *apiPackage) declareComponents() error {
+	for len(p.pending) > 0 {
+		name := p.pending[0]
+		p.pending = p.pending[1:]
+		s, typ := p.doc.Components.Schemas[name], p.components[name]
+
+		switch {
+		case s.Ref == "" && (len(s.AllOf) > 0 || len(s.Properties) > 0):
+			if _, err := p.object(s, typ); err != nil {
+				return fmt.Errorf("schema %s: %w", name, err)
+			}
+		default:
+			underlying, err := p.typeOf(s, typ)
+			if err != nil {
+				return fmt.Errorf("schema %s: %w", name, err)
+			}
+			t := &apiType{Name: typ, Doc: docLines(s.Description), Underlying: underlying}
+			if underlying == "string" {
+				for _, v := range s.Enum {
+					t.Enum = append(t.Enum, apiEnum{Name: typ + schema.GoName(v), Value: v})
+				}
+				t.Enum = slices.CompactFunc(t.Enum, func(a, b apiEnum) bool { return a.Name == b.Name })
+			}
+			p.add(t)
+		}
+	}
+	return nil
+}
+
+// object declares a struct named name for an object schema. References in
+// allOf become embedded fields, so their properties are promoted.
+func (p *apiPackage) object(s *openapi.Schema, name string) (string, error) {
+	t := &apiType{Name: name, Doc: docLines(s.Description), Struct: true}
+	p.structs[name] = true
+	parts := append([]*openapi.Schema{s}, s.AllOf...)
+	for _, part := range parts {
+		if part.Ref != "" {
+			typ, err := p.component(part.Ref)
+			if err != nil {
+				return "", err
+			}
+			t.Fields = append(t.Fields, apiField{Type: typ, Embedded: true})
+			continue
+		}
+		if part != s && len(part.AllOf) > 0 {
+			typ, err := p.typeOf(part, name+"Part")
+			if err != nil {
+				return "", err
+			}
+			t.Fields = append(t.Fields, apiField{Type: typ, Embedded: true})
+			continue
+		}
+		for _, prop := range part.Properties {
+			field := schema.GoName(prop.Name)
+			typ, err := p.typeOf(prop.Schema, name+field)
+			if err != nil {
+				return "", err
+			}
+			required := slices.Contains(part.Required, prop.Name) || slices.Contains(s.Required, prop.Name)
+			if (!required || prop.Schema.Nullable) && pointable(typ) {
+				typ = "*" + typ
+			}
+			tag := fmt.Sprintf("json:%q", prop.Name)
+			if !required {
+				tag = fmt.Sprintf("json:%q", prop.Name+",omitempty")
+			}
+			constraints := prop.Schema
+			if name, err := openapi.SchemaName(constraints.Ref); err == nil && p.doc.Components.Schemas[name] != nil {
+				constraints = p.doc.Components.Schemas[name]
+			}
+			if rules := validateRules(constraints, typ, required); rules != "" {
+				tag += fmt.Sprintf(" validate:%q", rules)
+			}
+			t.Fields = append(t.Fields, apiField{Name: field, Type: typ, Tag: tag, Doc: docLines(prop.Schema.Description)})
+		}
+	}
+	p.add(t)
+	return name, nil
+}
+
+// validateRules derives the validate tag of a property from its schema.
+// Like the DTOs of `add dto`, numbers and booleans are never required, as
+// their zero value is valid.
+func validateRules(s *openapi.Schema, typ string, required bool) string {
+	var rules []string
+	base := strings.TrimPrefix(typ, "*")
+	text := s.Type == "string" && base != "time.Time" && base != "[]byte"
+	number := s.Type == "integer" || s.Type == "number"
+	switch {
+	case required && (!number && s.Type != "boolean" || strings.HasPrefix(typ, "*")):
+		rules = append(rules, "required")
+	case !required:
+		rules = append(rules, "omitempty")
+	}
+
+	if text {
+		if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(v string) bool { return v == "" || strings.ContainsAny(v, " '\"") }) {
+			rules = append(rules, "oneof="+strings.Join(s.Enum, " "))
+		}
+		if s.MinLength != nil && *s.MinLength > 0 {
+			rules = append(rules, "min="+strconv.Itoa(*s.MinLength))
+		}
+		if s.MaxLength != nil {
+			rules = append(rules, "max="+strconv.Itoa(*s.MaxLength))
+		}
+		switch s.Format {
+		case "email":
+			rules = append(rules, "email")
+		case "uri", "url":
+			rules = append(rules, "url")
+		case "uuid":
+			rules = append(rules, "uuid")
+		}
+	}
+	if number {
+		if s.Minimum != nil {
+			rules = append(rules, "gte="+strconv.FormatFloat(*s.Minimum, 'f', -1, 64))
+		}
+		if s.Maximum != nil {
+			rules = append(rules, "lte="+strconv.FormatFloat(*s.Maximum, 'f', -1, 64))
+		}
+	}
+	if item, ok := strings.CutPrefix(base, "[]"); ok && !slices.Contains(goBasicTypes, item) {
+		// Validate the elements too
+		rules = append(rules, "dive")
+	}
+
+	if len(rules) == 1 && rules[0] == "omitempty" {
+		return ""
+	}
+	return strings.Join(rules, ",")
+}
+
+// goBasicTypes are the Go types typeOf maps scalars to
+var goBasicTypes = []string{"string", "bool", "int", "int32", "int64", "float32", "float64", "byte", "time.Time", "any"}
+
+// pointable reports whether optional values of typ are pointers; slices,
+// maps and any already have a nil value
+func pointable(typ string) bool {
+	return typ != "any" && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") && !strings.HasPrefix(typ, "*")
+}
+
+// typeName returns name, numbered if types.go or the package declares it already
+func (p *apiPackage) typeName(name string) string {
+	unique := name
+	for i := 2; p.used[unique] || p.declared[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	p.used[unique] = true
+	return unique
+}
+
+// add declares t in types.go
+func (p *apiPackage) add(t *apiType) {
+	for _, f := range t.Fields {
+		if strings.Contains(f.Type, "time.Time") {
+			p.imports["time"] = true
+		}
+	}
+	p.Types = append(p.Types, t)
+}
+
+// packageFiles renders types.go and the missing handlers of pkg
+func (g *FromOpenAPIGenerator) packageFiles(pkg *apiPackage) ([]File, error) {
+	if err := pkg.declareComponents(); err != nil {
+		return nil, &errs.ValidationError{Field: "spec", Message: err.Error()}
+	}
+	for _, h := range pkg.Handlers {
+		h.Validate = pkg.structs[h.Body] || pkg.declared[h.Body]
+	}
+	slices.SortStableFunc(pkg.Types, func(a, b *apiType) int { return strings.Compare(a.Name, b.Name) })
+	pkg.Imports = slices.Sorted(maps.Keys(pkg.imports))
+
+	var files []File
+	if len(pkg.Types) > 0 {
+		typesPath := filepath.Join(pkg.dir, "types.go")
+		file, err := g.env.renderGo("fromopenapi/types.go.tmpl", typesPath, pkg)
+		if err != nil {
+			return nil, err
+		}
+		if existing, err := os.ReadFile(typesPath); err == nil && bytes.HasPrefix(existing, []byte(typesHeader)) {
+			file.Edit = true
+		}
+		files = append(files, file)
+	}
+
+	for _, h := range pkg.Handlers {
+		if h.Exists {
+			g.warnUnbound(pkg, h)
+			continue
+		}
+		file, err := g.env.renderGo("fromopenapi/handler.go.tmpl", filepath.Join(pkg.dir, h.File), h)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// warnUnbound reports the parameter and body types an existing handler does
+// not refer to, such as the query parameters an operation gained since the
+// handler was generated. Its body is the user's, so it is not rewritten.
+func (g *FromOpenAPIGenerator) warnUnbound(pkg *apiPackage, h *apiHandler) {
+	uses, ok := pkg.uses[h.Name]
+	if !ok {
+		return
+	}
+	var missing []string
+	for _, bound := range []struct{ typ, what string }{
+		{h.Params, "path parameters"},
+		{h.Query, "query parameters"},
+		{h.Body, "request body"},
+	} {
+		if bound.typ != "" && !uses[bound.typ] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", bound.typ, bound.what))
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	g.env.Reporter.Warn(fmt.Sprintf("%s.%s does not bind %s of %s %s", pkg.Package, h.Name, strings.Join(missing, ", "), h.Method, h.Route))
+	g.env.Reporter.NextStep(fmt.Sprintf("Bind them in %s/%s/%s, or delete the file and re-run to regenerate the handler", g.config.Layout.Handlers, pkg.Package, h.File))
+}
+
+// routeFiles registers the routes of each package in its own setup
+// function, rewriting the functions written by earlier runs
+func (g *FromOpenAPIGenerator) routeFiles(packages []*apiPackage) ([]File, error) {
+	setup, err := loadRouteSetup(g.config.ProjectPath, g.config.Router)
+	if err != nil {
+		g.env.Reporter.Warn(fmt.Sprintf("routes were not registered: %v", err))
+		g.env.Reporter.NextStep(fmt.Sprintf("Register the handlers in %s by hand", g.config.Layout.Handlers))
+		return nil, nil
+	}
+
+	var registration *File
+	for _, pkg := range packages {
+		pkg.Setup = setup
+		imports := append([]string{"log/slog", g.config.ModuleName + "/" + g.config.Layout.Handlers + "/" + pkg.Package},
+			setup.http.routeImports(g.config.ModuleName+"/"+g.config.Layout.Handlers+"/util")...)
+		existing := setup.lookup(pkg.Func)
+		if existing != nil && (existing.Doc == nil || !strings.Contains(existing.Doc.Text(), "gen from-openapi")) {
+			// Written by hand or by add entity: only report the handlers it misses
+			if slices.ContainsFunc(pkg.Handlers, func(h *apiHandler) bool { return !h.Exists }) {
+				g.env.Reporter.Warn(fmt.Sprintf("%s in %s was not written by gen from-openapi and is left alone", pkg.Func, g.config.Router.Setup))
+				g.env.Reporter.NextStep(fmt.Sprintf("Register the new %s handlers in %s by hand", pkg.Package, pkg.Func))
+			}
+			continue
+		}
+
+		decl, err := g.env.Templates.Render("fromopenapi/routes.go.tmpl", pkg)
+		if err != nil {
+			return nil, err
+		}
+		var file File
+		if existing != nil {
+			g.warnRemoved(setup, existing, pkg)
+			file, err = setup.replace(pkg.Func, decl, imports...)
+		} else {
+			file, err = setup.register(pkg.Func, decl, imports...)
+		}
+		if err != nil {
+			return nil, &errs.TemplateError{Template: "fromopenapi/routes.go.tmpl", Err: err, Hint: templateHint}
+		}
+		registration = &file
+	}
+	if registration == nil {
+		return nil, nil
+	}
+	return []File{*registration}, nil
+}
+
+// warnRemoved reports the handlers an earlier run registered that no longer
+// match an operation; their routes are dropped, but their files are kept
+func (g *FromOpenAPIGenerator) warnRemoved(setup *routeSetup, fn *ast.FuncDecl, pkg *apiPackage) {
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == pkg.Package && strings.HasSuffix(sel.Sel.Name, "Handler") &&
+			!slices.ContainsFunc(pkg.Handlers, func(h *apiHandler) bool { return h.Name == sel.Sel.Name }) {
+			g.env.Reporter.Warn(fmt.Sprintf("%s.%s no longer matches an operation of %s; its route was removed", pkg.Package, sel.Sel.Name, pkg.Spec))
+			g.env.Reporter.NextStep(fmt.Sprintf("Delete %s.%s from %s/%s once it is no longer needed", pkg.Package, sel.Sel.Name, g.config.Layout.Handlers, pkg.Package))
+		}
+		return true
+	})
+}
+
+// fiberPath converts OpenAPI parameters to Fiber ones: /orders/{id} → /orders/:id
+func fiberPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			segments[i] = ":" + strings.Trim(seg, "{}")
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// statusName returns the name of the constant of a status code in net/http
+// and Fiber, e.g. StatusCreated
+func statusName(code int) string {
+	text := http.StatusText(code)
+	if text == "" {
+		return strconv.Itoa(code)
+	}
+	return "Status" + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+		return -1
+	}, text)
+}
+
+// snakeCase converts a Go name to snake_case: GetByID → get_by_id
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// docLines splits a description into comment lines
+func docLines(text string) []string {
+	if text = strings.TrimSpace(text); text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
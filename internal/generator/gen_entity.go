@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// StructGenerator writes an entity struct for a table defined by the
+// project's migrations
+type StructGenerator struct {
+	config *config.EntityConfig
+	nulls  NullStyle
+	env    *Env
+}
+
+// NewStructGenerator creates a new StructGenerator
+func NewStructGenerator(cfg *config.EntityConfig, nulls NullStyle, env *Env) *StructGenerator {
+	return &StructGenerator{
+		config: cfg,
+		nulls:  nulls,
+		env:    env,
+	}
+}
+
+// Generate replays the migrations and renders the struct for the configured table
+func (g *StructGenerator) Generate(ctx context.Context) error {
+	g.env.Reporter.Step("🔍 Reading migrations...")
+	migrationsDir := joinPath(g.config.ProjectPath, g.config.Layout.Migrations)
+	s, err := schema.Load(migrationsDir)
+	if err != nil {
+		return errs.Validation("fix the migration, or generate the struct by hand", "failed to replay migrations in %s: %v", g.config.Layout.Migrations, err)
+	}
+
+	table := s.Table(g.config.TableName)
+	if table == nil {
+		hint := "no tables are created by the migrations"
+		if names := s.TableNames(); len(names) > 0 {
+			hint = "known tables: " + strings.Join(names, ", ")
+		}
+		return &errs.ValidationError{Field: "table", Message: fmt.Sprintf("table %s is not created by any migration in %s", g.config.TableName, g.config.Layout.Migrations), Hint: hint}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	outputPath := joinPath(g.config.ProjectPath, g.config.Layout.Entity, g.config.EntityNameLower+".go")
+	file, err := g.env.renderEntityStruct(outputPath, newEntityStruct(g.config.Layout.EntityPackage(), g.config.EntityName, table, g.nulls))
+	if err != nil {
+		return fmt.Errorf("generate entity file: %w", err)
+	}
+
+	return g.env.Writer.WriteAll([]File{file})
+}
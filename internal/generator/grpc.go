@@ -0,0 +1,231 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+)
+
+// GRPCServiceGenerator adds a gRPC service: its protobuf definition, an
+// implementation stub and its registration. The first service of a project
+// without a gRPC server also adds the server, its command, the buf config
+// and the make targets.
+type GRPCServiceGenerator struct {
+	config *config.GRPCServiceConfig
+	env    *Env
+}
+
+// NewGRPCServiceGenerator creates a new GRPCServiceGenerator
+func NewGRPCServiceGenerator(cfg *config.GRPCServiceConfig, env *Env) *GRPCServiceGenerator {
+	return &GRPCServiceGenerator{
+		config: cfg,
+		env:    env,
+	}
+}
+
+// grpcData is the data rendered by the grpc templates
+type grpcData struct {
+	ModuleName string
+	Layout     config.Layout
+	GRPC       *config.GRPC
+	GRPCPort   string
+	// Service is the service name without its Service suffix: Orders
+	Service string
+	// Package is the proto package (without .v1) and the Go package of the
+	// implementation: orders
+	Package string
+	// Resource is the singular of Service (Order), ResourceLower its words in
+	// lower case (order) and ResourceField its proto field name (order)
+	Resource      string
+	ResourceLower string
+	ResourceField string
+	Setup         *routeSetup
+}
+
+// grpcServerTemplates lists the gRPC server files shared by `new --transport
+// http,grpc` and the first `add grpc-service`
+func grpcServerTemplates(projectPath string, layout config.Layout, grpc *config.GRPC) []struct {
+	template string
+	output   string
+} {
+	return []struct {
+		template string
+		output   string
+	}{
+		{"grpc/server.go.tmpl", joinPath(projectPath, grpc.Server, "server.go")},
+		{"grpc/interceptors.go.tmpl", joinPath(projectPath, grpc.Server, "interceptors.go")},
+		{"grpc/services.go.tmpl", joinPath(projectPath, grpc.Setup)},
+		{"grpc/main.go.tmpl", joinPath(projectPath, layout.GRPCCmd(), "main.go")},
+		{"grpc/buf.yaml.tmpl", filepath.Join(projectPath, "buf.yaml")},
+		{"grpc/buf.gen.yaml.tmpl", filepath.Join(projectPath, "buf.gen.yaml")},
+	}
+}
+
+// Generate renders the service and registers it with the gRPC server
+func (g *GRPCServiceGenerator) Generate(ctx context.Context) error {
+	snake := snakeCase(g.config.Resource)
+	data := grpcData{
+		ModuleName:    g.config.ModuleName,
+		Layout:        g.config.Layout,
+		GRPC:          g.config.GRPC,
+		GRPCPort:      g.config.GRPCPort,
+		Service:       g.config.ServiceName,
+		Package:       g.config.Package,
+		Resource:      g.config.Resource,
+		ResourceLower: strings.ReplaceAll(snake, "_", " "),
+		ResourceField: snake,
+	}
+
+	var files []File
+	if data.GRPC == nil {
+		g.env.Reporter.Step("🛰️  Adding the gRPC server...")
+		data.GRPC = config.DefaultGRPC()
+		server, err := g.serverFiles(data)
+		if err != nil {
+			return err
+		}
+		files = server
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	protoPath := joinPath(g.config.ProjectPath, data.GRPC.Proto, data.Package, "v1", data.Package+".proto")
+	proto, err := g.env.render("grpc/service.proto.tmpl", protoPath, data)
+	if err != nil {
+		return fmt.Errorf("generate proto: %w", err)
+	}
+	service, err := g.env.renderGo("grpc/service.go.tmpl", joinPath(g.config.ProjectPath, data.GRPC.Server, data.Package, "service.go"), data)
+	if err != nil {
+		return fmt.Errorf("generate service: %w", err)
+	}
+	files = append(files, proto, service)
+
+	registration, err := g.registration(data, files)
+	if err != nil {
+		return err
+	}
+	if registration != nil {
+		// The registration replaces services.go when it is written along with the server
+		files = slices.DeleteFunc(files, func(f File) bool { return f.Path == registration.Path })
+		files = append(files, *registration)
+	}
+
+	return g.env.Writer.WriteAll(files)
+}
+
+// registration adds register<Service>Service to the registration function,
+// reading it from pending when services.go is about to be written. It
+// returns nil when the service is registered already, or when the setup
+// can't be edited, which is reported as a next step.
+func (g *GRPCServiceGenerator) registration(data grpcData, pending []File) (*File, error) {
+	setupPath := joinPath(g.config.ProjectPath, data.GRPC.Setup)
+	var src []byte
+	if i := slices.IndexFunc(pending, func(f File) bool { return f.Path == setupPath }); i >= 0 {
+		src = pending[i].Content
+	} else {
+		// A missing or unreadable file is reported below
+		src, _ = os.ReadFile(setupPath)
+	}
+
+	fn := "register" + data.Service + "Service"
+	manual := fmt.Sprintf("Call %sv1.Register%sServiceServer from %s in %s", data.Package, data.Service, data.GRPC.Func, data.GRPC.Setup)
+	if src == nil {
+		g.env.Reporter.Warn(fmt.Sprintf("%s not found; the service was not registered", data.GRPC.Setup))
+		g.env.Reporter.NextStep(manual)
+		return nil, nil
+	}
+	setup, err := loadServiceSetup(setupPath, src, data.GRPC)
+	if err != nil {
+		g.env.Reporter.Warn(fmt.Sprintf("the service was not registered: %v", err))
+		g.env.Reporter.NextStep(manual)
+		return nil, nil
+	}
+	if setup.defines(fn) {
+		return nil, nil
+	}
+
+	data.Setup = setup
+	decl, err := g.env.Templates.Render("grpc/register.go.tmpl", data)
+	if err != nil {
+		return nil, err
+	}
+	file, err := setup.register(fn, decl,
+		"log/slog",
+		g.config.ModuleName+"/"+data.GRPC.Server+"/"+data.Package,
+		data.Package+"v1 "+g.config.ModuleName+"/"+data.GRPC.Gen+"/"+data.Package+"/v1",
+	)
+	if err != nil {
+		return nil, &errs.TemplateError{Template: "grpc/register.go.tmpl", Err: err, Hint: templateHint}
+	}
+	return &file, nil
+}
+
+// serverFiles adds the gRPC server to a project created without one: the
+// server package and command (skipping files that exist), the make targets,
+// GRPC_PORT in the config and the grpc section of the manifest
+func (g *GRPCServiceGenerator) serverFiles(data grpcData) ([]File, error) {
+	var files []File
+	for _, t := range grpcServerTemplates(g.config.ProjectPath, g.config.Layout, data.GRPC) {
+		if _, err := os.Stat(t.output); err == nil {
+			continue
+		}
+		file, err := g.env.render(t.template, t.output, data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	makefile := joinPath(g.config.ProjectPath, "Makefile")
+	if existing, err := os.ReadFile(makefile); err != nil || !protoTarget.Match(existing) {
+		content, err := g.env.Templates.Render("grpc/Makefile.tmpl", data)
+		if err != nil {
+			return nil, err
+		}
+		edit, err := appendFile(makefile, content)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, edit...)
+	}
+
+	port := []configField{{Group: "gRPC", Name: "GRPCPort", Type: "string", Env: "GRPC_PORT", Default: data.GRPCPort}}
+	patch, err := configPatch(joinPath(g.config.ProjectPath, "internal", "config", "config.go"), port)
+	if err != nil {
+		g.env.Reporter.Warn(err.Error())
+		g.env.Reporter.NextStep(fmt.Sprintf("Add GRPCPort to config.Config, loaded from GRPC_PORT (default %s)", data.GRPCPort))
+	}
+	files = append(files, patch...)
+
+	envExample, err := envExamplePatch(joinPath(g.config.ProjectPath, ".env.example"), port)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, envExample...)
+
+	m := g.config.Manifest
+	m.GRPC = data.GRPC
+	if m.Module == "" {
+		m.Module = g.config.ModuleName
+	}
+	manifest, err := m.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, File{Path: joinPath(g.config.ProjectPath, config.ManifestFile), Content: manifest, Edit: true})
+
+	g.env.Reporter.NextStep("go mod tidy         # The gRPC server uses google.golang.org/grpc")
+	g.env.Reporter.NextStep(fmt.Sprintf("make run-grpc       # %s serves gRPC; %s still serves HTTP only", g.config.Layout.GRPCCmd(), g.config.Layout.API))
+	return files, nil
+}
+
+var protoTarget = regexp.MustCompile(`(?m)^proto\s*:[^=]`)
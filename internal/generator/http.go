@@ -0,0 +1,245 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+)
+
+// httpSyntax writes the router-specific parts of handlers, routes and the
+// util package. Templates get it from the router function:
+//
+//	{{$h := router .Router.Framework}}
+//	func (h *ListHandler) Handle({{$h.Handle}}) error {
+//
+// Fiber and Echo handlers take the framework's context; chi and net/http
+// handlers take (w, r) and return an error, adapted by util.Handler.
+type httpSyntax struct {
+	fw string
+}
+
+// newHTTPSyntax returns the syntax of framework, Fiber when it is empty
+func newHTTPSyntax(framework string) httpSyntax {
+	if framework == "" {
+		framework = config.FrameworkFiber
+	}
+	return httpSyntax{fw: framework}
+}
+
+// Framework is the router framework: fiber, echo, chi or stdlib
+func (h httpSyntax) Framework() string {
+	return h.fw
+}
+
+// Name is the framework as written in prose: Fiber, Echo, chi or net/http
+func (h httpSyntax) Name() string {
+	switch h.fw {
+	case config.FrameworkFiber:
+		return "Fiber"
+	case config.FrameworkEcho:
+		return "Echo"
+	case config.FrameworkChi:
+		return "chi"
+	}
+	return "net/http"
+}
+
+// NetHTTP reports whether handlers are net/http handlers adapted by util.Handler
+func (h httpSyntax) NetHTTP() bool {
+	return h.fw == config.FrameworkChi || h.fw == config.FrameworkStdlib
+}
+
+// StdImports are the standard library imports of a file declaring handlers
+func (h httpSyntax) StdImports() []string {
+	if h.fw == config.FrameworkFiber {
+		return nil
+	}
+	return []string{"net/http"}
+}
+
+// Imports are the third-party imports of a file declaring handlers
+func (h httpSyntax) Imports() []string {
+	switch h.fw {
+	case config.FrameworkFiber:
+		return []string{"github.com/gofiber/fiber/v3"}
+	case config.FrameworkEcho:
+		return []string{"github.com/labstack/echo/v4"}
+	}
+	return nil
+}
+
+// RouterType is the type of the router SetupHandler registers routes with
+func (h httpSyntax) RouterType() string {
+	switch h.fw {
+	case config.FrameworkFiber:
+		return "*fiber.App"
+	case config.FrameworkEcho:
+		return "*echo.Echo"
+	case config.FrameworkChi:
+		return "chi.Router"
+	}
+	return "*http.ServeMux"
+}
+
+// MiddlewareType is the type of a middleware; chi and net/http share theirs
+func (h httpSyntax) MiddlewareType() string {
+	switch h.fw {
+	case config.FrameworkFiber:
+		return "fiber.Handler"
+	case config.FrameworkEcho:
+		return "echo.MiddlewareFunc"
+	}
+	return "func(http.Handler) http.Handler"
+}
+
+// Handle is the parameter list of a handler
+func (h httpSyntax) Handle() string {
+	switch h.fw {
+	case config.FrameworkFiber:
+		return "c fiber.Ctx"
+	case config.FrameworkEcho:
+		return "c echo.Context"
+	}
+	return "w http.ResponseWriter, r *http.Request"
+}
+
+// Param is the request parameter of the util helpers reading the request
+func (h httpSyntax) Param() string {
+	if h.NetHTTP() {
+		return "r *http.Request"
+	}
+	return h.Handle()
+}
+
+// Req names the request, passed to the util helpers taking Param
+func (h httpSyntax) Req() string {
+	if h.NetHTTP() {
+		return "r"
+	}
+	return "c"
+}
+
+// Res names what responses are written to, passed to util.HandleError
+func (h httpSyntax) Res() string {
+	if h.NetHTTP() {
+		return "w"
+	}
+	return "c"
+}
+
+// Ctx is the request's context.Context
+func (h httpSyntax) Ctx() string {
+	switch h.fw {
+	case config.FrameworkFiber:
+		return "c"
+	case config.FrameworkEcho:
+		return "c.Request().Context()"
+	}
+	return "r.Context()"
+}
+
+// Status returns the constant of a status, e.g. StatusCreated →
+// fiber.StatusCreated. Numeric codes are returned as they are.
+func (h httpSyntax) Status(name string) string {
+	if !strings.HasPrefix(name, "Status") {
+		return name
+	}
+	if h.fw == config.FrameworkFiber {
+		return "fiber." + name
+	}
+	return "http." + name
+}
+
+// Bind returns the call binding the URI, Query or Body of the request to
+// target, e.g. c.Bind().URI(&params)
+func (h httpSyntax) Bind(kind, target string) string {
+	if h.fw == config.FrameworkFiber {
+		return fmt.Sprintf("c.Bind().%s(%s)", kind, target)
+	}
+	return fmt.Sprintf("util.Bind%s(%s, %s)", kind, h.Req(), target)
+}
+
+// JSON returns the call responding value as JSON with status
+func (h httpSyntax) JSON(status, value string) string {
+	switch h.fw {
+	case config.FrameworkFiber:
+		if status == "StatusOK" {
+			return fmt.Sprintf("c.JSON(%s)", value)
+		}
+		return fmt.Sprintf("c.Status(%s).JSON(%s)", h.Status(status), value)
+	case config.FrameworkEcho:
+		return fmt.Sprintf("c.JSON(%s, %s)", h.Status(status), value)
+	}
+	return fmt.Sprintf("util.JSON(w, %s, %s)", h.Status(status), value)
+}
+
+// NoContent returns the call responding status without a body, e.g.
+// StatusNoContent
+func (h httpSyntax) NoContent(status string) string {
+	switch h.fw {
+	case config.FrameworkFiber:
+		return fmt.Sprintf("c.SendStatus(%s)", h.Status(status))
+	case config.FrameworkEcho:
+		return fmt.Sprintf("c.NoContent(%s)", h.Status(status))
+	}
+	return fmt.Sprintf("util.NoContent(w, %s)", h.Status(status))
+}
+
+// Query returns the value of the query parameter named by the expression name
+func (h httpSyntax) Query(name string) string {
+	switch h.fw {
+	case config.FrameworkFiber:
+		return fmt.Sprintf("c.Query(%s)", name)
+	case config.FrameworkEcho:
+		return fmt.Sprintf("c.QueryParam(%s)", name)
+	}
+	return fmt.Sprintf("r.URL.Query().Get(%s)", name)
+}
+
+// Queries returns the query parameters, keyed by name
+func (h httpSyntax) Queries() string {
+	switch h.fw {
+	case config.FrameworkFiber:
+		return "c.Queries()"
+	case config.FrameworkEcho:
+		return "c.QueryParams()"
+	}
+	return "r.URL.Query()"
+}
+
+// Route returns the registration of handler for method and path, a path in
+// Fiber syntax (/v1/orders/:id) that is converted for chi and net/http
+func (h httpSyntax) Route(router, method, path, handler string) string {
+	method = strings.ToUpper(method)
+	switch h.fw {
+	case config.FrameworkFiber:
+		return fmt.Sprintf("%s.%s(%q, %s)", router, method[:1]+strings.ToLower(method[1:]), path, handler)
+	case config.FrameworkEcho:
+		return fmt.Sprintf("%s.%s(%q, %s)", router, method, path, handler)
+	case config.FrameworkChi:
+		return fmt.Sprintf("%s.%s(%q, util.Handler(%s))", router, method[:1]+strings.ToLower(method[1:]), patternPath(path), handler)
+	}
+	return fmt.Sprintf("%s.Handle(%q, util.Handler(%s))", router, method+" "+patternPath(path), handler)
+}
+
+// routeImports are the imports of routes registered with Route, besides
+// the handler packages; util is the import path of the util package
+func (h httpSyntax) routeImports(util string) []string {
+	if h.NetHTTP() {
+		return []string{util}
+	}
+	return nil
+}
+
+// patternPath converts Fiber parameters to the {name} wildcards of chi and
+// net/http patterns: /orders/:id → /orders/{id}
+func patternPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if name, ok := strings.CutPrefix(seg, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
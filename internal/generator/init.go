@@ -0,0 +1,172 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/detect"
+)
+
+// InitGenerator adopts an existing Go service: it writes the project manifest,
+// creates missing database directories and adds the requested pieces
+type InitGenerator struct {
+	project *detect.Project
+	pieces  []detect.Piece
+	env     *Env
+}
+
+// NewInitGenerator creates an InitGenerator for an inspected project
+func NewInitGenerator(project *detect.Project, pieces []detect.Piece, env *Env) *InitGenerator {
+	return &InitGenerator{
+		project: project,
+		pieces:  pieces,
+		env:     env,
+	}
+}
+
+// Manifest returns the manifest describing the inspected project, falling
+// back to ready-go defaults for anything that wasn't found
+func (g *InitGenerator) Manifest() *config.Manifest {
+	p := g.project
+	m := config.DefaultManifest(p.Module)
+
+	if p.Engine != "" {
+		m.Engine = p.Engine
+	}
+	if p.Migrations != "" {
+		m.Layout.Migrations = p.Migrations
+	}
+	if p.Queries != "" {
+		m.Layout.Queries = p.Queries
+	}
+	if p.Migrations != "" {
+		// Mixed or absent schemes leave versioning to follow the latest file
+		m.Migrations.Versioning = p.Versioning
+	}
+	if p.Router != "" {
+		m.Router.Framework = p.Router
+	}
+	if dir := path.Dir(p.ServerMain); p.ServerMain != "" && dir != "." {
+		m.Layout.API = dir
+	}
+	if p.RouterSetup != "" && p.HandlersDir() != "." {
+		m.Layout.Handlers = p.HandlersDir()
+		m.Router.Setup = p.RouterSetup
+		m.Router.Func = p.RouterFunc
+	} else {
+		m.Router.Setup = m.Layout.Handlers + "/handler.go"
+	}
+
+	return m
+}
+
+// Generate writes the manifest and the requested pieces
+func (g *InitGenerator) Generate(ctx context.Context) error {
+	manifest := g.Manifest()
+	cfg := g.projectConfig(manifest)
+
+	content, err := manifest.Marshal()
+	if err != nil {
+		return err
+	}
+	files := []File{{Path: g.path(config.ManifestFile), Content: content}}
+
+	// add entity needs both database directories to exist
+	for _, dir := range []string{manifest.Layout.Migrations, manifest.Layout.Queries} {
+		if _, err := os.Stat(g.path(dir)); os.IsNotExist(err) {
+			files = append(files, File{Path: g.path(dir, ".gitkeep")})
+		}
+	}
+
+	for _, piece := range g.pieces {
+		pieceFiles, err := g.generatePiece(piece, cfg)
+		if err != nil {
+			return fmt.Errorf("generate %s: %w", piece, err)
+		}
+		files = append(files, pieceFiles...)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return g.env.Writer.WriteAll(files)
+}
+
+// generatePiece renders the files for one optional piece
+func (g *InitGenerator) generatePiece(piece detect.Piece, cfg *config.ProjectConfig) ([]File, error) {
+	switch piece {
+	case detect.PieceSQLC:
+		file, err := g.env.render("project/sqlc.yaml.tmpl", g.path("sqlc.yaml"), cfg)
+		return []File{file}, err
+
+	case detect.PieceUtil:
+		file, err := g.env.render("internal/handlers/util/util.go.tmpl", g.path(cfg.Layout.Handlers, "util", "util.go"), cfg)
+		return []File{file}, err
+
+	case detect.PieceMakefile:
+		return g.generateMakefile(cfg)
+
+	default:
+		return nil, fmt.Errorf("unknown piece %q", piece)
+	}
+}
+
+// generateMakefile appends the missing database targets to the Makefile, or creates one
+func (g *InitGenerator) generateMakefile(cfg *config.ProjectConfig) ([]File, error) {
+	targets := g.project.MissingMakeTargets
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	content, err := g.env.Templates.Render("init/Makefile.tmpl", map[string]any{
+		"Project": cfg,
+		"Targets": targets,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appendFile(g.path("Makefile"), content)
+}
+
+// appendFile returns a File that appends content to the file at path, or
+// creates it when it doesn't exist yet
+func appendFile(path string, content []byte) ([]File, error) {
+	existing, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []File{{Path: path, Content: content}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		existing = append(existing, '\n')
+	}
+	merged := append(append(slices.Clone(existing), '\n'), content...)
+	return []File{{Path: path, Content: merged, Edit: true}}, nil
+}
+
+// projectConfig builds the template data used for the pieces
+func (g *InitGenerator) projectConfig(m *config.Manifest) *config.ProjectConfig {
+	cfg := config.NewProjectConfig(filepath.Base(g.project.Dir))
+	cfg.ModuleName = m.Module
+	cfg.Engine = m.Engine
+	cfg.Layout = m.Layout
+	cfg.Router = m.Router
+	cfg.Versioning = m.Migrations.Versioning
+	cfg.EmbeddedMigrations = false
+	cfg.Process()
+	return cfg
+}
+
+// path joins slash-separated project-relative elements onto the project directory
+func (g *InitGenerator) path(elem ...string) string {
+	return joinPath(g.project.Dir, elem...)
+}
@@ -0,0 +1,487 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+)
+
+// MiddlewareGenerator adds middleware from the catalog to a project: its
+// file in the middleware package next to the handlers, its settings in
+// config.Config and .env.example, and its installation in SetupHandler
+// (Middleware for net/http, whose ServeMux has no Use)
+type MiddlewareGenerator struct {
+	config *config.MiddlewareConfig
+	env    *Env
+}
+
+// NewMiddlewareGenerator creates a new MiddlewareGenerator
+func NewMiddlewareGenerator(cfg *config.MiddlewareConfig, env *Env) *MiddlewareGenerator {
+	return &MiddlewareGenerator{
+		config: cfg,
+		env:    env,
+	}
+}
+
+// middlewareData is the data rendered by the middleware templates
+type middlewareData struct {
+	ModuleName string
+	Layout     config.Layout
+	Router     config.Router
+}
+
+// middleware is a catalog entry: Func in the middleware package is called
+// with the config field Arg (the whole config when it is empty)
+type middleware struct {
+	Func   string
+	Arg    string
+	Fields []configField
+}
+
+// middlewareCatalog holds the entries of config.Middlewares
+var middlewareCatalog = map[string]middleware{
+	"request-id": {Func: "RequestID", Fields: configGroup("Request ID",
+		configField{Name: "RequestIDHeader", Type: "string", Env: "REQUEST_ID_HEADER", Default: "X-Request-ID"},
+	)},
+	"recovery": {Func: "Recovery", Fields: configGroup("Panic recovery",
+		configField{Name: "RecoveryStackTrace", Type: "bool", Env: "RECOVERY_STACK_TRACE", Default: "true"},
+	)},
+	"security-headers": {Func: "SecurityHeaders", Fields: configGroup("Security headers (an empty CSP sends none, a zero HSTS max age disables it)",
+		configField{Name: "SecurityFrameOptions", Type: "string", Env: "SECURITY_FRAME_OPTIONS", Default: "DENY"},
+		configField{Name: "SecurityCSP", Type: "string", Env: "SECURITY_CSP", Default: ""},
+		configField{Name: "SecurityHSTSMaxAge", Type: "time.Duration", Env: "SECURITY_HSTS_MAX_AGE", Default: "8760h"},
+	)},
+	"cors": {Func: "CORS", Fields: configGroup("CORS (comma-separated lists)",
+		configField{Name: "CORSAllowOrigins", Type: "[]string", Env: "CORS_ALLOW_ORIGINS", Default: "*"},
+		configField{Name: "CORSAllowMethods", Type: "[]string", Env: "CORS_ALLOW_METHODS", Default: "GET,POST,PUT,PATCH,DELETE,OPTIONS"},
+		configField{Name: "CORSAllowHeaders", Type: "[]string", Env: "CORS_ALLOW_HEADERS", Default: "Origin,Content-Type,Accept,Authorization"},
+		configField{Name: "CORSAllowCredentials", Type: "bool", Env: "CORS_ALLOW_CREDENTIALS", Default: "false"},
+		configField{Name: "CORSMaxAge", Type: "time.Duration", Env: "CORS_MAX_AGE", Default: "12h"},
+	)},
+	"rate-limit": {Func: "RateLimit", Fields: configGroup("Rate limit (requests per window per principal or IP; 0 disables it)",
+		configField{Name: "RateLimit", Type: "int", Env: "RATE_LIMIT", Default: "100"},
+		configField{Name: "RateLimitWindow", Type: "time.Duration", Env: "RATE_LIMIT_WINDOW", Default: "1m"},
+	)},
+	"gzip": {Func: "Gzip", Fields: configGroup("Gzip (level 1-9)",
+		configField{Name: "GzipLevel", Type: "int", Env: "GZIP_LEVEL", Default: "6"},
+	)},
+	"body-limit": {Func: "BodyLimit", Fields: configGroup("Request body limit in bytes",
+		configField{Name: "BodyLimit", Type: "int", Env: "BODY_LIMIT", Default: "4194304"},
+	)},
+	"timeout": {Func: "Timeout", Arg: "RequestTimeout", Fields: configGroup("Request timeout (keep it below WRITE_TIMEOUT)",
+		configField{Name: "RequestTimeout", Type: "time.Duration", Env: "REQUEST_TIMEOUT", Default: "8s"},
+	)},
+}
+
+// middlewareOrder ranks the middleware functions SetupHandler installs,
+// outermost first: the catalog in the order of config.Middlewares, with the
+// project's LoggingMiddleware after RequestID, so its records carry the ID,
+// and before Recovery, so it logs the 500 a panic turns into. Authenticate,
+// from add auth, follows CORS, whose preflight requests carry no token, and
+// precedes RateLimit, which counts requests per principal.
+var middlewareOrder = []string{"RequestID", "LoggingMiddleware", "Recovery", "SecurityHeaders", "CORS", "Authenticate", "RateLimit", "Gzip", "BodyLimit", "Timeout"}
+
+// middlewareUse is a middleware install adds: Func of the package at Import,
+// called as Call with %s standing for the config
+type middlewareUse struct {
+	Name   string
+	Func   string
+	Import string
+	Call   string
+}
+
+// Generate renders the middleware missing from the project, adds their
+// settings and installs them
+func (g *MiddlewareGenerator) Generate(ctx context.Context) error {
+	cfg := g.config
+	data := middlewareData{ModuleName: cfg.ModuleName, Layout: cfg.Layout, Router: cfg.Router}
+	dir := joinPath(cfg.ProjectPath, cfg.Layout.Handlers, "middleware")
+
+	g.env.Reporter.Step("📝 Generating middleware...")
+	var (
+		files  []File
+		fields []configField
+	)
+	for _, name := range cfg.Names {
+		file := strings.ReplaceAll(name, "-", "_") + ".go"
+		fields = append(fields, middlewareCatalog[name].Fields...)
+		if _, err := os.Stat(joinPath(dir, file)); err == nil {
+			g.env.Reporter.Info(fmt.Sprintf("%s/middleware/%s exists; keeping it", cfg.Layout.Handlers, file))
+			continue
+		}
+		f, err := g.env.renderGo("middleware/"+file+".tmpl", joinPath(dir, file), data)
+		if err != nil {
+			return fmt.Errorf("generate %s: %w", name, err)
+		}
+		files = append(files, f)
+	}
+
+	if slices.Contains(cfg.Names, "rate-limit") {
+		// The limiter is in the cache package, and the principal in util.Actor
+		shared, err := cacheFiles(g.env, cfg.ProjectPath, cfg.Layout)
+		if err != nil {
+			return err
+		}
+		actor, err := actorFiles(g.env, cfg.ProjectPath, cfg.Layout, cfg.Router)
+		if err != nil {
+			return err
+		}
+		files = append(append(files, shared...), actor...)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	patch, err := configPatch(joinPath(cfg.ProjectPath, "internal", "config", "config.go"), fields)
+	if err != nil {
+		g.env.Reporter.Warn(err.Error())
+		g.env.Reporter.NextStep("Add the settings of the middleware to config.Config; see the cfg fields they use")
+	}
+	files = append(files, patch...)
+
+	envExample, err := envExamplePatch(joinPath(cfg.ProjectPath, ".env.example"), fields)
+	if err != nil {
+		return err
+	}
+	files = append(files, envExample...)
+
+	files = append(files, serviceConfigFiles(g.env, cfg.ProjectPath, cfg.ModuleName, cfg.Layout)...)
+
+	uses := make([]middlewareUse, len(cfg.Names))
+	for i, name := range cfg.Names {
+		m := middlewareCatalog[name]
+		call := "middleware." + m.Func + "(%s)"
+		if m.Arg != "" {
+			call = "middleware." + m.Func + "(%s." + m.Arg + ")"
+		}
+		uses[i] = middlewareUse{Name: name, Func: m.Func, Import: cfg.ModuleName + "/" + cfg.Layout.Handlers + "/middleware", Call: call}
+	}
+	installed, err := g.install(uses)
+	if err != nil {
+		return err
+	}
+	files = append(files, installed...)
+
+	return g.env.Writer.WriteAll(files)
+}
+
+// install adds uses to SetupHandler, or to the chain of Middleware for
+// net/http. Failures are reported as next steps.
+func (g *MiddlewareGenerator) install(uses []middlewareUse) ([]File, error) {
+	cfg := g.config
+	manual := func(err error) ([]File, error) {
+		g.env.Reporter.Warn(fmt.Sprintf("the middleware was not installed: %v", err))
+		for _, u := range uses {
+			g.env.Reporter.NextStep(fmt.Sprintf("Install %s in %s, in the order of `ready-go add middleware --help`", fmt.Sprintf(u.Call, "cfg"), cfg.Router.Setup))
+		}
+		return nil, nil
+	}
+
+	var (
+		setup *routeSetup
+		arg   string
+		err   error
+	)
+	if cfg.Router.Framework == config.FrameworkStdlib {
+		// Middleware(next http.Handler, cfg *config.Config) has the chain
+		path := joinPath(cfg.ProjectPath, cfg.Router.Setup)
+		src, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return manual(fmt.Errorf("failed to read %s: %w", cfg.Router.Setup, readErr))
+		}
+		setup, err = parseSetup(path, src, "Middleware")
+		if err != nil {
+			return manual(err)
+		}
+		setup.http = newHTTPSyntax(cfg.Router.Framework)
+		for _, field := range setup.fn.Type.Params.List {
+			if setup.text(field.Type) == "*config.Config" && len(field.Names) > 0 {
+				arg = field.Names[0].Name
+			}
+		}
+		if arg == "" {
+			return manual(fmt.Errorf("function Middleware in %s does not take the *config.Config", cfg.Router.Setup))
+		}
+	} else {
+		setup, err = loadRouteSetup(cfg.ProjectPath, cfg.Router)
+		if err != nil {
+			return manual(err)
+		}
+		arg = setup.Svc + ".Config"
+	}
+
+	var file *File
+	requestID := false
+	for _, u := range uses {
+		call := fmt.Sprintf(u.Call, arg)
+		requestID = requestID || u.Func == "RequestID"
+
+		var e *edit
+		if cfg.Router.Framework == config.FrameworkStdlib {
+			e, err = g.chainEdit(setup, u.Func, call)
+		} else {
+			e = g.useEdit(setup, u.Func, fmt.Sprintf("%s.Use(%s)", setup.Router, call))
+		}
+		if err != nil {
+			return manual(err)
+		}
+		if e == nil {
+			g.env.Reporter.Info(fmt.Sprintf("%s is installed already", u.Name))
+			continue
+		}
+		f, err := setup.apply([]edit{*e}, []string{u.Import})
+		if err != nil {
+			return nil, err
+		}
+		file = &f
+	}
+
+	if requestID {
+		if f := g.logRequestID(setup); f != nil {
+			file = f
+		}
+	}
+	if file == nil {
+		return nil, nil
+	}
+	return []File{*file}, nil
+}
+
+// useEdit inserts stmt among the router.Use calls of the setup function in
+// middlewareOrder, or first in the function when it has none. It returns nil
+// when fn is used already.
+func (g *MiddlewareGenerator) useEdit(setup *routeSetup, fn, stmt string) *edit {
+	rank := slices.Index(middlewareOrder, fn)
+	var last ast.Stmt
+	for _, s := range setup.fn.Body.List {
+		call, ok := callOf(s)
+		if !ok || !isSelectorCall(call, setup.Router, "Use") {
+			continue
+		}
+		for _, a := range call.Args {
+			name := funcName(a)
+			if name == fn {
+				return nil
+			}
+			if r := slices.Index(middlewareOrder, name); r > rank {
+				return &edit{at: setup.offset(s.Pos()), text: stmt + "\n\t"}
+			}
+		}
+		last = s
+	}
+	if last != nil {
+		return &edit{at: setup.offset(last.End()), text: "\n\t" + stmt}
+	}
+	return &edit{at: setup.offset(setup.fn.Body.Lbrace) + 1, text: "\n\t" + stmt}
+}
+
+// chainEdit inserts call into the chain slice of Middleware in
+// middlewareOrder. It returns nil when fn is in the chain already.
+func (g *MiddlewareGenerator) chainEdit(setup *routeSetup, fn, call string) (*edit, error) {
+	var chain *ast.CompositeLit
+	ast.Inspect(setup.fn.Body, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.CompositeLit); ok && chain == nil {
+			if _, ok := lit.Type.(*ast.ArrayType); ok {
+				chain = lit
+			}
+		}
+		return chain == nil
+	})
+	if chain == nil {
+		return nil, fmt.Errorf("function Middleware in %s has no chain of middleware", g.config.Router.Setup)
+	}
+
+	rank := slices.Index(middlewareOrder, fn)
+	for _, elt := range chain.Elts {
+		name := funcName(elt)
+		if name == fn {
+			return nil, nil
+		}
+		if r := slices.Index(middlewareOrder, name); r > rank {
+			return &edit{at: setup.offset(elt.Pos()), text: call + ",\n\t\t"}, nil
+		}
+	}
+	return &edit{at: setup.offset(chain.Rbrace), text: "\t" + call + ",\n"}, nil
+}
+
+// logRequestID makes the request log of LoggingMiddleware carry the request
+// ID, by logging with the request context: slog.Info("request", ...) becomes
+// slog.InfoContext(ctx, "request", ...)
+func (g *MiddlewareGenerator) logRequestID(setup *routeSetup) *File {
+	fn := setup.lookup("LoggingMiddleware")
+	if fn == nil {
+		return nil
+	}
+	var info *ast.CallExpr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && isSelectorCall(call, "slog", "Info") && len(call.Args) > 0 && setup.text(call.Args[0]) == `"request"` {
+			info = call
+		}
+		return info == nil
+	})
+	if info == nil {
+		return nil
+	}
+	sel := info.Fun.(*ast.SelectorExpr).Sel
+	f, err := setup.apply([]edit{{
+		at:   setup.offset(sel.Pos()),
+		text: "InfoContext(" + setup.http.Ctx() + ", ",
+		end:  setup.offset(info.Lparen) + 1,
+	}}, nil)
+	if err != nil {
+		g.env.Reporter.Warn(fmt.Sprintf("LoggingMiddleware was not changed: %v", err))
+		return nil
+	}
+	return &f
+}
+
+// serviceConfigFiles adds the Config field middleware and handlers read
+// their settings from to cmd.APIService, and sets it where the commands
+// create the service. Failures are reported as next steps.
+func serviceConfigFiles(env *Env, projectPath, moduleName string, layout config.Layout) []File {
+	manual := func(err error) []File {
+		env.Reporter.Warn(err.Error())
+		env.Reporter.NextStep("Add Config *config.Config to cmd.APIService and set it to the loaded config in main")
+		return nil
+	}
+
+	path := joinPath(projectPath, "cmd", "service.go")
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return manual(fmt.Errorf("failed to read cmd/service.go: %w", err))
+	}
+	service, err := parseFile(path, src)
+	if err != nil {
+		return manual(err)
+	}
+	var fields *ast.FieldList
+	ast.Inspect(service.file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok && spec.Name.Name == "APIService" {
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				fields = st.Fields
+			}
+		}
+		return fields == nil
+	})
+	if fields == nil {
+		return manual(fmt.Errorf("APIService not found in cmd/service.go"))
+	}
+	for _, field := range fields.List {
+		for _, name := range field.Names {
+			if name.Name == "Config" {
+				return nil
+			}
+		}
+	}
+
+	f, err := service.apply([]edit{{at: service.offset(fields.Opening) + 1, text: "\n\tConfig *config.Config"}},
+		[]string{moduleName + "/internal/config"})
+	if err != nil {
+		return manual(err)
+	}
+	files := []File{f}
+
+	for _, main := range []string{joinPath(projectPath, layout.API, "main.go"), joinPath(projectPath, layout.GRPCCmd(), "main.go")} {
+		src, err := os.ReadFile(main)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return manual(fmt.Errorf("failed to read %s: %w", main, err))
+		}
+		file, err := serviceLiteralPatch(main, src)
+		if err != nil {
+			return manual(err)
+		}
+		files = append(files, file...)
+	}
+	return files
+}
+
+// serviceLiteralPatch sets Config in the cmd.APIService literal of a main
+// package to the variable assigned from config.Load
+func serviceLiteralPatch(path string, src []byte) ([]File, error) {
+	r, err := parseFile(path, src)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		literal *ast.CompositeLit
+		loaded  string
+	)
+	ast.Inspect(r.file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if call, ok := n.Rhs[0].(*ast.CallExpr); ok && isSelectorCall(call, "config", "Load") {
+				if ident, ok := n.Lhs[0].(*ast.Ident); ok {
+					loaded = ident.Name
+				}
+			}
+		case *ast.CompositeLit:
+			if r.text(n.Type) == "cmd.APIService" && literal == nil {
+				literal = n
+			}
+		}
+		return true
+	})
+	if literal == nil {
+		return nil, nil
+	}
+	if loaded == "" {
+		return nil, fmt.Errorf("config.Load is not called in %s; APIService.Config was not set", path)
+	}
+	for _, elt := range literal.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok && r.text(kv.Key) == "Config" {
+			return nil, nil
+		}
+	}
+
+	f, err := r.apply([]edit{{at: r.offset(literal.Lbrace) + 1, text: "\n\t\tConfig: " + loaded + ","}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return []File{f}, nil
+}
+
+// callOf returns the call a statement consists of
+func callOf(s ast.Stmt) (*ast.CallExpr, bool) {
+	expr, ok := s.(*ast.ExprStmt)
+	if !ok {
+		return nil, false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	return call, ok
+}
+
+// isSelectorCall reports whether call calls x.name, a method such as
+// router.Use or a function such as config.Load
+func isSelectorCall(call *ast.CallExpr, x, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == x
+}
+
+// funcName returns the name of the function a middleware expression is or
+// calls: RequestID for middleware.RequestID(svc.Config), LoggingMiddleware
+// for LoggingMiddleware()
+func funcName(expr ast.Expr) string {
+	if call, ok := expr.(*ast.CallExpr); ok {
+		expr = call.Fun
+	}
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
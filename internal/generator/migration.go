@@ -0,0 +1,312 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// MigrationGenerator writes a goose migration with Up and Down statements
+// for a set of column and index changes
+type MigrationGenerator struct {
+	config *config.MigrationConfig
+	env    *Env
+}
+
+// NewMigrationGenerator creates a new MigrationGenerator
+func NewMigrationGenerator(cfg *config.MigrationConfig, env *Env) *MigrationGenerator {
+	return &MigrationGenerator{
+		config: cfg,
+		env:    env,
+	}
+}
+
+// migrationData is the data rendered by migration/migration.sql.tmpl
+type migrationData struct {
+	Name string
+	Up   []string
+	Down []string
+}
+
+// Generate checks every change against the schema the existing migrations
+// produce, then renders the migration
+func (g *MigrationGenerator) Generate(ctx context.Context) error {
+	migrationsDir := joinPath(g.config.ProjectPath, g.config.Layout.Migrations)
+
+	s, err := schema.Load(migrationsDir)
+	if err != nil {
+		return errs.Validation("fix the migration, or write this migration by hand", "failed to replay migrations in %s: %v", g.config.Layout.Migrations, err)
+	}
+
+	data, err := g.plan(s)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	version, err := nextMigrationVersion(migrationsDir, g.config.Versioning)
+	if err != nil {
+		return err
+	}
+	outputPath := filepath.Join(migrationsDir, version+"_"+g.config.Name+".sql")
+
+	file, err := g.env.render("migration/migration.sql.tmpl", outputPath, data)
+	if err != nil {
+		return fmt.Errorf("generate migration file: %w", err)
+	}
+
+	return g.env.Writer.WriteAll([]File{file})
+}
+
+// plan turns the requested changes into Up and Down statements. Each change
+// is applied to s as it is planned, so later changes can refer to earlier
+// ones, e.g. an index on a column added by the same migration.
+func (g *MigrationGenerator) plan(s *schema.Schema) (migrationData, error) {
+	d := schema.Dialect{Engine: g.config.Engine}
+	data := migrationData{Name: g.config.Name}
+	var downs [][]string
+
+	apply := func(up, down []string) error {
+		for _, stmt := range up {
+			if err := s.Apply(stmt); err != nil {
+				return err
+			}
+		}
+		data.Up = append(data.Up, up...)
+		downs = append(downs, down)
+		return nil
+	}
+
+	for _, spec := range g.config.AddColumns {
+		up, down, err := g.addColumn(s, d, spec)
+		if err != nil {
+			return data, err
+		}
+		if err := apply(up, down); err != nil {
+			return data, err
+		}
+	}
+
+	for _, spec := range g.config.AddIndexes {
+		up, down, err := g.addIndex(s, d, spec)
+		if err != nil {
+			return data, err
+		}
+		if err := apply(up, down); err != nil {
+			return data, err
+		}
+	}
+
+	for _, spec := range g.config.DropColumns {
+		up, down, err := g.dropColumn(s, d, spec)
+		if err != nil {
+			return data, err
+		}
+		if err := apply(up, down); err != nil {
+			return data, err
+		}
+	}
+
+	// Undo the changes in reverse order
+	for _, down := range slices.Backward(downs) {
+		data.Down = append(data.Down, down...)
+	}
+
+	return data, nil
+}
+
+const (
+	addColumnUsage  = "use table.column:type[:unique][:index][:notnull][:default=value], e.g. products.sku:string:unique"
+	addIndexUsage   = "use table(column[,column...])[:unique], e.g. products(sku)"
+	dropColumnUsage = "use table.column, e.g. products.legacy"
+)
+
+// addColumn plans --add-column table.column:type[:modifiers...]
+func (g *MigrationGenerator) addColumn(s *schema.Schema, d schema.Dialect, spec string) (up, down []string, err error) {
+	target, rest, _ := strings.Cut(spec, ":")
+	table, column, ok := splitColumnRef(target)
+	if !ok || rest == "" {
+		return nil, nil, &errs.ValidationError{Field: "add-column", Message: fmt.Sprintf("invalid column %q", spec), Hint: addColumnUsage}
+	}
+
+	t, err := lookupTable(s, table, "add-column")
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.Column(column) != nil {
+		return nil, nil, &errs.ValidationError{Field: "add-column", Message: fmt.Sprintf("column %s.%s already exists", table, column)}
+	}
+
+	parts := splitModifiers(rest)
+	definition := []string{column, d.ColumnType(parts[0])}
+	var indexes []*schema.Index
+
+	for _, mod := range parts[1:] {
+		key, value, _ := strings.Cut(mod, "=")
+		switch strings.ToLower(key) {
+		case "unique":
+			indexes = append(indexes, &schema.Index{Name: schema.IndexName(table, []string{column}, true), Columns: []string{column}, Unique: true})
+		case "index":
+			indexes = append(indexes, &schema.Index{Name: schema.IndexName(table, []string{column}, false), Columns: []string{column}})
+		case "notnull", "required":
+			definition = append(definition, "NOT NULL")
+		case "null", "nullable":
+			definition = append(definition, "NULL")
+		case "default":
+			definition = append(definition, "DEFAULT "+sqlLiteral(value))
+		default:
+			return nil, nil, &errs.ValidationError{Field: "add-column", Message: fmt.Sprintf("unknown modifier %q in %q", mod, spec), Hint: addColumnUsage}
+		}
+	}
+
+	up = []string{d.AddColumn(table, strings.Join(definition, " "))}
+	for _, idx := range indexes {
+		up = append(up, d.CreateIndex(table, idx))
+		down = append(down, d.DropIndex(table, idx))
+	}
+	down = append(down, d.DropColumn(table, column))
+	return up, down, nil
+}
+
+var indexSpec = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\(([^)]+)\)(:unique)?$`)
+
+// addIndex plans --add-index table(col[,col...])[:unique]
+func (g *MigrationGenerator) addIndex(s *schema.Schema, d schema.Dialect, spec string) (up, down []string, err error) {
+	m := indexSpec.FindStringSubmatch(strings.ReplaceAll(spec, " ", ""))
+	if m == nil {
+		return nil, nil, &errs.ValidationError{Field: "add-index", Message: fmt.Sprintf("invalid index %q", spec), Hint: addIndexUsage}
+	}
+	table, columns, unique := m[1], strings.Split(m[2], ","), m[3] != ""
+
+	t, err := lookupTable(s, table, "add-index")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, col := range columns {
+		if t.Column(col) == nil {
+			return nil, nil, &errs.ValidationError{Field: "add-index", Message: fmt.Sprintf("column %s.%s does not exist", table, col)}
+		}
+	}
+
+	idx := &schema.Index{Name: schema.IndexName(table, columns, unique), Columns: columns, Unique: unique}
+	if t.Index(idx.Name) != nil {
+		return nil, nil, &errs.ValidationError{Field: "add-index", Message: fmt.Sprintf("index %s already exists on %s", idx.Name, table)}
+	}
+
+	return []string{d.CreateIndex(table, idx)}, []string{d.DropIndex(table, idx)}, nil
+}
+
+// dropColumn plans --drop-column table.column. The Down section restores the
+// column from its definition in the earlier migrations, along with any
+// single-column indexes that are dropped with it.
+func (g *MigrationGenerator) dropColumn(s *schema.Schema, d schema.Dialect, spec string) (up, down []string, err error) {
+	table, column, ok := splitColumnRef(spec)
+	if !ok {
+		return nil, nil, &errs.ValidationError{Field: "drop-column", Message: fmt.Sprintf("invalid column %q", spec), Hint: dropColumnUsage}
+	}
+
+	t, err := lookupTable(s, table, "drop-column")
+	if err != nil {
+		return nil, nil, err
+	}
+	c := t.Column(column)
+	if c == nil {
+		return nil, nil, &errs.ValidationError{Field: "drop-column", Message: fmt.Sprintf("column %s.%s does not exist", table, column)}
+	}
+
+	after := ""
+	if i := slices.Index(t.Columns, c); i > 0 {
+		after = t.Columns[i-1].Name
+	}
+
+	down = []string{d.RestoreColumn(table, c.Definition, after)}
+	for _, idx := range t.Indexes {
+		if !slices.Contains(idx.Columns, c.Name) {
+			continue
+		}
+		if len(idx.Columns) > 1 {
+			g.env.Reporter.Warn(fmt.Sprintf("index %s on %s also covers other columns; recreate it by hand in the Down section", idx.Name, table))
+			continue
+		}
+		if !strings.Contains(strings.ToUpper(c.Definition), "UNIQUE") {
+			down = append(down, d.CreateIndex(table, idx))
+		}
+	}
+
+	return []string{d.DropColumn(table, column)}, down, nil
+}
+
+// lookupTable returns the named table or a validation error listing the known tables
+func lookupTable(s *schema.Schema, name, field string) (*schema.Table, error) {
+	if t := s.Table(name); t != nil {
+		return t, nil
+	}
+	hint := "no tables are created by the migrations"
+	if names := s.TableNames(); len(names) > 0 {
+		hint = "known tables: " + strings.Join(names, ", ")
+	}
+	return nil, &errs.ValidationError{Field: field, Message: fmt.Sprintf("table %s is not created by any migration", name), Hint: hint}
+}
+
+// splitColumnRef splits "table.column"
+func splitColumnRef(ref string) (table, column string, ok bool) {
+	table, column, ok = strings.Cut(ref, ".")
+	return table, column, ok && table != "" && column != ""
+}
+
+// splitModifiers splits "decimal(12,2):notnull" on colons outside parentheses
+func splitModifiers(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ':':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// sqlLiteral quotes a default value unless it is a number, keyword or already quoted
+func sqlLiteral(v string) string {
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v
+	}
+	switch strings.ToUpper(v) {
+	case "NULL", "TRUE", "FALSE", "CURRENT_TIMESTAMP", "CURRENT_DATE", "NOW()":
+		return v
+	}
+	if strings.HasPrefix(v, "'") || strings.HasPrefix(v, "(") {
+		return v
+	}
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
+
+// nextMigrationVersion returns the version prefix for a new migration in dir
+// under the project's versioning scheme
+func nextMigrationVersion(dir string, versioning schema.Versioning) (string, error) {
+	migrations, err := schema.Migrations(dir)
+	if err != nil {
+		return "", err
+	}
+	return schema.NextVersion(migrations, versioning, time.Now()), nil
+}
@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/openapi"
+)
+
+// openAPIHeader starts every document written by OpenAPIGenerator. Files
+// starting with it are regenerated without a conflict.
+const openAPIHeader = "# Generated by `ready-go gen openapi` from the routes and DTOs. Re-run it instead of editing this file.\n"
+
+// OpenAPIGenerator documents the routes registered in a project's handlers as
+// an OpenAPI document, optionally served by the project at /docs
+type OpenAPIGenerator struct {
+	config *config.OpenAPIConfig
+	env    *Env
+}
+
+// NewOpenAPIGenerator creates a new OpenAPIGenerator
+func NewOpenAPIGenerator(cfg *config.OpenAPIConfig, env *Env) *OpenAPIGenerator {
+	return &OpenAPIGenerator{
+		config: cfg,
+		env:    env,
+	}
+}
+
+// docsData is the data rendered by the openapi templates
+type docsData struct {
+	ModuleName string
+	Router     config.Router
+	// EmbedDir is the directory of the document, and EmbedPackage its package
+	EmbedDir     string
+	EmbedPackage string
+	FileName     string
+	DocsPath     string
+	SpecPath     string
+	Setup        *routeSetup
+}
+
+// Generate scans the handlers and writes the document
+func (g *OpenAPIGenerator) Generate(ctx context.Context) error {
+	g.env.Reporter.Step("🔍 Scanning routes...")
+	doc, routes, warnings, err := openapi.Build(openapi.Project{
+		Dir:      g.config.ProjectPath,
+		Module:   g.config.ModuleName,
+		Handlers: g.config.Layout.Handlers,
+		Entity:   g.config.Layout.Entity,
+		Models:   g.config.Layout.Models,
+	}, openapi.Info{Title: path.Base(g.config.ModuleName), Version: "1.0.0"})
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %w", g.config.Layout.Handlers, err)
+	}
+	for _, w := range warnings {
+		g.env.Reporter.Warn(w)
+	}
+	if len(routes) == 0 {
+		return errs.Validation("register routes with router.Get(\"/path\", handler.Handle) and friends", "no routes found in %s", g.config.Layout.Handlers)
+	}
+	g.env.Reporter.Info(fmt.Sprintf("Documenting %d routes", len(routes)))
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	content, err := doc.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to encode the OpenAPI document: %w", err)
+	}
+	outputPath := joinPath(g.config.ProjectPath, g.config.Output)
+	file := File{Path: outputPath, Content: append([]byte(openAPIHeader), content...)}
+	if existing, err := os.ReadFile(outputPath); err == nil && bytes.HasPrefix(existing, []byte(openAPIHeader)) {
+		file.Edit = true
+	}
+	files := []File{file}
+
+	if g.config.Docs {
+		docs, err := g.docsFiles()
+		if err != nil {
+			return err
+		}
+		files = append(files, docs...)
+	}
+
+	return g.env.Writer.WriteAll(files)
+}
+
+// docsFiles embeds the document in a package next to it and serves it with
+// Swagger UI. Existing files are kept, so re-runs only refresh the document.
+func (g *OpenAPIGenerator) docsFiles() ([]File, error) {
+	data := docsData{
+		ModuleName:   g.config.ModuleName,
+		Router:       g.config.Router,
+		EmbedDir:     path.Dir(g.config.Output),
+		EmbedPackage: path.Base(path.Dir(g.config.Output)),
+		FileName:     path.Base(g.config.Output),
+		DocsPath:     openapi.DocsPath,
+		SpecPath:     openapi.DocsPath + "/" + path.Base(g.config.Output),
+	}
+
+	var files []File
+	for _, f := range []struct{ tmpl, out string }{
+		{"openapi/embed.go.tmpl", joinPath(g.config.ProjectPath, data.EmbedDir, "embed.go")},
+		{"openapi/docs.go.tmpl", joinPath(g.config.ProjectPath, g.config.Layout.Handlers, "docs", "docs.go")},
+	} {
+		if _, err := os.Stat(f.out); err == nil {
+			continue
+		}
+		file, err := g.env.renderGo(f.tmpl, f.out, data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	setup, err := loadRouteSetup(g.config.ProjectPath, g.config.Router)
+	if err != nil {
+		g.env.Reporter.Warn(fmt.Sprintf("docs routes were not registered: %v", err))
+		g.env.Reporter.NextStep(fmt.Sprintf("Serve docs.UI at %s and docs.Spec at %s by hand", data.DocsPath, data.SpecPath))
+		return files, nil
+	}
+	if setup.defines("setupDocsHandlers") {
+		return files, nil
+	}
+
+	data.Setup = setup
+	decl, err := g.env.Templates.Render("openapi/routes.go.tmpl", data)
+	if err != nil {
+		return nil, err
+	}
+	imports := append([]string{"log/slog", g.config.ModuleName + "/" + g.config.Layout.Handlers + "/docs"},
+		setup.http.routeImports(g.config.ModuleName+"/"+g.config.Layout.Handlers+"/util")...)
+	registration, err := setup.register("setupDocsHandlers", decl, imports...)
+	if err != nil {
+		return nil, &errs.TemplateError{Template: "openapi/routes.go.tmpl", Err: err, Hint: templateHint}
+	}
+	g.env.Reporter.NextStep(fmt.Sprintf("make run, then open http://localhost:<port>%s", data.DocsPath))
+	return append(files, registration), nil
+}
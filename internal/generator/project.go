@@ -1,64 +1,109 @@
 package generator
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
 )
 
 // ProjectGenerator handles the generation of a new project
 type ProjectGenerator struct {
 	config *config.ProjectConfig
+	env    *Env
 }
 
 // NewProjectGenerator creates a new ProjectGenerator
-func NewProjectGenerator(cfg *config.ProjectConfig) *ProjectGenerator {
+func NewProjectGenerator(cfg *config.ProjectConfig, env *Env) *ProjectGenerator {
 	return &ProjectGenerator{
 		config: cfg,
+		env:    env,
 	}
 }
 
-// Generate creates the entire project structure
-func (g *ProjectGenerator) Generate() error {
+// Generate creates the entire project structure, stopping early if ctx is cancelled
+func (g *ProjectGenerator) Generate(ctx context.Context) error {
 	projectPath := filepath.Join(g.config.OutputDir, g.config.ProjectName)
 
-	// Check if project directory already exists
-	if _, err := os.Stat(projectPath); err == nil {
-		return fmt.Errorf("directory %s already exists", projectPath)
+	if err := g.checkProjectDir(projectPath); err != nil {
+		return err
 	}
 
 	// Create directory structure
-	fmt.Println("📁 Creating directory structure...")
+	g.env.Reporter.Step("📁 Creating directory structure...")
 	if err := g.createDirectoryStructure(projectPath); err != nil {
 		return fmt.Errorf("failed to create directory structure: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Generate files
-	fmt.Println("📝 Generating project files...")
+	g.env.Reporter.Step("📝 Generating project files...")
 	if err := g.generateFiles(projectPath); err != nil {
 		return fmt.Errorf("failed to generate files: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Initialize go module
-	fmt.Println("🔧 Initializing go module...")
-	if err := g.initGoModule(projectPath); err != nil {
+	g.env.Reporter.Step("🔧 Initializing go module...")
+	if err := g.initGoModule(ctx, projectPath); err != nil {
 		return fmt.Errorf("failed to initialize go module: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Initialize git repository
-	fmt.Println("🔀 Initializing git repository...")
-	if err := g.initGit(projectPath); err != nil {
-		fmt.Printf("⚠️  Warning: failed to initialize git: %v\n", err)
+	g.env.Reporter.Step("🔀 Initializing git repository...")
+	if err := g.initGit(ctx, projectPath); err != nil {
+		g.env.Reporter.Warn(fmt.Sprintf("failed to initialize git: %v", err))
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	// Download dependencies
-	fmt.Println("📦 Downloading dependencies...")
-	if err := g.downloadDependencies(projectPath); err != nil {
-		fmt.Printf("⚠️  Warning: failed to download dependencies: %v\n", err)
-		fmt.Println("   Run 'go mod tidy' manually in the project directory")
+	g.env.Reporter.Step("📦 Downloading dependencies...")
+	if err := g.downloadDependencies(ctx, projectPath); err != nil {
+		g.env.Reporter.Warn(fmt.Sprintf("failed to download dependencies: %v", err))
+		g.env.Reporter.NextStep("go mod tidy         # Dependencies were not downloaded")
+	}
+
+	return nil
+}
+
+// checkProjectDir refuses to generate into a non-empty directory unless the
+// conflict policy allows touching existing files
+func (g *ProjectGenerator) checkProjectDir(projectPath string) error {
+	info, err := os.Stat(projectPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", projectPath, err)
+	}
+	if !info.IsDir() {
+		return errs.Conflict(projectPath, "choose a different project name", "%s already exists and is not a directory", projectPath)
+	}
+
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", projectPath, err)
+	}
+	if len(entries) > 0 && g.env.Writer.Policy() == ConflictFail {
+		return errs.Conflict(projectPath, conflictHint, "directory %s already exists and is not empty", projectPath)
 	}
 
 	return nil
@@ -66,16 +111,18 @@ func (g *ProjectGenerator) Generate() error {
 
 // createDirectoryStructure creates the project directory structure
 func (g *ProjectGenerator) createDirectoryStructure(projectPath string) error {
+	layout := g.config.Layout
 	dirs := []string{
 		projectPath,
-		filepath.Join(projectPath, "cmd", "api"),
+		joinPath(projectPath, layout.API),
+		joinPath(projectPath, layout.MigrateCmd()),
 		filepath.Join(projectPath, "internal", "config"),
-		filepath.Join(projectPath, "internal", "handlers", g.config.SampleAPINameLower),
-		filepath.Join(projectPath, "internal", "handlers", "util"),
-		filepath.Join(projectPath, "internal", "models"),
+		joinPath(projectPath, layout.Handlers, g.config.SampleAPINameLower),
+		joinPath(projectPath, layout.Handlers, "util"),
+		joinPath(projectPath, layout.Models),
 		filepath.Join(projectPath, "internal", "repository"),
-		filepath.Join(projectPath, "database", "migrations"),
-		filepath.Join(projectPath, "database", "queries"),
+		joinPath(projectPath, layout.Migrations),
+		joinPath(projectPath, layout.Queries),
 	}
 
 	for _, dir := range dirs {
@@ -89,25 +136,28 @@ func (g *ProjectGenerator) createDirectoryStructure(projectPath string) error {
 
 // generateFiles generates all project files from templates
 func (g *ProjectGenerator) generateFiles(projectPath string) error {
-	renderer := NewTemplateRenderer(g.config)
-
-	files := []struct {
+	layout := g.config.Layout
+	templates := []struct {
 		template string
 		output   string
 	}{
 		// Go source files
-		{"cmd/api/main.go.tmpl", filepath.Join(projectPath, "cmd", "api", "main.go")},
+		{"cmd/api/main.go.tmpl", joinPath(projectPath, layout.API, "main.go")},
+		{"cmd/migrate/main.go.tmpl", joinPath(projectPath, layout.MigrateCmd(), "main.go")},
 		{"cmd/service.go.tmpl", filepath.Join(projectPath, "cmd", "service.go")},
 		{"internal/config/config.go.tmpl", filepath.Join(projectPath, "internal", "config", "config.go")},
-		{"internal/handlers/handler.go.tmpl", filepath.Join(projectPath, "internal", "handlers", "handler.go")},
-		{"internal/handlers/sample/sample_handler.go.tmpl", filepath.Join(projectPath, "internal", "handlers", g.config.SampleAPINameLower, "handler.go")},
-		{"internal/handlers/util/util.go.tmpl", filepath.Join(projectPath, "internal", "handlers", "util", "util.go")},
-		{"internal/models/db.go.tmpl", filepath.Join(projectPath, "internal", "models", "db.go")},
+		{"internal/handlers/handler.go.tmpl", joinPath(projectPath, layout.Handlers, "handler.go")},
+		{"internal/handlers/sample/sample_handler.go.tmpl", joinPath(projectPath, layout.Handlers, g.config.SampleAPINameLower, "handler.go")},
+		{"internal/handlers/util/util.go.tmpl", joinPath(projectPath, layout.Handlers, "util", "util.go")},
+		{"internal/handlers/util/pagination.go.tmpl", joinPath(projectPath, layout.Handlers, "util", "pagination.go")},
+		{"internal/handlers/util/validate.go.tmpl", joinPath(projectPath, layout.Handlers, "util", "validate.go")},
+		{"internal/models/db.go.tmpl", joinPath(projectPath, layout.Models, "db.go")},
 		{"internal/repository/db.go.tmpl", filepath.Join(projectPath, "internal", "repository", "db.go")},
 
 		// Database files
-		{"database/migrations/init.sql.tmpl", filepath.Join(projectPath, "database", "migrations", "00001_init.sql")},
-		{"database/queries/sample.sql.tmpl", filepath.Join(projectPath, "database", "queries", g.config.SampleAPINameLower+".sql")},
+		{"database/migrations/embed.go.tmpl", joinPath(projectPath, layout.Migrations, "embed.go")},
+		{"database/migrations/init.sql.tmpl", joinPath(projectPath, layout.Migrations, schema.NextVersion(nil, g.config.Versioning, time.Now())+"_init.sql")},
+		{"database/queries/sample.sql.tmpl", joinPath(projectPath, layout.Queries, g.config.SampleAPINameLower+".sql")},
 
 		// Project config files
 		{"project/docker-compose.yml.tmpl", filepath.Join(projectPath, "docker-compose.yml")},
@@ -118,41 +168,50 @@ func (g *ProjectGenerator) generateFiles(projectPath string) error {
 		{"project/README.md.tmpl", filepath.Join(projectPath, "README.md")},
 	}
 
-	for _, file := range files {
-		if err := renderer.RenderToFile(file.template, file.output); err != nil {
-			return fmt.Errorf("failed to generate %s: %w", file.output, err)
+	if g.config.Router.Framework != config.FrameworkFiber {
+		templates = append(templates, struct {
+			template string
+			output   string
+		}{"internal/handlers/util/bind.go.tmpl", joinPath(projectPath, layout.Handlers, "util", "bind.go")})
+	}
+	if g.config.GRPC != nil {
+		templates = append(templates, grpcServerTemplates(projectPath, layout, g.config.GRPC)...)
+	}
+
+	files := make([]File, 0, len(templates))
+	for _, t := range templates {
+		file, err := g.env.render(t.template, t.output, g.config)
+		if err != nil {
+			return fmt.Errorf("failed to generate %s: %w", t.output, err)
 		}
+		files = append(files, file)
 	}
 
-	return nil
+	manifest, err := g.config.Manifest().Marshal()
+	if err != nil {
+		return err
+	}
+	files = append(files, File{Path: filepath.Join(projectPath, config.ManifestFile), Content: manifest})
+
+	return g.env.Writer.WriteAll(files)
 }
 
-// initGoModule initializes the go module
-func (g *ProjectGenerator) initGoModule(projectPath string) error {
-	cmd := exec.Command("go", "mod", "init", g.config.ModuleName)
-	cmd.Dir = projectPath
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("go mod init failed: %w\n%s", err, output)
+// initGoModule initializes the go module, keeping the go.mod of a directory
+// generated into again under --force or --skip-existing
+func (g *ProjectGenerator) initGoModule(ctx context.Context, projectPath string) error {
+	if _, err := os.Stat(filepath.Join(projectPath, "go.mod")); err == nil {
+		g.env.Reporter.Info("go.mod exists; keeping it")
+		return nil
 	}
-	return nil
+	return runCommand(ctx, g.env.Reporter, projectPath, "go", "mod", "init", g.config.ModuleName)
 }
 
 // downloadDependencies downloads all project dependencies
-func (g *ProjectGenerator) downloadDependencies(projectPath string) error {
-	cmd := exec.Command("go", "mod", "tidy")
-	cmd.Dir = projectPath
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("go mod tidy failed: %w\n%s", err, output)
-	}
-	return nil
+func (g *ProjectGenerator) downloadDependencies(ctx context.Context, projectPath string) error {
+	return runCommand(ctx, g.env.Reporter, projectPath, "go", "mod", "tidy")
 }
 
 // initGit initializes a git repository
-func (g *ProjectGenerator) initGit(projectPath string) error {
-	cmd := exec.Command("git", "init")
-	cmd.Dir = projectPath
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git init failed: %w\n%s", err, output)
-	}
-	return nil
+func (g *ProjectGenerator) initGit(ctx context.Context, projectPath string) error {
+	return runCommand(ctx, g.env.Reporter, projectPath, "git", "init")
 }
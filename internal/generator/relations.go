@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// relation links a new entity to an existing one, through a foreign key
+// column (--belongs-to) or a join table (--many-to-many)
+type relation struct {
+	Entity      string // Order
+	EntityLower string // order
+	Table       string // orders
+	// Key is the referenced primary key column, and KeyType its SQL type
+	Key     string
+	KeyType string
+	// GoType is the Go type sqlc generates for the key, e.g. int32
+	GoType string
+	// Column references Key: order_id in the entity's table, or tag_id in the join table
+	Column string
+	// Field is the sqlc field name of Column, e.g. TagID
+	Field string
+	// JoinTable links both tables, e.g. post_tags (many-to-many only)
+	JoinTable string
+	// Path is the nested route segment: "items" in /v1/orders/:id/items
+	Path string
+}
+
+// resolveRelation looks up the table of the related entity, which must have
+// been created by an earlier migration with a single-column primary key
+func (g *EntityGenerator) resolveRelation(s *schema.Schema, name, field string) (relation, error) {
+	related := config.NewEntityConfig(name)
+	related.ProjectPath = g.config.ProjectPath
+	related.Process()
+
+	t := s.Table(related.TableName)
+	if t == nil {
+		return relation{}, &errs.ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("table %s of %s is not created by any migration", related.TableName, name),
+			Hint:    fmt.Sprintf("add it first: ready-go add entity %s", name),
+		}
+	}
+	if len(t.PrimaryKey) != 1 {
+		return relation{}, &errs.ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("table %s needs a single-column primary key to be referenced", t.Name),
+		}
+	}
+
+	key := t.Column(t.PrimaryKey[0])
+	goType, _, _ := goType(key)
+	column := related.EntityNameLower + "_id"
+	return relation{
+		Entity:      related.EntityName,
+		EntityLower: related.EntityNameLower,
+		Table:       t.Name,
+		Key:         key.Name,
+		KeyType:     keyType(key),
+		GoType:      goType,
+		Column:      column,
+		Field:       sqlcName(column),
+	}, nil
+}
+
+// keyType returns the SQL type of a referenced key column, e.g. BIGINT UNSIGNED
+func keyType(c *schema.Column) string {
+	typ := c.Type
+	if len(c.Args) > 0 {
+		typ += "(" + strings.Join(c.Args, ",") + ")"
+	}
+	if c.Unsigned {
+		typ += " UNSIGNED"
+	}
+	return typ
+}
@@ -0,0 +1,282 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+)
+
+// routeSetup is the function registering a project's routes, located through
+// the manifest's router setting (SetupHandler in handlers/handler.go by
+// default). Generators register routes by adding a call to it. The gRPC
+// service registration function is edited the same way.
+type routeSetup struct {
+	path string
+	src  []byte
+	fset *token.FileSet
+	file *ast.File
+	fn   *ast.FuncDecl
+
+	// Params is the parameter list as written, e.g.
+	// "ctx context.Context, router *fiber.App, svc *cmd.APIService"
+	Params string
+	// Args are the parameter names, in order
+	Args []string
+	// Ctx, Router and Svc name the context, router (or gRPC server) and
+	// APIService parameters; Ctx is empty when the function takes no context
+	Ctx, Router, Svc string
+
+	// http is the syntax of the project's router
+	http httpSyntax
+}
+
+// loadRouteSetup parses the route setup function of the project in
+// projectPath. The error explains why routes can't be registered
+// automatically, e.g. a router other than Fiber or an unexpected signature.
+func loadRouteSetup(projectPath string, router config.Router) (*routeSetup, error) {
+	if router.Framework != "" && !slices.Contains(config.Frameworks, router.Framework) {
+		return nil, fmt.Errorf("routes can't be registered with router %s; use one of %s", router.Framework, strings.Join(config.Frameworks, ", "))
+	}
+	if router.Setup == "" || router.Func == "" {
+		return nil, fmt.Errorf("%s does not name the route setup function", config.ManifestFile)
+	}
+
+	path := joinPath(projectPath, router.Setup)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", router.Setup, err)
+	}
+	r, err := parseSetup(path, src, router.Func)
+	if err != nil {
+		return nil, err
+	}
+	if r.Router == "" || r.Svc == "" {
+		return nil, fmt.Errorf("%s must take a router and the *cmd.APIService", router.Func)
+	}
+	r.http = newHTTPSyntax(router.Framework)
+	return r, nil
+}
+
+// loadServiceSetup parses the gRPC service registration function from src,
+// the content of the file at path
+func loadServiceSetup(path string, src []byte, grpc *config.GRPC) (*routeSetup, error) {
+	r, err := parseSetup(path, src, grpc.Func)
+	if err != nil {
+		return nil, err
+	}
+	if r.Router == "" || r.Svc == "" {
+		return nil, fmt.Errorf("%s must take the *grpc.Server and the *cmd.APIService", grpc.Func)
+	}
+	return r, nil
+}
+
+// parseSetup parses src, the content of the file at path, and finds the
+// setup function fn and its parameters
+func parseSetup(path string, src []byte, fn string) (*routeSetup, error) {
+	r, err := parseFile(path, src)
+	if err != nil {
+		return nil, err
+	}
+	r.fn = r.lookup(fn)
+	if r.fn == nil || r.fn.Body == nil {
+		return nil, fmt.Errorf("function %s not found in %s", fn, path)
+	}
+
+	for _, field := range r.fn.Type.Params.List {
+		typ := r.text(field.Type)
+		for _, name := range field.Names {
+			r.Args = append(r.Args, name.Name)
+			if name.Name == "_" {
+				continue
+			}
+			switch {
+			case typ == "context.Context":
+				r.Ctx = name.Name
+			case strings.HasSuffix(typ, ".App") || strings.HasSuffix(typ, ".Router"),
+				strings.HasSuffix(typ, ".Echo") || strings.HasSuffix(typ, ".Group") || strings.HasSuffix(typ, "Mux"),
+				typ == "*grpc.Server" || typ == "grpc.ServiceRegistrar":
+				r.Router = name.Name
+			case strings.HasSuffix(typ, "APIService"):
+				r.Svc = name.Name
+			}
+		}
+	}
+	params := r.fn.Type.Params
+	r.Params = string(src[r.offset(params.Opening)+1 : r.offset(params.Closing)])
+
+	return r, nil
+}
+
+// parseFile parses src, the content of the Go file at path, for edits that
+// don't belong to a function, e.g. adding a field to a struct
+func parseFile(path string, src []byte) (*routeSetup, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &routeSetup{path: path, src: src, fset: fset, file: file}, nil
+}
+
+// Route returns the registration of handler for method and path, a path in
+// Fiber syntax that is converted for the project's router:
+//
+//	{{.Setup.Route "GET" "/v1/orders/:id" "getHandler.Handle"}}
+func (r *routeSetup) Route(method, path, handler string) string {
+	return r.http.Route(r.Router, method, path, handler)
+}
+
+// defines reports whether the setup file declares a top-level function name
+func (r *routeSetup) defines(name string) bool {
+	return r.lookup(name) != nil
+}
+
+// register calls fn at the end of the setup function, appends decl (the
+// function being called) to the file and adds the missing imports
+func (r *routeSetup) register(fn string, decl []byte, imports ...string) (File, error) {
+	return r.apply([]edit{
+		{at: r.offset(r.fn.Body.Rbrace), text: fmt.Sprintf("\t%s(%s)\n", fn, strings.Join(r.Args, ", "))},
+		{at: len(r.src), text: "\n" + string(decl)},
+	}, imports)
+}
+
+// lookup returns the top-level function name declared in the setup file, or nil
+func (r *routeSetup) lookup(name string) *ast.FuncDecl {
+	for _, decl := range r.file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// replace swaps the declaration of the function name, including its doc
+// comment, for decl and adds the missing imports
+func (r *routeSetup) replace(name string, decl []byte, imports ...string) (File, error) {
+	fn := r.lookup(name)
+	if fn == nil {
+		return File{}, fmt.Errorf("function %s not found in %s", name, r.path)
+	}
+	start := fn.Pos()
+	if fn.Doc != nil {
+		start = fn.Doc.Pos()
+	}
+	return r.apply([]edit{{at: r.offset(start), text: string(decl), end: r.offset(fn.End())}}, imports)
+}
+
+// edit replaces the source from at to end with text; it inserts when end is zero
+type edit struct {
+	at   int
+	text string
+	end  int
+}
+
+// apply edits the setup file, adds the missing imports and formats the
+// result. The setup is reloaded, so later edits see the changes.
+func (r *routeSetup) apply(edits []edit, imports []string) (File, error) {
+	var missing, std []string
+	for _, imp := range imports {
+		// Imports are a path, or a name and a path: "ordersv1 example.com/gen/orders/v1"
+		name, path, named := strings.Cut(imp, " ")
+		if !named {
+			name, path = "", imp
+		}
+		if slices.ContainsFunc(r.file.Imports, func(spec *ast.ImportSpec) bool { return spec.Path.Value == strconv.Quote(path) }) {
+			continue
+		}
+		spec := strings.TrimSpace(name + " " + strconv.Quote(path))
+		if isStdImport(path) {
+			std = append(std, spec)
+		} else {
+			missing = append(missing, spec)
+		}
+	}
+	if len(missing)+len(std) > 0 {
+		// Add to the first grouped import, standard packages after the last
+		// standard one, or declare a new one after the package clause
+		at, text := r.offset(r.file.Name.End()), fmt.Sprintf("\n\nimport (\n%s\n)", strings.Join(append(std, missing...), "\n"))
+		for _, decl := range r.file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.IMPORT || !gen.Lparen.IsValid() {
+				continue
+			}
+			at, text = r.offset(gen.Rparen), ""
+			if len(missing) > 0 {
+				text = "\t" + strings.Join(missing, "\n\t") + "\n"
+			}
+			if len(std) > 0 {
+				stdAt := -1
+				for _, spec := range gen.Specs {
+					if path, _ := strconv.Unquote(spec.(*ast.ImportSpec).Path.Value); isStdImport(path) {
+						stdAt = r.offset(spec.End())
+					}
+				}
+				if stdAt >= 0 {
+					edits = append(edits, edit{at: stdAt, text: "\n\t" + strings.Join(std, "\n\t")})
+				} else {
+					text = "\t" + strings.Join(std, "\n\t") + "\n" + text
+				}
+			}
+			break
+		}
+		if text != "" {
+			edits = append(edits, edit{at: at, text: text})
+		}
+	}
+
+	slices.SortFunc(edits, func(a, b edit) int { return b.at - a.at })
+	out := slices.Clone(r.src)
+	for _, e := range edits {
+		if e.end > e.at {
+			out = slices.Delete(out, e.at, e.end)
+		}
+		out = slices.Insert(out, e.at, []byte(e.text)...)
+	}
+
+	formatted, err := format.Source(out)
+	if err != nil {
+		return File{}, fmt.Errorf("failed to edit %s: %w", r.path, err)
+	}
+	if err := r.reload(formatted); err != nil {
+		return File{}, err
+	}
+	return File{Path: r.path, Content: formatted, Edit: true}, nil
+}
+
+// reload parses src as the new content of the setup file
+func (r *routeSetup) reload(src []byte) error {
+	file, err := parser.ParseFile(r.fset, r.path, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", r.path, err)
+	}
+	r.src, r.file = src, file
+	if r.fn != nil {
+		r.fn = r.lookup(r.fn.Name.Name)
+	}
+	return nil
+}
+
+// text returns the source of node
+func (r *routeSetup) text(node ast.Node) string {
+	return string(r.src[r.offset(node.Pos()):r.offset(node.End())])
+}
+
+// offset converts pos to a byte offset in the source
+func (r *routeSetup) offset(pos token.Pos) int {
+	return r.fset.Position(pos).Offset
+}
+
+// isStdImport reports whether path is a standard library package, which has
+// no dot in its first element
+func isStdImport(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
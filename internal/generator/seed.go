@@ -0,0 +1,614 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// SeedGenerator writes a deterministic fake-data factory for an entity. The
+// first run also writes the shared seeds package, the seed command and the
+// `make seed` target.
+type SeedGenerator struct {
+	config *config.EntityConfig
+	env    *Env
+}
+
+// NewSeedGenerator creates a new SeedGenerator
+func NewSeedGenerator(cfg *config.EntityConfig, env *Env) *SeedGenerator {
+	return &SeedGenerator{
+		config: cfg,
+		env:    env,
+	}
+}
+
+// seedData is the data rendered by the seed templates
+type seedData struct {
+	ModuleName string
+	Layout     config.Layout
+	Entity     string
+	Name       string
+	Table      string
+	// Command is the sqlc command of the Create query, e.g. execresult
+	Command string
+	// Params is true when Create takes a Params struct rather than a single value
+	Params bool
+	// ParamType is the type New<Entity>Params returns: the Params struct, or
+	// the type of the only parameter
+	ParamType string
+	Fields    []seedField
+	DependsOn []string
+	// References lists the parent columns the foreign keys take their values
+	// from, e.g. customers.id
+	References []string
+	Imports    []string
+}
+
+// seedField is one argument of the Create query and the Go expression that fakes it
+type seedField struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// Generate reads the table from the migrations and the Create query from the
+// queries directory, then renders the factory
+func (g *SeedGenerator) Generate(ctx context.Context) error {
+	g.env.Reporter.Step("🔍 Reading migrations and queries...")
+	s, err := schema.Load(joinPath(g.config.ProjectPath, g.config.Layout.Migrations))
+	if err != nil {
+		return errs.Validation("fix the migration, or write the seeder by hand", "failed to replay migrations in %s: %v", g.config.Layout.Migrations, err)
+	}
+	table, err := lookupTable(s, g.config.TableName, "name")
+	if err != nil {
+		return err
+	}
+
+	query, err := g.findCreateQuery()
+	if err != nil {
+		return err
+	}
+
+	data, err := g.seedData(table, query)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	files, err := g.sharedFiles(data)
+	if err != nil {
+		return err
+	}
+
+	outputPath := joinPath(g.config.ProjectPath, g.config.Layout.Seeds(), g.config.EntityNameLower+".go")
+	file, err := g.env.renderGo("seed/entity.go.tmpl", outputPath, data)
+	if err != nil {
+		return fmt.Errorf("generate seeder: %w", err)
+	}
+
+	return g.env.Writer.WriteAll(append(files, file))
+}
+
+// sharedFiles renders the seeds package, the seed command and the Makefile
+// target, skipping whichever already exist so that every entity can be added
+// without going through the conflict policy
+func (g *SeedGenerator) sharedFiles(data seedData) ([]File, error) {
+	// refs marks the files able to run seeders with References: earlier
+	// versions filled foreign keys from the row index instead
+	shared := []struct{ template, path, refs string }{
+		{"seed/seeds.go.tmpl", joinPath(g.config.ProjectPath, g.config.Layout.Seeds(), "seeds.go"), "References []string"},
+		{"seed/faker.go.tmpl", joinPath(g.config.ProjectPath, g.config.Layout.Seeds(), "faker.go"), "func (f *Faker) RefInt("},
+		{"seed/main.go.tmpl", joinPath(g.config.ProjectPath, g.config.Layout.SeedCmd(), "main.go"), ""},
+	}
+
+	var files []File
+	for _, f := range shared {
+		if existing, err := os.ReadFile(f.path); err == nil {
+			if len(data.References) > 0 && f.refs != "" && !bytes.Contains(existing, []byte(f.refs)) {
+				g.env.Reporter.Warn(fmt.Sprintf("%s predates Faker.Ref, which the %s seeder uses", f.path, data.Name))
+				g.env.Reporter.NextStep(fmt.Sprintf("Delete %s and re-run `ready-go add seed %s` to regenerate it", f.path, data.Entity))
+			}
+			continue
+		}
+		file, err := g.env.render(f.template, f.path, data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	makefile := joinPath(g.config.ProjectPath, "Makefile")
+	if existing, err := os.ReadFile(makefile); err == nil && seedTarget.Match(existing) {
+		return files, nil
+	}
+	content, err := g.env.Templates.Render("seed/Makefile.tmpl", data)
+	if err != nil {
+		return nil, err
+	}
+	edit, err := appendFile(makefile, content)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, edit...), nil
+}
+
+var seedTarget = regexp.MustCompile(`(?m)^seed\s*:[^=]`)
+
+// createQuery is the INSERT behind a sqlc Create<Entity> query
+type createQuery struct {
+	Command string
+	// Params lists the inserted columns whose value is a placeholder, in order
+	Params []string
+}
+
+var (
+	queryName  = regexp.MustCompile(`(?m)^--\s*name:\s*(\w+)\s+:(\w+)\s*$`)
+	insertStmt = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+[\w."` + "`" + `]+\s*\((.*?)\)\s*VALUES\s*\((.*)\)`)
+)
+
+// findCreateQuery looks for Create<Entity> in the entity's query file first,
+// then in every other query file
+func (g *SeedGenerator) findCreateQuery() (*createQuery, error) {
+	name := "Create" + g.config.EntityName
+	dir := joinPath(g.config.ProjectPath, g.config.Layout.Queries)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queries directory: %w", err)
+	}
+	paths := []string{joinPath(dir, g.config.EntityNameLower+".sql")}
+	for _, entry := range entries {
+		if p := joinPath(dir, entry.Name()); !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") && !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+
+	for _, p := range paths {
+		src, err := os.ReadFile(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read queries: %w", err)
+		}
+		if q, err := parseCreateQuery(string(src), name); q != nil || err != nil {
+			return q, err
+		}
+	}
+
+	return nil, errs.Validation(
+		fmt.Sprintf("add a `-- name: %s :execresult` INSERT to %s, or run ready-go add entity %s", name, g.config.Layout.Queries, g.config.EntityName),
+		"query %s not found in %s", name, g.config.Layout.Queries)
+}
+
+// parseCreateQuery returns the query called name in src, or nil if src has none
+func parseCreateQuery(src, name string) (*createQuery, error) {
+	matches := queryName.FindAllStringSubmatchIndex(src, -1)
+	for i, m := range matches {
+		if src[m[2]:m[3]] != name {
+			continue
+		}
+		end := len(src)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		body := strings.TrimRight(strings.TrimSpace(src[m[1]:end]), ";")
+
+		insert := insertStmt.FindStringSubmatch(body)
+		if insert == nil {
+			return nil, errs.Validation("seeders need a single-row INSERT INTO t (columns) VALUES (...)", "query %s is not an INSERT with a column list", name)
+		}
+		columns, values := splitTopLevel(insert[1]), splitTopLevel(insert[2])
+		if len(columns) != len(values) {
+			return nil, errs.Validation("seeders need a single-row INSERT", "query %s inserts %d columns but has %d values", name, len(columns), len(values))
+		}
+
+		q := &createQuery{Command: src[m[4]:m[5]]}
+		for j, v := range values {
+			if isPlaceholder(v) {
+				q.Params = append(q.Params, strings.Trim(columns[j], "`\""))
+			}
+		}
+		return q, nil
+	}
+	return nil, nil
+}
+
+// splitTopLevel splits a SQL list on commas outside parentheses and quotes
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+var numberedPlaceholder = regexp.MustCompile(`^\$\d+$`)
+
+// isPlaceholder reports whether a VALUES item is a bound parameter: ?, $1 or sqlc.arg(x)
+func isPlaceholder(v string) bool {
+	v = strings.TrimSpace(v)
+	return v == "?" || numberedPlaceholder.MatchString(v) || strings.HasPrefix(strings.ToLower(v), "sqlc.arg(")
+}
+
+// seedData pairs each Create parameter with a fake value for its column
+func (g *SeedGenerator) seedData(t *schema.Table, q *createQuery) (seedData, error) {
+	data := seedData{
+		ModuleName: g.config.ModuleName,
+		Layout:     g.config.Layout,
+		Entity:     g.config.EntityName,
+		Name:       g.config.EntityNameLower,
+		Table:      t.Name,
+		Command:    q.Command,
+		Params:     len(q.Params) > 1,
+	}
+	imports := map[string]bool{}
+
+	for _, name := range q.Params {
+		c := t.Column(name)
+		if c == nil {
+			return seedData{}, errs.Validation("regenerate the queries or fix the column name", "query Create%s inserts %s, which table %s does not have", g.config.EntityName, name, t.Name)
+		}
+		field := g.fakeValue(t, c)
+		if strings.HasPrefix(field.Value, "sql.") {
+			imports["database/sql"] = true
+		}
+		data.Fields = append(data.Fields, field)
+	}
+
+	switch {
+	case data.Params:
+		data.ParamType = fmt.Sprintf("%s.Create%sParams", g.config.Layout.ModelsPackage(), g.config.EntityName)
+	case len(data.Fields) == 1:
+		data.ParamType = data.Fields[0].Type
+		if pkg := typePackage(data.ParamType); pkg != "" {
+			imports[pkg] = true
+		}
+	}
+
+	for _, fk := range t.ForeignKeys {
+		if fk.RefTable != t.Name && !slices.Contains(data.DependsOn, fk.RefTable) {
+			data.DependsOn = append(data.DependsOn, fk.RefTable)
+		}
+	}
+	for _, name := range q.Params {
+		if ref := reference(t, t.Column(name)); ref != "" && !slices.Contains(data.References, ref) {
+			data.References = append(data.References, ref)
+		}
+	}
+	for p := range imports {
+		data.Imports = append(data.Imports, p)
+	}
+	slices.Sort(data.Imports)
+	return data, nil
+}
+
+// fakeValue returns the sqlc parameter for column c with a Go expression
+// faking it. The expression may use f (*Faker), i (the row index) and n (the
+// number of rows).
+func (g *SeedGenerator) fakeValue(t *schema.Table, c *schema.Column) seedField {
+	models := g.config.Layout.ModelsPackage()
+	field := seedField{Name: sqlcName(c.Name)}
+
+	if c.IsEnum() {
+		values := make([]string, len(c.Args))
+		for i, v := range c.Args {
+			values[i] = strconv.Quote(v)
+		}
+		// sqlc names enum types after the table and column: products.status → ProductsStatus
+		enum := sqlcName(t.Name + "_" + c.Name)
+		value := fmt.Sprintf("%s.%s(f.Pick(%s))", models, enum, strings.Join(values, ", "))
+		if c.Nullable {
+			field.Type = fmt.Sprintf("%s.Null%s", models, enum)
+			field.Value = fmt.Sprintf("%s{%s: %s, Valid: true}", field.Type, enum, value)
+			return field
+		}
+		field.Type, field.Value = models+"."+enum, value
+		return field
+	}
+
+	typ, nullType := g.sqlcType(c)
+	if c.Nullable && nullType != "" {
+		// sql.NullInt16 holds an int16 in its Int16 field, and so on
+		valueField := strings.TrimPrefix(nullType, "sql.Null")
+		inner := strings.ToLower(valueField)
+		if inner == "time" {
+			inner = "time.Time"
+		}
+		field.Type = nullType
+		field.Value = fmt.Sprintf("%s{%s: %s, Valid: true}", nullType, valueField, fakeExpr(t, c, inner))
+		return field
+	}
+	field.Type, field.Value = typ, fakeExpr(t, c, typ)
+	return field
+}
+
+// typePackage returns the import path a Go type from goType needs
+func typePackage(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "sql."):
+		return "database/sql"
+	case strings.HasPrefix(typ, "time."):
+		return "time"
+	case strings.HasPrefix(typ, "json."):
+		return "encoding/json"
+	}
+	return ""
+}
+
+// sqlcType returns the Go type sqlc generates for a column parameter. It
+// follows goType except where sqlc's MySQL mapping differs.
+func (g *SeedGenerator) sqlcType(c *schema.Column) (typ, nullType string) {
+	typ, nullType, _ = goType(c)
+	switch {
+	case c.Type == "TIME":
+		return "time.Time", "sql.NullTime"
+	case g.config.Engine == "mysql" && (c.Type == "FLOAT" || c.Type == "REAL"):
+		return "float64", "sql.NullFloat64"
+	}
+	return typ, nullType
+}
+
+var personTable = regexp.MustCompile(`user|customer|person|people|author|member|employee|contact|account|profile`)
+
+// fakeExpr picks a Faker call of Go type typ for column c, guided by its name
+func fakeExpr(t *schema.Table, c *schema.Column, typ string) string {
+	name := strings.ToLower(c.Name)
+	unique := isUnique(t, c)
+	has := func(words ...string) bool {
+		for _, w := range words {
+			if name == w || strings.HasPrefix(name, w+"_") || strings.HasSuffix(name, "_"+w) || strings.Contains(name, "_"+w+"_") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Foreign keys take the key of a parent row, seeded first
+	if ref := reference(t, c); ref != "" {
+		switch {
+		case strings.HasPrefix(typ, "int") || strings.HasPrefix(typ, "uint"):
+			return fmt.Sprintf("%s(f.RefInt(%q))", typ, ref)
+		case typ == "string":
+			return fmt.Sprintf("f.Ref(%q)", ref)
+		}
+	}
+
+	switch typ {
+	case "bool":
+		return "f.Bool()"
+	case "float64":
+		return "f.Float(1, 1000)"
+	case "float32":
+		return "float32(f.Float(1, 1000))"
+	case "time.Time":
+		if c.Type == "DATE" {
+			return "f.Date()"
+		}
+		return "f.Time()"
+	case "json.RawMessage":
+		return "f.JSON()"
+	case "[]byte":
+		return "f.Bytes(16)"
+	}
+
+	if strings.HasPrefix(typ, "int") || strings.HasPrefix(typ, "uint") {
+		lo, hi := "1", "1000"
+		switch {
+		case isForeignKey(t, c) || strings.HasSuffix(name, "_id"):
+			// An id without a single-column foreign key: ids 1..n exist when
+			// the table it names was seeded with the same n in a fresh database
+			lo, hi = "1", "n"
+		case unique:
+			return fmt.Sprintf("%s(i + 1)", typ)
+		case has("quantity", "qty", "stock", "count", "position", "sort", "rank"):
+			lo, hi = "0", "100"
+		case has("age"):
+			lo, hi = "18", "80"
+		case has("year"):
+			lo, hi = "1990", "2030"
+		case typ == "int8" || typ == "uint8":
+			lo, hi = "1", "100"
+		}
+		return fmt.Sprintf("%s(f.IntBetween(%s, %s))", typ, lo, hi)
+	}
+
+	var value string
+	switch {
+	case c.Type == "DECIMAL" || c.Type == "NUMERIC" || c.Type == "DEC" || c.Type == "FIXED":
+		value = fmt.Sprintf("f.Decimal(%d, %d)", decimalMax(c), decimalScale(c))
+	case strings.Contains(name, "email"):
+		value = "f.Email(i)"
+	case has("first_name", "firstname", "given_name"):
+		value = "f.FirstName()"
+	case has("last_name", "lastname", "surname", "family_name"):
+		value = "f.LastName()"
+	case has("username", "user_name", "login", "handle", "nickname"):
+		value = "f.Username(i)"
+	case has("name", "full_name", "display_name"):
+		if personTable.MatchString(t.Name) {
+			value = "f.Name()"
+		} else {
+			value = "f.Title(2)"
+		}
+	case has("title", "subject", "headline"):
+		value = "f.Title(4)"
+	case has("description", "body", "content", "bio", "summary", "notes", "note", "comment", "message", "text") || strings.HasSuffix(c.Type, "TEXT"):
+		value = "f.Sentence(12)"
+	case has("phone", "mobile", "telephone"):
+		value = "f.Phone()"
+	case has("url", "website", "link", "avatar", "image", "photo", "homepage"):
+		value = "f.URL(i)"
+	case has("slug"):
+		value = "f.Slug(i)"
+	case has("sku", "code", "reference", "ref", "number"):
+		value = fmt.Sprintf("f.Code(%q, i)", strings.ToUpper(strings.SplitN(name, "_", 2)[0]))
+	case has("currency"):
+		value = `f.Pick("USD", "EUR", "GBP", "MYR")`
+	case has("country"):
+		value = "f.Country()"
+	case has("city"):
+		value = "f.City()"
+	case has("address", "street"):
+		value = "f.Street()"
+	case has("password", "hash", "digest", "secret"):
+		value = "f.Hex(32)"
+	case has("uuid", "guid", "token") || c.Type == "UUID" || (c.Type == "CHAR" && len(c.Args) == 1 && c.Args[0] == "36"):
+		value = "f.UUID()"
+	case has("ip", "ip_address"):
+		value = "f.IPv4()"
+	case has("color", "colour"):
+		value = `f.Pick("red", "green", "blue", "black", "white")`
+	default:
+		value = "f.Word()"
+	}
+
+	size := stringSize(c)
+	// Generators taking i are unique already; others get a suffix, which
+	// must survive truncation
+	if unique && !strings.Contains(value, "i)") {
+		if size > uniqueSuffix {
+			value = fmt.Sprintf("Truncate(%s, %d)", value, size-uniqueSuffix)
+		}
+		return fmt.Sprintf("f.Unique(%s, i)", value)
+	}
+	if size > 0 {
+		value = fmt.Sprintf("Truncate(%s, %d)", value, size)
+	}
+	return value
+}
+
+// uniqueSuffix is the room left for the " 12345" suffix added by Faker.Unique
+const uniqueSuffix = 6
+
+// isUnique reports whether c alone is the primary key or a unique index of t
+func isUnique(t *schema.Table, c *schema.Column) bool {
+	if len(t.PrimaryKey) == 1 && t.PrimaryKey[0] == c.Name {
+		return true
+	}
+	for _, idx := range t.Indexes {
+		if idx.Unique && len(idx.Columns) == 1 && idx.Columns[0] == c.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// isForeignKey reports whether c references another table
+func isForeignKey(t *schema.Table, c *schema.Column) bool {
+	for _, fk := range t.ForeignKeys {
+		if slices.Contains(fk.Columns, c.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// reference returns the parent column c takes its values from, e.g.
+// customers.id, when c alone is a foreign key to another table
+func reference(t *schema.Table, c *schema.Column) string {
+	if c == nil {
+		return ""
+	}
+	for _, fk := range t.ForeignKeys {
+		if len(fk.Columns) != 1 || fk.Columns[0] != c.Name || fk.RefTable == t.Name {
+			continue
+		}
+		column := "id"
+		if len(fk.RefColumns) == 1 {
+			column = fk.RefColumns[0]
+		}
+		return fk.RefTable + "." + column
+	}
+	return ""
+}
+
+// stringSize returns the length limit of a short CHAR or VARCHAR column, or 0
+// when generated values always fit
+func stringSize(c *schema.Column) int {
+	if c.Type != "CHAR" && c.Type != "VARCHAR" && c.Type != "CHARACTER VARYING" || len(c.Args) != 1 {
+		return 0
+	}
+	size, err := strconv.Atoi(c.Args[0])
+	if err != nil || size >= 100 {
+		return 0
+	}
+	return size
+}
+
+// decimalScale returns the number of fractional digits of a DECIMAL(p,s) column
+func decimalScale(c *schema.Column) int {
+	if len(c.Args) == 2 {
+		if s, err := strconv.Atoi(c.Args[1]); err == nil {
+			return s
+		}
+	}
+	if len(c.Args) == 1 {
+		return 0
+	}
+	return 2
+}
+
+// decimalMax returns the largest whole part a DECIMAL(p,s) column holds, capped at 1000
+func decimalMax(c *schema.Column) int {
+	if len(c.Args) == 0 {
+		return 1000
+	}
+	p, err := strconv.Atoi(c.Args[0])
+	if err != nil {
+		return 1000
+	}
+	max := 1
+	for range p - decimalScale(c) {
+		if max >= 1000 {
+			break
+		}
+		max *= 10
+	}
+	return min(max-1, 1000)
+}
+
+// sqlcName converts a column or table name to the field name sqlc generates:
+// each _-separated part is title-cased, and only "id" becomes an initialism
+func sqlcName(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		if part == "id" {
+			b.WriteString("ID")
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
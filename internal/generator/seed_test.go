@@ -0,0 +1,247 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/templates"
+)
+
+// seedProject writes the customers and orders seeders of a project, with
+// stubs of the sqlc models that print the rows they insert, and builds a
+// command running the seeders as `seed SEED ONLY` against an in-memory SQLite
+// database enforcing foreign keys
+func seedProject(t *testing.T) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds the generated seeders")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found")
+	}
+	// The command uses modernc.org/sqlite as required by this module
+	goMod, err := os.ReadFile(filepath.Join("..", "..", "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := os.ReadFile(filepath.Join("..", "..", "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, requires, _ := strings.Cut(string(goMod), "\nrequire")
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.24\n\nrequire" + requires,
+		"go.sum": string(goSum),
+		"database/migrations/00001_create_customers.sql": `-- +goose Up
+CREATE TABLE customers (id BIGINT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(64) NOT NULL);
+`,
+		"database/migrations/00002_create_orders.sql": `-- +goose Up
+CREATE TABLE orders (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  customer_id BIGINT NOT NULL,
+  note VARCHAR(32) NOT NULL,
+  FOREIGN KEY (customer_id) REFERENCES customers (id)
+);
+`,
+		"database/queries/customer.sql": "-- name: CreateCustomer :exec\nINSERT INTO customers (name) VALUES (?);\n",
+		"database/queries/order.sql":    "-- name: CreateOrder :exec\nINSERT INTO orders (customer_id, note) VALUES (?, ?);\n",
+		// What sqlc generates for the queries, printing the rows
+		"internal/models/models.go": `package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type DBTX interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+}
+
+type Queries struct{}
+
+func New() *Queries { return &Queries{} }
+
+type CreateOrderParams struct {
+	CustomerID int64
+	Note       string
+}
+
+func (q *Queries) CreateCustomer(ctx context.Context, db DBTX, name string) error {
+	fmt.Printf("customers %q\n", name)
+	_, err := db.ExecContext(ctx, "INSERT INTO customers (name) VALUES (?)", name)
+	return err
+}
+
+func (q *Queries) CreateOrder(ctx context.Context, db DBTX, arg CreateOrderParams) error {
+	fmt.Printf("orders %d %q\n", arg.CustomerID, arg.Note)
+	_, err := db.ExecContext(ctx, "INSERT INTO orders (customer_id, note) VALUES (?, ?)", arg.CustomerID, arg.Note)
+	return err
+}
+`,
+		"cmd/check/main.go": `package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"example.com/p/database/seeds"
+)
+
+// schema numbers customers from 101, so seeders guessing ids 1..n fail
+const schema = ` + "`" + `
+CREATE TABLE customers (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL);
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  customer_id INTEGER NOT NULL REFERENCES customers (id),
+  note TEXT NOT NULL
+);
+INSERT INTO sqlite_sequence (name, seq) VALUES ('customers', 100);
+` + "`" + `
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	seed, _ := strconv.ParseUint(os.Args[1], 10, 64)
+	var names []string
+	if os.Args[2] != "" {
+		names = strings.Split(os.Args[2], ",")
+	}
+	seeders, err := seeds.Select(names...)
+	if err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+	return seeds.Run(context.Background(), db, seeders, 3, seed)
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	env, err := NewEnv(templates.FS, ConflictFail, nil, report.Discard("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entity := range []string{"Customer", "Order"} {
+		cfg := config.NewEntityConfig(entity)
+		cfg.ProjectPath = dir
+		cfg.ModuleName = "example.com/p"
+		cfg.Engine = "mysql"
+		cfg.Process()
+		if err := NewSeedGenerator(cfg, env).Generate(context.Background()); err != nil {
+			t.Fatalf("seed %s: %v", entity, err)
+		}
+	}
+
+	bin := filepath.Join(dir, "seed")
+	build := exec.Command(goBin, "build", "-o", bin, "./cmd/check")
+	build.Dir = dir
+	build.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod", "GOTOOLCHAIN=local")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("generated seeders don't build: %v\n%s", err, out)
+	}
+	return bin
+}
+
+func TestSeedSelection(t *testing.T) {
+	bin := seedProject(t)
+	run := func(t *testing.T, seed, only string) (string, error) {
+		t.Helper()
+		out, err := exec.Command(bin, seed, only).CombinedOutput()
+		return string(out), err
+	}
+
+	t.Run("same seed, same rows", func(t *testing.T) {
+		first, err := run(t, "7", "")
+		if err != nil {
+			t.Fatalf("%v\n%s", err, first)
+		}
+		second, err := run(t, "7", "")
+		if err != nil {
+			t.Fatalf("%v\n%s", err, second)
+		}
+		if first != second {
+			t.Errorf("seed 7 inserted different rows:\n%s\nthen:\n%s", first, second)
+		}
+		other, err := run(t, "8", "")
+		if err != nil {
+			t.Fatalf("%v\n%s", err, other)
+		}
+		if other == first {
+			t.Errorf("seeds 7 and 8 inserted the same rows:\n%s", first)
+		}
+	})
+
+	tests := []struct {
+		name, only string
+		// tables lists the table of each inserted row, in order
+		tables  []string
+		wantErr string
+	}{
+		{name: "all", tables: []string{"customers", "customers", "customers", "orders", "orders", "orders"}},
+		{name: "parents first", only: "order,customer", tables: []string{"customers", "customers", "customers", "orders", "orders", "orders"}},
+		{name: "one", only: " Customer ", tables: []string{"customers", "customers", "customers"}},
+		{name: "without its parent", only: "order", wantErr: "seed orders: customers has no rows to reference; seed it too"},
+		{name: "unknown", only: "customer,invoice", wantErr: `unknown seeder "invoice" (known: customer, order)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := run(t, "1", tt.only)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(out, tt.wantErr) {
+					t.Fatalf("err = %v, output:\n%s\nwant an error containing %q", err, out, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%v\n%s", err, out)
+			}
+			var tables []string
+			for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+				table, _, _ := strings.Cut(line, " ")
+				tables = append(tables, table)
+			}
+			if strings.Join(tables, " ") != strings.Join(tt.tables, " ") {
+				t.Errorf("rows:\n%s\nwant tables %q", out, tt.tables)
+			}
+		})
+	}
+}
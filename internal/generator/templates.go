@@ -1,114 +1,135 @@
 package generator
 
 import (
-	"embed"
+	"bytes"
 	"fmt"
-	"os"
+	"io/fs"
+	"slices"
+	"strings"
 	"text/template"
 
-	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
 )
 
-var embeddedFS embed.FS
+const templateHint = "check the template for syntax errors, or drop custom templates to use the bundled ones"
 
-// SetEmbeddedTemplates sets the embedded filesystem for templates
-func SetEmbeddedTemplates(fs embed.FS) {
-	embeddedFS = fs
-}
+// partialsDir holds shared {{define}} blocks available to every template
+const partialsDir = "_partials"
 
-// TemplateRenderer handles rendering of template files
-type TemplateRenderer struct {
-	config *config.ProjectConfig
+// Templates loads and renders templates from a filesystem. Template names are
+// slash-separated paths relative to the root of that filesystem.
+type Templates struct {
+	fsys fs.FS
 }
 
-// NewTemplateRenderer creates a new TemplateRenderer
-func NewTemplateRenderer(cfg *config.ProjectConfig) *TemplateRenderer {
-	return &TemplateRenderer{
-		config: cfg,
+// NewTemplates creates a Templates that reads from fsys
+func NewTemplates(fsys fs.FS) *Templates {
+	return &Templates{
+		fsys: fsys,
 	}
 }
 
-// RenderToFile renders a template to a file
-func (r *TemplateRenderer) RenderToFile(templateName, outputPath string) error {
-	// Read template content
-	content, err := r.getTemplateContent(templateName)
+// Content retrieves the raw content of a template
+func (t *Templates) Content(templateName string) (string, error) {
+	data, err := fs.ReadFile(t.fsys, templateName)
 	if err != nil {
-		return fmt.Errorf("failed to get template %s: %w", templateName, err)
+		return "", &errs.TemplateError{Template: templateName, Err: err, Hint: templateHint}
 	}
+	return string(data), nil
+}
 
-	// Parse and execute template
-	tmpl, err := template.New(templateName).Parse(content)
+// Parse parses a template together with all shared partials
+func (t *Templates) Parse(templateName string) (*template.Template, error) {
+	content, err := t.Content(templateName)
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+		return nil, err
 	}
 
-	// Create output file
-	file, err := os.Create(outputPath)
+	partials, err := fs.Glob(t.fsys, partialsDir+"/*.tmpl")
 	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", outputPath, err)
-	}
-	defer file.Close()
-
-	// Execute template
-	if err := tmpl.Execute(file, r.config); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return nil
-}
-
-// getTemplateContent retrieves template content from embedded filesystem
-func (r *TemplateRenderer) getTemplateContent(templateName string) (string, error) {
-	return GetTemplateContent(templateName)
-}
-
-// GetTemplateContent retrieves template content from embedded filesystem (standalone function)
-func GetTemplateContent(templateName string) (string, error) {
-	// Try to read from embedded filesystem first
-	path := "templates/" + templateName
-	data, err := embeddedFS.ReadFile(path)
-	if err == nil {
-		return string(data), nil
+		return nil, &errs.TemplateError{Template: partialsDir, Err: err, Hint: templateHint}
 	}
 
-	// Fallback to reading from disk (for development)
-	diskPaths := []string{
-		"templates/" + templateName,
-		"../../templates/" + templateName,
-		templateName,
+	tmpl := template.New(templateName).Funcs(templateFuncs())
+	for _, partial := range partials {
+		data, err := fs.ReadFile(t.fsys, partial)
+		if err != nil {
+			return nil, &errs.TemplateError{Template: partial, Err: err, Hint: templateHint}
+		}
+		if _, err := tmpl.New(partial).Parse(string(data)); err != nil {
+			return nil, &errs.TemplateError{Template: partial, Err: err, Hint: templateHint}
+		}
 	}
 
-	for _, diskPath := range diskPaths {
-		data, err := os.ReadFile(diskPath)
-		if err == nil {
-			return string(data), nil
-		}
+	if _, err := tmpl.Parse(content); err != nil {
+		return nil, &errs.TemplateError{Template: templateName, Err: err, Hint: templateHint}
 	}
 
-	return "", fmt.Errorf("template %s not found in embedded FS or disk", templateName)
+	return tmpl, nil
 }
 
-// RenderTemplateToFile renders a template with arbitrary data to a file
-func RenderTemplateToFile(templateName, outputPath string, data any) error {
-	content, err := GetTemplateContent(templateName)
+// Render renders a template with arbitrary data
+func (t *Templates) Render(templateName string, data any) ([]byte, error) {
+	tmpl, err := t.Parse(templateName)
 	if err != nil {
-		return fmt.Errorf("failed to get template %s: %w", templateName, err)
+		return nil, err
 	}
 
-	tmpl, err := template.New(templateName).Parse(content)
-	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, &errs.TemplateError{Template: templateName, Err: err, Hint: templateHint}
 	}
 
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", outputPath, err)
-	}
-	defer file.Close()
+	return buf.Bytes(), nil
+}
 
-	if err := tmpl.Execute(file, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+// templateFuncs returns the helper functions available to templates and partials
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// dict builds a map from alternating key/value pairs so partials can take named arguments
+		"dict": func(pairs ...any) (map[string]any, error) {
+			if len(pairs)%2 != 0 {
+				return nil, fmt.Errorf("dict requires an even number of arguments")
+			}
+			m := make(map[string]any, len(pairs)/2)
+			for i := 0; i < len(pairs); i += 2 {
+				key, ok := pairs[i].(string)
+				if !ok {
+					return nil, fmt.Errorf("dict keys must be strings, got %T", pairs[i])
+				}
+				m[key] = pairs[i+1]
+			}
+			return m, nil
+		},
+		"list": func(items ...string) []string {
+			return items
+		},
+		"join": strings.Join,
+		"has":  slices.Contains[[]string],
+		// router returns the handler syntax of a router framework
+		"router": newHTTPSyntax,
+		// gooseDriver maps a sqlc engine name to the goose dialect name
+		"gooseDriver": func(engine string) string {
+			switch engine {
+			case "postgresql":
+				return "postgres"
+			case "sqlite":
+				return "sqlite3"
+			default:
+				return engine
+			}
+		},
+		// placeholders returns n comma-separated "?" bind parameters
+		"placeholders": func(n int) string {
+			return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+		},
+		// assignments returns "col = ?" pairs for an UPDATE statement
+		"assignments": func(columns []string) string {
+			parts := make([]string, len(columns))
+			for i, col := range columns {
+				parts[i] = col + " = ?"
+			}
+			return strings.Join(parts, ", ")
+		},
 	}
-
-	return nil
 }
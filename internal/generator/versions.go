@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// VersionChecker validates and repairs migration version numbers
+type VersionChecker struct {
+	config *config.MigrationConfig
+	env    *Env
+}
+
+// NewVersionChecker creates a new VersionChecker
+func NewVersionChecker(cfg *config.MigrationConfig, env *Env) *VersionChecker {
+	return &VersionChecker{
+		config: cfg,
+		env:    env,
+	}
+}
+
+// Check reports duplicate versions, out-of-order additions, gaps and
+// migrations that don't follow the configured versioning
+func (g *VersionChecker) Check(ctx context.Context) ([]schema.Problem, error) {
+	migrations, err := g.migrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return schema.CheckVersions(migrations, g.config.Versioning), nil
+}
+
+// Renumber moves duplicate and out-of-order migrations after the others.
+// With dryRun it only reports the planned renames.
+func (g *VersionChecker) Renumber(ctx context.Context, dryRun bool) ([]schema.Rename, error) {
+	migrations, err := g.migrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	renames := schema.Renumber(migrations, g.config.Versioning, time.Now())
+	for _, r := range renames {
+		to := filepath.Join(filepath.Dir(r.From.Path), r.To)
+		if dryRun {
+			g.env.Reporter.Info(fmt.Sprintf("  would rename %s → %s", filepath.Base(r.From.Path), r.To))
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(r.From.Path, to); err != nil {
+			return nil, fmt.Errorf("failed to rename %s: %w", filepath.Base(r.From.Path), err)
+		}
+		g.env.Reporter.FileRenamed(r.From.Path, to)
+	}
+	return renames, nil
+}
+
+// migrations lists the project's migrations, annotated with when git first
+// saw each one. Files git doesn't know about yet count as added after every commit.
+func (g *VersionChecker) migrations(ctx context.Context) ([]schema.Migration, error) {
+	dir := joinPath(g.config.ProjectPath, g.config.Layout.Migrations)
+	migrations, err := schema.Migrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	added, ok := gitAddedTimes(ctx, dir)
+	if !ok {
+		g.env.Reporter.Info("ℹ️  No git history for the migrations; skipping the out-of-order check")
+		return migrations, nil
+	}
+
+	for i, m := range migrations {
+		if t, found := added[filepath.Base(m.Path)]; found {
+			migrations[i].Added = t
+		} else {
+			migrations[i].Added = math.MaxInt64
+		}
+	}
+	return migrations, nil
+}
+
+// gitAddedTimes returns, for each file in dir, the author time of the commit
+// that most recently added it. ok is false when dir is not in a git repository.
+func gitAddedTimes(ctx context.Context, dir string) (map[string]int64, bool) {
+	cmd := exec.CommandContext(ctx, "git", "log", "--no-renames", "--diff-filter=A", "--format=%x00%at", "--name-only", "--relative", "--", ".")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, false
+	}
+
+	added := map[string]int64{}
+	var current int64
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "\x00"):
+			current, _ = strconv.ParseInt(strings.TrimPrefix(line, "\x00"), 10, 64)
+		default:
+			// git log lists newest commits first; keep the latest addition
+			if _, seen := added[line]; !seen && !strings.Contains(line, "/") {
+				added[line] = current
+			}
+		}
+	}
+	return added, true
+}
@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+)
+
+// ConflictPolicy decides what happens when a generated file already exists
+type ConflictPolicy string
+
+const (
+	// ConflictFail aborts before anything is written
+	ConflictFail ConflictPolicy = "fail"
+	// ConflictSkip keeps the existing file
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite replaces the existing file
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictPrompt asks a ConflictResolver, file by file, after showing a diff
+	ConflictPrompt ConflictPolicy = "prompt"
+)
+
+const conflictHint = "use --force to overwrite, --skip-existing to keep existing files, or --interactive to decide per file"
+
+// Resolution is a ConflictResolver's answer for one file
+type Resolution int
+
+const (
+	ResolveSkip Resolution = iota
+	ResolveOverwrite
+	// ResolveOverwriteAll overwrites this and every later conflicting file
+	ResolveOverwriteAll
+	// ResolveAbort stops generation before anything is written
+	ResolveAbort
+)
+
+// ConflictResolver decides what to do with an existing file under ConflictPrompt
+type ConflictResolver interface {
+	Resolve(path, diff string) (Resolution, error)
+}
+
+// File is a rendered file waiting to be written
+type File struct {
+	Path    string
+	Content []byte
+	// Edit marks a deliberate change to an existing file, such as appending
+	// Makefile targets. Edits are applied whatever the policy, except that
+	// ConflictPrompt still asks first.
+	Edit bool
+}
+
+// Writer writes rendered files to disk, applying a ConflictPolicy to files that already exist
+type Writer struct {
+	policy   ConflictPolicy
+	resolver ConflictResolver
+	reporter report.Reporter
+}
+
+// NewWriter creates a Writer. resolver is only consulted under ConflictPrompt.
+func NewWriter(policy ConflictPolicy, resolver ConflictResolver, r report.Reporter) (*Writer, error) {
+	switch policy {
+	case "":
+		policy = ConflictFail
+	case ConflictFail, ConflictSkip, ConflictOverwrite:
+	case ConflictPrompt:
+		if resolver == nil {
+			return nil, &errs.ValidationError{Field: "conflict", Message: "the prompt conflict policy needs a resolver"}
+		}
+	default:
+		return nil, &errs.ValidationError{Field: "conflict", Message: fmt.Sprintf("unknown conflict policy %q (expected fail, skip, overwrite or prompt)", policy)}
+	}
+
+	return &Writer{
+		policy:   policy,
+		resolver: resolver,
+		reporter: r,
+	}, nil
+}
+
+// Policy returns the writer's conflict policy
+func (w *Writer) Policy() ConflictPolicy {
+	return w.policy
+}
+
+// WriteAll writes files after resolving every conflict, so that a failed or
+// aborted run leaves the project untouched
+func (w *Writer) WriteAll(files []File) error {
+	actions := make([]fileAction, 0, len(files))
+
+	for _, file := range files {
+		action, err := w.plan(file)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, action)
+	}
+
+	for _, action := range actions {
+		switch action.kind {
+		case actionSkip:
+			w.reporter.FileSkipped(action.file.Path, action.reason)
+			continue
+		}
+
+		if err := writeFile(action.file); err != nil {
+			return err
+		}
+
+		switch action.kind {
+		case actionOverwrite:
+			w.reporter.FileOverwritten(action.file.Path)
+		case actionUpdate:
+			w.reporter.FileUpdated(action.file.Path)
+		default:
+			w.reporter.FileCreated(action.file.Path)
+		}
+	}
+
+	return nil
+}
+
+// Write writes a single file under the writer's policy
+func (w *Writer) Write(path string, content []byte) error {
+	return w.WriteAll([]File{{Path: path, Content: content}})
+}
+
+type actionKind int
+
+const (
+	actionCreate actionKind = iota
+	actionOverwrite
+	actionUpdate
+	actionSkip
+)
+
+type fileAction struct {
+	file   File
+	kind   actionKind
+	reason string
+}
+
+// plan decides what to do with one file without touching the disk
+func (w *Writer) plan(file File) (fileAction, error) {
+	existing, err := os.ReadFile(file.Path)
+	if os.IsNotExist(err) {
+		return fileAction{file: file, kind: actionCreate}, nil
+	}
+	if err != nil {
+		return fileAction{}, fmt.Errorf("failed to read %s: %w", file.Path, err)
+	}
+
+	if bytes.Equal(existing, file.Content) {
+		return fileAction{file: file, kind: actionSkip, reason: "unchanged"}, nil
+	}
+
+	overwrite := actionOverwrite
+	if file.Edit {
+		overwrite = actionUpdate
+		if w.policy != ConflictPrompt {
+			return fileAction{file: file, kind: actionUpdate}, nil
+		}
+	}
+
+	switch w.policy {
+	case ConflictSkip:
+		return fileAction{file: file, kind: actionSkip, reason: "already exists"}, nil
+	case ConflictOverwrite:
+		return fileAction{file: file, kind: actionOverwrite}, nil
+	case ConflictPrompt:
+		resolution, err := w.resolver.Resolve(file.Path, Diff(file.Path, string(existing), string(file.Content)))
+		if err != nil {
+			return fileAction{}, err
+		}
+		switch resolution {
+		case ResolveOverwriteAll:
+			w.policy = ConflictOverwrite
+			return fileAction{file: file, kind: overwrite}, nil
+		case ResolveOverwrite:
+			return fileAction{file: file, kind: overwrite}, nil
+		case ResolveSkip:
+			return fileAction{file: file, kind: actionSkip, reason: "kept by user"}, nil
+		default:
+			return fileAction{}, errs.Conflict(file.Path, "", "aborted: %s already exists", file.Path)
+		}
+	default:
+		return fileAction{}, errs.Conflict(file.Path, conflictHint, "%s already exists", file.Path)
+	}
+}
+
+func writeFile(file File) error {
+	if err := os.MkdirAll(filepath.Dir(file.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", file.Path, err)
+	}
+	if err := os.WriteFile(file.Path, file.Content, 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", file.Path, err)
+	}
+	return nil
+}
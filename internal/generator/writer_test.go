@@ -0,0 +1,319 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+)
+
+// scriptedResolver answers Resolve from a list, recording what it was asked
+type scriptedResolver struct {
+	answers []Resolution
+	err     error
+	asked   []string
+	diffs   []string
+}
+
+func (r *scriptedResolver) Resolve(path, diff string) (Resolution, error) {
+	r.asked = append(r.asked, filepath.Base(path))
+	r.diffs = append(r.diffs, diff)
+	if r.err != nil {
+		return 0, r.err
+	}
+	answer := r.answers[0]
+	r.answers = r.answers[1:]
+	return answer, nil
+}
+
+// project writes files into a temporary directory and returns it
+func project(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// contents reads every file in dir
+func contents(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	got := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		rel, _ := filepath.Rel(dir, path)
+		got[filepath.ToSlash(rel)] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+// outcome summarizes a report by file name, e.g. "created new.go"
+func outcome(s *report.Summary) []string {
+	var out []string
+	for _, p := range s.Created {
+		out = append(out, "created "+filepath.Base(p))
+	}
+	for _, p := range s.Overwritten {
+		out = append(out, "overwritten "+filepath.Base(p))
+	}
+	for _, p := range s.Updated {
+		out = append(out, "updated "+filepath.Base(p))
+	}
+	for _, f := range s.Skipped {
+		out = append(out, fmt.Sprintf("skipped %s (%s)", filepath.Base(f.Path), f.Reason))
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestWriteAll(t *testing.T) {
+	existing := map[string]string{
+		"conflict.go":  "old\n",
+		"same.go":      "same\n",
+		"Makefile":     "build:\n",
+		"untouched.go": "mine\n",
+	}
+	files := func(dir string) []File {
+		return []File{
+			{Path: filepath.Join(dir, "sub", "new.go"), Content: []byte("new\n")},
+			{Path: filepath.Join(dir, "conflict.go"), Content: []byte("generated\n")},
+			{Path: filepath.Join(dir, "same.go"), Content: []byte("same\n")},
+			{Path: filepath.Join(dir, "Makefile"), Content: []byte("build:\ntest:\n"), Edit: true},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		policy   ConflictPolicy
+		answers  []Resolution
+		wantErr  bool
+		want     map[string]string
+		outcome  []string
+		wantAsks []string
+	}{
+		{
+			// Nothing is written, not even the new file planned before the conflict
+			name:    "fail",
+			policy:  ConflictFail,
+			wantErr: true,
+			want:    existing,
+		},
+		{
+			name:   "skip",
+			policy: ConflictSkip,
+			want: map[string]string{
+				"sub/new.go": "new\n", "conflict.go": "old\n", "same.go": "same\n",
+				"Makefile": "build:\ntest:\n", "untouched.go": "mine\n",
+			},
+			outcome: []string{"created new.go", "skipped conflict.go (already exists)", "skipped same.go (unchanged)", "updated Makefile"},
+		},
+		{
+			name:   "overwrite",
+			policy: ConflictOverwrite,
+			want: map[string]string{
+				"sub/new.go": "new\n", "conflict.go": "generated\n", "same.go": "same\n",
+				"Makefile": "build:\ntest:\n", "untouched.go": "mine\n",
+			},
+			outcome: []string{"created new.go", "overwritten conflict.go", "skipped same.go (unchanged)", "updated Makefile"},
+		},
+		{
+			// Edits are asked about too under prompt
+			name:    "prompt keeps one and takes the edit",
+			policy:  ConflictPrompt,
+			answers: []Resolution{ResolveSkip, ResolveOverwrite},
+			want: map[string]string{
+				"sub/new.go": "new\n", "conflict.go": "old\n", "same.go": "same\n",
+				"Makefile": "build:\ntest:\n", "untouched.go": "mine\n",
+			},
+			outcome:  []string{"created new.go", "skipped conflict.go (kept by user)", "skipped same.go (unchanged)", "updated Makefile"},
+			wantAsks: []string{"conflict.go", "Makefile"},
+		},
+		{
+			name:    "prompt overwrite all stops asking",
+			policy:  ConflictPrompt,
+			answers: []Resolution{ResolveOverwriteAll},
+			want: map[string]string{
+				"sub/new.go": "new\n", "conflict.go": "generated\n", "same.go": "same\n",
+				"Makefile": "build:\ntest:\n", "untouched.go": "mine\n",
+			},
+			outcome:  []string{"created new.go", "overwritten conflict.go", "skipped same.go (unchanged)", "updated Makefile"},
+			wantAsks: []string{"conflict.go"},
+		},
+		{
+			name:     "prompt abort writes nothing",
+			policy:   ConflictPrompt,
+			answers:  []Resolution{ResolveOverwrite, ResolveAbort},
+			wantErr:  true,
+			want:     existing,
+			wantAsks: []string{"conflict.go", "Makefile"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := project(t, existing)
+			rep := report.Discard("test")
+			resolver := &scriptedResolver{answers: tt.answers}
+			w, err := NewWriter(tt.policy, resolver, rep)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.WriteAll(files(dir))
+			var conflict *errs.ConflictError
+			if tt.wantErr != errors.As(err, &conflict) {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := contents(t, dir); !maps.Equal(got, tt.want) {
+				t.Errorf("files = %q, want %q", got, tt.want)
+			}
+			if got := outcome(rep.Summary()); !slices.Equal(got, tt.outcome) {
+				t.Errorf("report = %q, want %q", got, tt.outcome)
+			}
+			if !slices.Equal(resolver.asked, tt.wantAsks) {
+				t.Errorf("asked about %q, want %q", resolver.asked, tt.wantAsks)
+			}
+		})
+	}
+}
+
+func TestWriteAllPromptShowsDiff(t *testing.T) {
+	dir := project(t, map[string]string{"a.go": "one\ntwo\n"})
+	resolver := &scriptedResolver{answers: []Resolution{ResolveSkip}}
+	w, err := NewWriter(ConflictPrompt, resolver, report.Discard("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "a.go")
+	if err := w.Write(path, []byte("one\n2\n")); err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("--- %s (existing)\n+++ %s (generated)\n@@\n one\n-two\n+2\n", path, path)
+	if len(resolver.diffs) != 1 || resolver.diffs[0] != want {
+		t.Errorf("diff = %q, want %q", resolver.diffs, want)
+	}
+}
+
+func TestWriteAllResolverError(t *testing.T) {
+	dir := project(t, map[string]string{"a.go": "old\n"})
+	resolver := &scriptedResolver{err: errors.New("stdin closed")}
+	w, err := NewWriter(ConflictPrompt, resolver, report.Discard("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.WriteAll([]File{
+		{Path: filepath.Join(dir, "b.go"), Content: []byte("new\n")},
+		{Path: filepath.Join(dir, "a.go"), Content: []byte("new\n")},
+	})
+	if err == nil || err.Error() != "stdin closed" {
+		t.Fatalf("err = %v, want the resolver's error", err)
+	}
+	if got := contents(t, dir); !maps.Equal(got, map[string]string{"a.go": "old\n"}) {
+		t.Errorf("files = %q, want the project untouched", got)
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	tests := []struct {
+		policy   ConflictPolicy
+		resolver ConflictResolver
+		want     ConflictPolicy
+		wantErr  string
+	}{
+		{policy: "", want: ConflictFail},
+		{policy: ConflictSkip, want: ConflictSkip},
+		{policy: ConflictPrompt, resolver: &scriptedResolver{}, want: ConflictPrompt},
+		{policy: ConflictPrompt, wantErr: "needs a resolver"},
+		{policy: "merge", wantErr: `unknown conflict policy "merge"`},
+	}
+	for _, tt := range tests {
+		w, err := NewWriter(tt.policy, tt.resolver, report.Discard("test"))
+		if tt.wantErr != "" {
+			var verr *errs.ValidationError
+			if !errors.As(err, &verr) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewWriter(%q) = %v, want a validation error containing %q", tt.policy, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Policy() != tt.want {
+			t.Errorf("NewWriter(%q).Policy() = %q, want %q", tt.policy, w.Policy(), tt.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	lines := func(from, to int) string {
+		var sb strings.Builder
+		for i := from; i <= to; i++ {
+			fmt.Fprintf(&sb, "%d\n", i)
+		}
+		return sb.String()
+	}
+
+	tests := []struct {
+		name                string
+		existing, generated string
+		want                string
+	}{
+		{
+			name:     "identical",
+			existing: "a\nb\n", generated: "a\nb\n",
+			want: "",
+		},
+		{
+			name:     "new content",
+			existing: "", generated: "a\nb\n",
+			want: "@@\n+a\n+b\n",
+		},
+		{
+			name:     "change with context",
+			existing: lines(1, 9), generated: strings.Replace(lines(1, 9), "5\n", "five\n", 1),
+			want: "@@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name:     "insert and delete",
+			existing: "a\nb\nc\n", generated: "a\nc\nd\n",
+			want: "@@\n a\n-b\n c\n+d\n",
+		},
+		{
+			// Changes more than 2*diffContext lines apart get separate hunks
+			name:     "separate hunks",
+			existing: lines(1, 20), generated: strings.Replace(strings.Replace(lines(1, 20), "2\n", "two\n", 1), "19\n", "nineteen\n", 1),
+			want: "@@\n 1\n-2\n+two\n 3\n 4\n 5\n@@\n 16\n 17\n 18\n-19\n+nineteen\n 20\n",
+		},
+		{
+			// Changes closer than that share one
+			name:     "merged hunk",
+			existing: lines(1, 12), generated: strings.Replace(strings.Replace(lines(1, 12), "3\n", "three\n", 1), "8\n", "eight\n", 1),
+			want: "@@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n 7\n-8\n+eight\n 9\n 10\n 11\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := "--- f.go (existing)\n+++ f.go (generated)\n" + tt.want
+			if got := Diff("f.go", tt.existing, tt.generated); got != want {
+				t.Errorf("Diff:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
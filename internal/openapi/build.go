@@ -0,0 +1,299 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Build scans the route registrations of the project and documents them.
+// Warnings describe routes that could only be documented partially.
+func Build(p Project, info Info) (doc *Document, routes []Route, warnings []string, err error) {
+	s := newScanner(p)
+	routes, err = s.routes()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	doc = &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]*PathItem{},
+	}
+	schemas := newSchemas(s)
+	envelopes(schemas.components)
+
+	for _, r := range routes {
+		path := openAPIPath(r.Path)
+		item := doc.Paths[path]
+		if item == nil {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		slot := item.operation(r.Method)
+		if *slot != nil {
+			s.warnings = append(s.warnings, fmt.Sprintf("%s: %s %s is registered twice; documenting the last registration", r.Pos, r.Method, r.Path))
+		}
+		*slot = schemas.operation(r)
+	}
+
+	doc.Components.Schemas = schemas.components
+	return doc, routes, s.warnings, nil
+}
+
+// envelopes adds the response envelopes of the util package
+func envelopes(components map[string]*Schema) {
+	components["ErrorResponse"] = &Schema{
+		Type:        "object",
+		Description: "Returned by every failing request",
+		Required:    []string{"http_code", "message"},
+		Properties: Properties{
+			{"http_code", &Schema{Type: "integer"}},
+			{"message", &Schema{Type: "string"}},
+			{"code", &Schema{Type: "string", Description: "Machine-readable error code, e.g. NOT_FOUND"}},
+			{"errors", &Schema{Type: "array", Items: ref("FieldError"), Description: "The invalid fields of a 422 VALIDATION_FAILED"}},
+		},
+	}
+	components["FieldError"] = &Schema{
+		Type:     "object",
+		Required: []string{"field", "rule", "message"},
+		Properties: Properties{
+			{"field", &Schema{Type: "string"}},
+			{"rule", &Schema{Type: "string"}},
+			{"message", &Schema{Type: "string"}},
+		},
+	}
+	components["SuccessResponse"] = &Schema{
+		Type: "object",
+		Properties: Properties{
+			{"data", &Schema{}},
+			{"message", &Schema{Type: "string"}},
+		},
+	}
+	components["PaginatedResponse"] = &Schema{
+		Type:     "object",
+		Required: []string{"data", "total"},
+		Properties: Properties{
+			{"data", &Schema{Type: "array", Items: &Schema{}}},
+			{"next_cursor", &Schema{Type: "string", Description: "Pass as ?cursor= to get the next page; omitted on the last page and with ?offset="}},
+			{"total", &Schema{Type: "integer", Format: "int64", Description: "Number of rows matching the filters"}},
+		},
+	}
+}
+
+// openAPIPath converts Fiber parameters to OpenAPI ones: /orders/:id → /orders/{id}
+func openAPIPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, seg := range segments {
+		if name, ok := strings.CutPrefix(seg, ":"); ok {
+			segments[i] = "{" + strings.TrimSuffix(name, "?") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operation documents one route
+func (s *schemas) operation(r Route) *Operation {
+	op := &Operation{Responses: map[string]*Response{}}
+	h := r.Handler
+
+	for _, seg := range strings.Split(r.Path, "/") {
+		if name, ok := strings.CutPrefix(seg, ":"); ok {
+			name = strings.TrimSuffix(name, "?")
+			op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: s.tagged(h, h.uri(), "uri", name)})
+		}
+	}
+
+	if h == nil {
+		op.Responses["200"] = &Response{Description: http.StatusText(200)}
+		return op
+	}
+
+	op.OperationID = h.Package + strings.TrimSuffix(h.Name, "Handler")
+	op.Tags = []string{h.Package}
+	op.Summary, op.Description = summarize(h.Doc)
+
+	if h.Query != nil {
+		op.Parameters = append(op.Parameters, s.structParams(h, h.Query, "query")...)
+	}
+	if h.Paginated {
+		op.Parameters = append(op.Parameters, listParams(h)...)
+	}
+
+	if h.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: s.of(h.pkg, h.Body)}},
+		}
+	}
+
+	op.Responses[strconv.Itoa(h.Status)] = s.success(h)
+	for _, code := range slices.Sorted(maps.Keys(h.Errors)) {
+		op.Responses[strconv.Itoa(code)] = errorResponse(code, h.Errors[code])
+	}
+	op.Responses["500"] = errorResponse(500, []string{"INTERNAL_ERROR"})
+	return op
+}
+
+// summarize splits a doc comment into its first sentence and, when there is
+// more to it, the whole comment with its lines joined
+func summarize(doc string) (summary, description string) {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.TrimSpace(doc), "\n\n") {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	if len(paragraphs) == 0 {
+		return "", ""
+	}
+	summary, rest, _ := strings.Cut(paragraphs[0], ". ")
+	summary = strings.TrimSuffix(summary, ".")
+	if rest == "" && len(paragraphs) == 1 {
+		return summary, ""
+	}
+	return summary, strings.Join(paragraphs, "\n\n")
+}
+
+// success documents the successful response of h
+func (s *schemas) success(h *Handler) *Response {
+	resp := &Response{Description: http.StatusText(h.Status)}
+	if h.NoContent {
+		return resp
+	}
+
+	envelope := ref("SuccessResponse")
+	if h.Data != nil || h.Paginated {
+		item := &Schema{}
+		if h.Data != nil {
+			item = s.of(h.Data.pkg, h.Data.expr)
+		}
+		data := item
+		if h.Paginated || h.DataList {
+			data = &Schema{Type: "array", Items: item}
+		}
+		base := "SuccessResponse"
+		if h.Paginated {
+			base = "PaginatedResponse"
+		}
+		envelope = &Schema{AllOf: []*Schema{
+			ref(base),
+			{Type: "object", Properties: Properties{{"data", data}}},
+		}}
+	}
+	resp.Content = map[string]MediaType{"application/json": {Schema: envelope}}
+	return resp
+}
+
+// errorResponse documents a status returned as an ErrorResponse with codes
+func errorResponse(status int, codes []string) *Response {
+	schema := ref("ErrorResponse")
+	description := http.StatusText(status)
+	if len(codes) > 0 {
+		schema = &Schema{AllOf: []*Schema{
+			schema,
+			{Type: "object", Properties: Properties{{"code", &Schema{Type: "string", Enum: codes}}}},
+		}}
+		description += ": " + strings.Join(codes, ", ")
+	}
+	return &Response{
+		Description: description,
+		Content:     map[string]MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// listParams documents the query parameters of util.ParseListQuery
+func listParams(h *Handler) []*Parameter {
+	one, defaultSize, maxSize := 1.0, 20, 100.0
+	params := []*Parameter{
+		{Name: "limit", In: "query", Description: "Page size", Schema: &Schema{Type: "integer", Minimum: &one, Maximum: &maxSize, Default: defaultSize}},
+		{Name: "cursor", In: "query", Description: "next_cursor of the previous page; requires sorting by created_at", Schema: &Schema{Type: "string"}},
+		{Name: "offset", In: "query", Description: "Rows to skip; can't be combined with cursor", Schema: &Schema{Type: "integer", Minimum: new(float64)}},
+	}
+	if len(h.Sorts) > 0 {
+		var values []string
+		for _, column := range h.Sorts {
+			values = append(values, column, "-"+column)
+		}
+		params = append(params, &Parameter{Name: "sort", In: "query", Description: "Sort column, prefixed with - for descending order", Schema: &Schema{Type: "string", Enum: values, Default: "-created_at"}})
+	}
+	for _, column := range h.Filters {
+		params = append(params, &Parameter{Name: column, In: "query", Description: "Only return rows whose " + column + " equals the value", Schema: &Schema{Type: "string"}})
+	}
+	return params
+}
+
+// uri returns the type bound with c.Bind().URI, or nil
+func (h *Handler) uri() ast.Expr {
+	if h == nil {
+		return nil
+	}
+	return h.URI
+}
+
+// tagged returns the schema of the field of typ whose tag key is name,
+// defaulting to a string
+func (s *schemas) tagged(h *Handler, typ ast.Expr, key, name string) *Schema {
+	if typ == nil {
+		return &Schema{Type: "string"}
+	}
+	for _, param := range s.structParams(h, typ, key) {
+		if param.Name == name {
+			return param.Schema
+		}
+	}
+	return &Schema{Type: "string"}
+}
+
+// structParams documents the fields of a bound struct carrying a key tag
+func (s *schemas) structParams(h *Handler, typ ast.Expr, key string) []*Parameter {
+	st, p := s.structType(h.pkg, typ)
+	if st == nil {
+		return nil
+	}
+	var params []*Parameter
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		v, _ := strconv.Unquote(field.Tag.Value)
+		tag := reflect.StructTag(v)
+		name, _, _ := strings.Cut(tag.Get(key), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := s.of(p, field.Type)
+		required := constrain(schema, tag.Get("validate"))
+		params = append(params, &Parameter{Name: name, In: key, Required: required || key == "uri", Description: fieldDoc(field), Schema: schema})
+	}
+	if key == "uri" {
+		for _, param := range params {
+			param.In = "path"
+		}
+	}
+	return params
+}
+
+// structType resolves typ to a struct declaration
+func (s *schemas) structType(p *pkg, typ ast.Expr) (*ast.StructType, *pkg) {
+	switch t := typ.(type) {
+	case *ast.StructType:
+		return t, p
+	case *ast.Ident:
+		if spec := p.types[t.Name]; spec != nil {
+			return s.structType(p, spec.Type)
+		}
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			if target := s.scanner.load(p.imports[x.Name]); target != nil {
+				return s.structType(target, ast.NewIdent(t.Sel.Name))
+			}
+		}
+	}
+	return nil, nil
+}
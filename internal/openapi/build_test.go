@@ -0,0 +1,216 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIPath(t *testing.T) {
+	tests := []struct{ route, want string }{
+		{"/v1/orders", "/v1/orders"},
+		{"/v1/orders/:id", "/v1/orders/{id}"},
+		{"/v1/orders/:id/items/:item?", "/v1/orders/{id}/items/{item}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			if got := openAPIPath(tt.route); got != tt.want {
+				t.Errorf("openAPIPath(%q) = %q, want %q", tt.route, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name, doc            string
+		summary, description string
+	}{
+		{name: "empty"},
+		{name: "one sentence", doc: "GetHandler returns an order.", summary: "GetHandler returns an order"},
+		{
+			name:        "more sentences",
+			doc:         "CancelHandler cancels an order. Paid orders are\nrefunded first.",
+			summary:     "CancelHandler cancels an order",
+			description: "CancelHandler cancels an order. Paid orders are refunded first.",
+		},
+		{
+			name:        "paragraphs",
+			doc:         "ListHandler lists orders\n\nDeleted orders are left out.",
+			summary:     "ListHandler lists orders",
+			description: "ListHandler lists orders\n\nDeleted orders are left out.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, description := summarize(tt.doc)
+			if summary != tt.summary || description != tt.description {
+				t.Errorf("summarize(%q) = %q, %q; want %q, %q", tt.doc, summary, description, tt.summary, tt.description)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	p := writeProject(t, map[string]string{
+		"internal/handlers/handler.go": `package handlers
+import ("github.com/gofiber/fiber/v3"; "example.com/svc/internal/handlers/order")
+func SetupHandler(router *fiber.App) {
+	v1 := router.Group("/v1")
+	v1.Get("/orders/:id", (&order.GetHandler{}).Handle)
+	v1.Post("/orders", (&order.CreateHandler{}).Handle)
+	v1.Post("/orders", (&order.CreateHandler{}).Handle)
+	router.Get("/health", func(c fiber.Ctx) error { return nil })
+}
+`,
+		"internal/handlers/order/get.go": `package order
+import ("github.com/gofiber/fiber/v3"; "example.com/svc/internal/handlers/util")
+
+// GetHandler returns an order
+type GetHandler struct{}
+
+type GetParams struct {
+	ID int64 ` + "`uri:\"id\" validate:\"required\"`" + `
+}
+
+type GetQuery struct {
+	Expand string ` + "`query:\"expand\" validate:\"omitempty,oneof=items customer\"`" + `
+}
+
+func (h *GetHandler) Handle(c fiber.Ctx) error {
+	var params GetParams
+	if err := c.Bind().URI(&params); err != nil {
+		return util.HandleError(c, util.BuildErrorWithCode(fiber.StatusBadRequest, "Invalid ID format", "INVALID_ID_FORMAT"))
+	}
+	var query GetQuery
+	if err := c.Bind().Query(&query); err != nil {
+		return util.HandleError(c, util.BuildErrorWithCode(fiber.StatusBadRequest, "Invalid query parameters", "INVALID_QUERY"))
+	}
+	order, err := h.Queries.GetOrder(c.Context(), params.ID)
+	if err != nil {
+		return util.HandleError(c, util.BuildErrorWithCode(fiber.StatusNotFound, "Order not found", "NOT_FOUND"))
+	}
+	return c.JSON(util.SuccessResponse{Data: order})
+}
+`,
+		"internal/handlers/order/create.go": `package order
+import ("github.com/gofiber/fiber/v3"; "example.com/svc/internal/handlers/util")
+
+// CreateHandler places an order
+type CreateHandler struct{}
+
+type CreateRequest struct {
+	Name string ` + "`json:\"name\" validate:\"required,max=64\"`" + `
+}
+
+func (h *CreateHandler) Handle(c fiber.Ctx) error {
+	var req CreateRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return util.HandleError(c, util.BuildErrorWithCode(fiber.StatusBadRequest, "Invalid request body", "INVALID_BODY"))
+	}
+	if err := util.Validate(&req); err != nil {
+		return util.HandleError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(util.SuccessResponse{Data: req})
+}
+`,
+		"internal/entity/order.go": `package entity
+
+type Order struct {
+	ID   int64  ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+`,
+	})
+
+	doc, routes, warnings, err := Build(p, Info{Title: "svc", Version: "1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 4 {
+		t.Errorf("routes = %d, want 4", len(routes))
+	}
+	for _, want := range []string{"POST /v1/orders is registered twice", "GET /health is documented without request and response details"} {
+		if !slices.ContainsFunc(warnings, func(w string) bool { return strings.Contains(w, want) }) {
+			t.Errorf("warnings %q lack %q", warnings, want)
+		}
+	}
+
+	tests := []struct {
+		path, method string
+		operationID  string
+		// params lists the parameters as "in name", followed by "required"
+		params    []string
+		body      string
+		responses []string
+	}{
+		{
+			path: "/v1/orders/{id}", method: "GET",
+			operationID: "orderGet",
+			params:      []string{"path id required", "query expand"},
+			responses:   []string{"200", "400", "404", "500"},
+		},
+		{
+			path: "/v1/orders", method: "POST",
+			operationID: "orderCreate",
+			body:        "#/components/schemas/OrderCreateRequest",
+			responses:   []string{"201", "400", "422", "500"},
+		},
+		{
+			path: "/health", method: "GET",
+			responses: []string{"200"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			item := doc.Paths[tt.path]
+			if item == nil {
+				t.Fatalf("paths lack %s; have %q", tt.path, slices.Sorted(maps.Keys(doc.Paths)))
+			}
+			op := *item.operation(tt.method)
+			if op == nil {
+				t.Fatalf("%s has no %s operation", tt.path, tt.method)
+			}
+			if op.OperationID != tt.operationID {
+				t.Errorf("operationId = %q, want %q", op.OperationID, tt.operationID)
+			}
+			var params []string
+			for _, param := range op.Parameters {
+				s := param.In + " " + param.Name
+				if param.Required {
+					s += " required"
+				}
+				params = append(params, s)
+			}
+			if !slices.Equal(params, tt.params) {
+				t.Errorf("parameters = %q, want %q", params, tt.params)
+			}
+			body := ""
+			if op.RequestBody != nil {
+				body = op.RequestBody.Content["application/json"].Schema.Ref
+			}
+			if body != tt.body {
+				t.Errorf("request body = %q, want %q", body, tt.body)
+			}
+			if got := slices.Sorted(maps.Keys(op.Responses)); !slices.Equal(got, tt.responses) {
+				t.Errorf("responses = %q, want %q", got, tt.responses)
+			}
+		})
+	}
+
+	// Constraints come from the validate tags, and the data of the Get
+	// response from the entity named after the query
+	request := doc.Components.Schemas["OrderCreateRequest"]
+	if request == nil || !slices.Equal(request.Required, []string{"name"}) || len(request.Properties) != 1 ||
+		request.Properties[0].Schema.MaxLength == nil || *request.Properties[0].Schema.MaxLength != 64 {
+		t.Errorf("OrderCreateRequest = %+v, want a required name of at most 64 characters", request)
+	}
+	expand := (*doc.Paths["/v1/orders/{id}"].operation("GET")).Parameters[1].Schema
+	if !slices.Equal(expand.Enum, []string{"items", "customer"}) {
+		t.Errorf("expand enum = %q, want [items customer]", expand.Enum)
+	}
+	if doc.Components.Schemas["Order"] == nil {
+		t.Errorf("components lack the Order entity; have %q", slices.Sorted(maps.Keys(doc.Components.Schemas)))
+	}
+}
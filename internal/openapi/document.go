@@ -0,0 +1,189 @@
+// Package openapi derives an OpenAPI 3 document from the route registrations
+// and handler structs of a ready-go project, whichever router it uses. The
+// project is parsed, not compiled: handlers are understood through the shapes
+// the generators emit, such as c.Bind().Body(&req), util.BuildErrorWithCode
+// and util.ParseListQuery, and request and response types are read from their
+// struct declarations.
+package openapi
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the OpenAPI version of the documents built here
+const Version = "3.0.3"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Info       Info                 `yaml:"info"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// PathItem holds the operations of one path
+type PathItem struct {
+	// Parameters are shared by every operation of the path
+	Parameters []*Parameter `yaml:"parameters,omitempty"`
+
+	Get     *Operation `yaml:"get,omitempty"`
+	Put     *Operation `yaml:"put,omitempty"`
+	Post    *Operation `yaml:"post,omitempty"`
+	Delete  *Operation `yaml:"delete,omitempty"`
+	Patch   *Operation `yaml:"patch,omitempty"`
+	Head    *Operation `yaml:"head,omitempty"`
+	Options *Operation `yaml:"options,omitempty"`
+}
+
+// operation returns the slot of method, or nil for methods OpenAPI has no field for
+func (p *PathItem) operation(method string) **Operation {
+	switch method {
+	case "GET":
+		return &p.Get
+	case "PUT":
+		return &p.Put
+	case "POST":
+		return &p.Post
+	case "DELETE":
+		return &p.Delete
+	case "PATCH":
+		return &p.Patch
+	case "HEAD":
+		return &p.Head
+	case "OPTIONS":
+		return &p.Options
+	}
+	return nil
+}
+
+// Operation is one method of a path
+type Operation struct {
+	OperationID string               `yaml:"operationId,omitempty"`
+	Tags        []string             `yaml:"tags,omitempty"`
+	Summary     string               `yaml:"summary,omitempty"`
+	Description string               `yaml:"description,omitempty"`
+	Parameters  []*Parameter         `yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Ref         string  `yaml:"$ref,omitempty"`
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description,omitempty"`
+	Required    bool    `yaml:"required,omitempty"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+// RequestBody is the body of an operation
+type RequestBody struct {
+	Ref      string               `yaml:"$ref,omitempty"`
+	Required bool                 `yaml:"required"`
+	Content  map[string]MediaType `yaml:"content"`
+}
+
+// Response is the response of an operation for one status code
+type Response struct {
+	Ref         string               `yaml:"$ref,omitempty"`
+	Description string               `yaml:"description"`
+	Content     map[string]MediaType `yaml:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Components holds the objects referenced by operations
+type Components struct {
+	Schemas       map[string]*Schema      `yaml:"schemas"`
+	Parameters    map[string]*Parameter   `yaml:"parameters,omitempty"`
+	RequestBodies map[string]*RequestBody `yaml:"requestBodies,omitempty"`
+	Responses     map[string]*Response    `yaml:"responses,omitempty"`
+}
+
+// Schema is a (subset of an) OpenAPI schema object
+type Schema struct {
+	Ref                  string     `yaml:"$ref,omitempty"`
+	Type                 string     `yaml:"type,omitempty"`
+	Format               string     `yaml:"format,omitempty"`
+	Description          string     `yaml:"description,omitempty"`
+	Nullable             bool       `yaml:"nullable,omitempty"`
+	Enum                 []string   `yaml:"enum,omitempty"`
+	Default              any        `yaml:"default,omitempty"`
+	MinLength            *int       `yaml:"minLength,omitempty"`
+	MaxLength            *int       `yaml:"maxLength,omitempty"`
+	Minimum              *float64   `yaml:"minimum,omitempty"`
+	Maximum              *float64   `yaml:"maximum,omitempty"`
+	Pattern              string     `yaml:"pattern,omitempty"`
+	Items                *Schema    `yaml:"items,omitempty"`
+	Required             []string   `yaml:"required,omitempty"`
+	Properties           Properties `yaml:"properties,omitempty"`
+	AdditionalProperties *Schema    `yaml:"additionalProperties,omitempty"`
+	AllOf                []*Schema  `yaml:"allOf,omitempty"`
+	OneOf                []*Schema  `yaml:"oneOf,omitempty"`
+	AnyOf                []*Schema  `yaml:"anyOf,omitempty"`
+}
+
+// Property is a named property of an object schema
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties keeps object properties in declaration order, where a map
+// would sort them
+type Properties []Property
+
+// MarshalYAML encodes the properties as a mapping in order
+func (p Properties) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, prop := range p {
+		var value yaml.Node
+		if err := value.Encode(prop.Schema); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: prop.Name}, &value)
+	}
+	return node, nil
+}
+
+// ref returns a schema referencing the component name
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// nullable returns s admitting null. A $ref can't have siblings in OpenAPI
+// 3.0, so references are wrapped in allOf.
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{AllOf: []*Schema{s}, Nullable: true}
+	}
+	c := *s
+	c.Nullable = true
+	return &c
+}
+
+// Marshal encodes the document as YAML
+func (d *Document) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(d); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,197 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads an OpenAPI 3 document in YAML or JSON
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var probe struct {
+		OpenAPI string `yaml:"openapi"`
+		Swagger string `yaml:"swagger"`
+	}
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	switch {
+	case probe.Swagger != "":
+		return nil, fmt.Errorf("%s is a Swagger %s document; convert it to OpenAPI 3 first", path, probe.Swagger)
+	case !strings.HasPrefix(probe.OpenAPI, "3."):
+		return nil, fmt.Errorf("%s is not an OpenAPI 3 document: openapi is %q", path, probe.OpenAPI)
+	}
+
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &doc, nil
+}
+
+// UnmarshalYAML decodes the properties in declaration order
+func (p *Properties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.New("properties must be a mapping")
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var s Schema
+		if err := node.Content[i+1].Decode(&s); err != nil {
+			return err
+		}
+		*p = append(*p, Property{Name: node.Content[i].Value, Schema: &s})
+	}
+	return nil
+}
+
+// UnmarshalYAML accepts the boolean form of additionalProperties and the
+// OpenAPI 3.1 type lists, e.g. [string, "null"]
+func (s *Schema) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!bool" {
+		*s = Schema{}
+		return nil
+	}
+	type plain Schema
+	nullable := false
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			types := node.Content[i+1]
+			if node.Content[i].Value != "type" || types.Kind != yaml.SequenceNode {
+				continue
+			}
+			scalar := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str"}
+			for _, t := range types.Content {
+				if t.Value == "null" {
+					nullable = true
+				} else if scalar.Value == "" {
+					scalar.Value = t.Value
+				}
+			}
+			node.Content[i+1] = scalar
+		}
+	}
+	if err := node.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	s.Nullable = s.Nullable || nullable
+	return nil
+}
+
+// Endpoint is an operation with its method and path
+type Endpoint struct {
+	Method    string // GET
+	Path      string // /v1/orders/{id}
+	Operation *Operation
+	// Parameters merges the path's parameters with the operation's, which
+	// override them
+	Parameters []*Parameter
+}
+
+// methods lists the operations of a path item in document order
+var methods = []string{"GET", "PUT", "POST", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+
+// Endpoints returns every operation of the document, sorted by path and
+// method. References to component parameters are resolved.
+func (d *Document) Endpoints() ([]Endpoint, error) {
+	var endpoints []Endpoint
+	for _, path := range slices.Sorted(maps.Keys(d.Paths)) {
+		item := d.Paths[path]
+		for _, method := range methods {
+			op := *item.operation(method)
+			if op == nil {
+				continue
+			}
+			e := Endpoint{Method: method, Path: path, Operation: op}
+			for _, list := range [][]*Parameter{item.Parameters, op.Parameters} {
+				for _, p := range list {
+					p, err := d.Parameter(p)
+					if err != nil {
+						return nil, fmt.Errorf("%s %s: %w", method, path, err)
+					}
+					e.Parameters = slices.DeleteFunc(e.Parameters, func(q *Parameter) bool { return q.Name == p.Name && q.In == p.In })
+					e.Parameters = append(e.Parameters, p)
+				}
+			}
+			// Undeclared path parameters are strings
+			for _, seg := range strings.Split(path, "/") {
+				name, ok := strings.CutPrefix(seg, "{")
+				name = strings.TrimSuffix(name, "}")
+				if ok && !slices.ContainsFunc(e.Parameters, func(p *Parameter) bool { return p.Name == name && p.In == "path" }) {
+					e.Parameters = append(e.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+				}
+			}
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints, nil
+}
+
+// Parameter resolves a reference to a component parameter
+func (d *Document) Parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	return lookup(d.Components.Parameters, p.Ref, "parameters")
+}
+
+// RequestBody resolves a reference to a component request body
+func (d *Document) RequestBody(b *RequestBody) (*RequestBody, error) {
+	if b == nil || b.Ref == "" {
+		return b, nil
+	}
+	return lookup(d.Components.RequestBodies, b.Ref, "requestBodies")
+}
+
+// Response resolves a reference to a component response
+func (d *Document) Response(r *Response) (*Response, error) {
+	if r == nil || r.Ref == "" {
+		return r, nil
+	}
+	return lookup(d.Components.Responses, r.Ref, "responses")
+}
+
+// SchemaName returns the component name of a schema reference
+func SchemaName(ref string) (string, error) {
+	name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("unsupported reference %s: only #/components/schemas/ references are supported", ref)
+	}
+	return name, nil
+}
+
+// lookup resolves a local reference to a component of kind
+func lookup[T any](components map[string]*T, ref, kind string) (*T, error) {
+	name, ok := strings.CutPrefix(ref, "#/components/"+kind+"/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %s: only #/components/%s/ references are supported", ref, kind)
+	}
+	c := components[name]
+	if c == nil {
+		return nil, fmt.Errorf("reference %s not found", ref)
+	}
+	return c, nil
+}
+
+// JSON returns the schema of the application/json content, or of the only
+// content when there is a single media type
+func JSON(content map[string]MediaType) *Schema {
+	if mt, ok := content["application/json"]; ok {
+		return mt.Schema
+	}
+	if len(content) == 1 {
+		for _, mt := range content {
+			return mt.Schema
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+		// props lists the properties of the Order schema in order, with a *
+		// marking nullable ones
+		props   []string
+		wantErr string
+	}{
+		{
+			name: "yaml 3.0",
+			file: "api.yaml",
+			content: `openapi: 3.0.3
+info: {title: svc, version: "1"}
+paths: {}
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        total: {type: number}
+        id: {type: integer}
+        note: {type: string, nullable: true}
+`,
+			props: []string{"total", "id", "note*"},
+		},
+		{
+			name: "json 3.1",
+			file: "api.json",
+			content: `{"openapi": "3.1.0", "info": {"title": "svc", "version": "1"}, "paths": {},
+ "components": {"schemas": {"Order": {"type": "object", "additionalProperties": true,
+  "properties": {"status": {"type": "string"}, "note": {"type": ["string", "null"]}}}}}}`,
+			props: []string{"status", "note*"},
+		},
+		{
+			name:    "swagger",
+			file:    "api.yaml",
+			content: "swagger: \"2.0\"\ninfo: {title: svc, version: \"1\"}\npaths: {}\n",
+			wantErr: "is a Swagger 2.0 document; convert it to OpenAPI 3 first",
+		},
+		{
+			name:    "not openapi",
+			file:    "api.yaml",
+			content: "name: svc\n",
+			wantErr: `is not an OpenAPI 3 document: openapi is ""`,
+		},
+		{
+			name:    "invalid",
+			file:    "api.yaml",
+			content: "openapi: [3\n",
+			wantErr: "failed to parse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			doc, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			order := doc.Components.Schemas["Order"]
+			if order == nil {
+				t.Fatal("Order schema not loaded")
+			}
+			var props []string
+			for _, prop := range order.Properties {
+				name := prop.Name
+				if prop.Schema.Nullable {
+					name += "*"
+				}
+				props = append(props, name)
+			}
+			if !slices.Equal(props, tt.props) {
+				t.Errorf("properties = %q, want %q", props, tt.props)
+			}
+		})
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	tests := []struct {
+		name, paths string
+		// want lists the endpoints as "METHOD path" followed by their
+		// parameters as in:name:type
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "sorted by path and method",
+			paths: `
+  /v1/orders:
+    post: {responses: {}}
+    get: {responses: {}}
+  /v1/customers:
+    get: {responses: {}}`,
+			want: []string{"GET /v1/customers", "GET /v1/orders", "POST /v1/orders"},
+		},
+		{
+			name: "operations override path parameters",
+			paths: `
+  /v1/orders/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+      - {name: expand, in: query, schema: {type: string}}
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses: {}`,
+			want: []string{"GET /v1/orders/{id} query:expand:string path:id:integer"},
+		},
+		{
+			name: "component parameters",
+			paths: `
+  /v1/orders:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Limit'
+      responses: {}`,
+			want: []string{"GET /v1/orders query:limit:integer"},
+		},
+		{
+			name: "undeclared path parameters are strings",
+			paths: `
+  /v1/orders/{id}/items/{item}:
+    get:
+      parameters:
+        - {name: item, in: path, required: true, schema: {type: integer}}
+      responses: {}`,
+			want: []string{"GET /v1/orders/{id}/items/{item} path:item:integer path:id:string"},
+		},
+		{
+			name: "missing component",
+			paths: `
+  /v1/orders:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Cursor'
+      responses: {}`,
+			wantErr: "GET /v1/orders: reference #/components/parameters/Cursor not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "api.yaml")
+			content := `openapi: 3.0.3
+info: {title: svc, version: "1"}
+components:
+  parameters:
+    Limit: {name: limit, in: query, schema: {type: integer}}
+paths:` + tt.paths + "\n"
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			doc, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			endpoints, err := doc.Endpoints()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range endpoints {
+				s := e.Method + " " + e.Path
+				for _, p := range e.Parameters {
+					s += fmt.Sprintf(" %s:%s:%s", p.In, p.Name, p.Schema.Type)
+				}
+				got = append(got, s)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("endpoints = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
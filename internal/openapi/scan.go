@@ -0,0 +1,685 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Project locates the packages of a ready-go project
+type Project struct {
+	Dir    string // project root
+	Module string // Go module path
+	// Handlers, Entity and Models are slash-separated directories relative to Dir
+	Handlers string
+	Entity   string
+	Models   string
+}
+
+// Route is a route registration found in the handlers
+type Route struct {
+	Method string // GET
+	Path   string // in Fiber syntax: /v1/orders/:id
+	Pos    string // file:line of the registration
+	// Handler is nil when the registered handler isn't the method of a
+	// handler struct, e.g. a function literal
+	Handler *Handler
+}
+
+// Handler is what a handler's method reveals about its endpoint
+type Handler struct {
+	Package string // order
+	Name    string // CreateHandler
+	Doc     string
+
+	pkg *pkg
+	// Body, URI and Query are the types bound by c.Bind() or util.BindX
+	Body, URI, Query ast.Expr
+	// Status is the success status; NoContent is set by c.SendStatus(204)
+	// and its Echo and util equivalents
+	Status    int
+	NoContent bool
+	// Data is the type of SuccessResponse.Data, or of the items of a page
+	Data      *typeRef
+	DataList  bool
+	Paginated bool
+	Filters   []string
+	Sorts     []string
+	// Errors lists the error codes returned by status
+	Errors map[int][]string
+}
+
+// typeRef is a type expression and the package it appears in
+type typeRef struct {
+	pkg  *pkg
+	expr ast.Expr
+}
+
+// pkg is a parsed package of the project
+type pkg struct {
+	path    string // import path
+	name    string
+	fset    *token.FileSet
+	files   []*ast.File
+	imports map[string]string // local name → import path
+	types   map[string]*ast.TypeSpec
+	consts  map[string][]string // string constants by type name, e.g. the values of an enum
+	funcs   map[string]*ast.FuncDecl
+	methods map[string]*ast.FuncDecl // "Type.Method"
+	vars    map[string]ast.Expr      // values of package-level variables
+}
+
+// scanner loads the project's packages on demand
+type scanner struct {
+	project  Project
+	fset     *token.FileSet
+	pkgs     map[string]*pkg
+	warnings []string
+}
+
+func newScanner(p Project) *scanner {
+	return &scanner{project: p, fset: token.NewFileSet(), pkgs: map[string]*pkg{}}
+}
+
+// importPath returns the import path of a slash-separated project directory
+func (s *scanner) importPath(dir string) string {
+	return path.Join(s.project.Module, dir)
+}
+
+// load parses the package with importPath, or returns nil for packages
+// outside the module and directories without Go files
+func (s *scanner) load(importPath string) *pkg {
+	if p, ok := s.pkgs[importPath]; ok {
+		return p
+	}
+	s.pkgs[importPath] = nil
+
+	rel, ok := strings.CutPrefix(importPath, s.project.Module+"/")
+	if !ok {
+		return nil
+	}
+	dir := filepath.Join(s.project.Dir, filepath.FromSlash(rel))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	p := &pkg{
+		path:    importPath,
+		fset:    s.fset,
+		imports: map[string]string{},
+		types:   map[string]*ast.TypeSpec{},
+		consts:  map[string][]string{},
+		funcs:   map[string]*ast.FuncDecl{},
+		methods: map[string]*ast.FuncDecl{},
+		vars:    map[string]ast.Expr{},
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(s.fset, filepath.Join(dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			s.warnings = append(s.warnings, fmt.Sprintf("skipped %s: %v", filepath.Join(rel, name), err))
+			continue
+		}
+		p.add(file)
+	}
+	if len(p.files) == 0 {
+		return nil
+	}
+	s.pkgs[importPath] = p
+	return p
+}
+
+// add indexes the declarations of file
+func (p *pkg) add(file *ast.File) {
+	p.name = file.Name.Name
+	p.files = append(p.files, file)
+
+	for _, imp := range file.Imports {
+		importPath, _ := strconv.Unquote(imp.Path.Value)
+		name := path.Base(importPath)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		p.imports[name] = importPath
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				p.funcs[d.Name.Name] = d
+			} else if recv := receiverType(d); recv != "" {
+				p.methods[recv+"."+d.Name.Name] = d
+			}
+		case *ast.GenDecl:
+			p.addGenDecl(d)
+		}
+	}
+}
+
+func (p *pkg) addGenDecl(d *ast.GenDecl) {
+	var typ ast.Expr // carried over by iota-style const specs
+	for _, spec := range d.Specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			if spec.Doc == nil && len(d.Specs) == 1 {
+				spec.Doc = d.Doc
+			}
+			p.types[spec.Name.Name] = spec
+		case *ast.ValueSpec:
+			if spec.Type != nil {
+				typ = spec.Type
+			}
+			for i, name := range spec.Names {
+				if i >= len(spec.Values) {
+					continue
+				}
+				if d.Tok == token.VAR {
+					p.vars[name.Name] = spec.Values[i]
+					continue
+				}
+				ident, ok := typ.(*ast.Ident)
+				lit, isString := spec.Values[i].(*ast.BasicLit)
+				if ok && isString && lit.Kind == token.STRING {
+					v, _ := strconv.Unquote(lit.Value)
+					p.consts[ident.Name] = append(p.consts[ident.Name], v)
+				}
+			}
+		}
+	}
+}
+
+// receiverType returns the type name of a method's receiver
+func receiverType(fn *ast.FuncDecl) string {
+	if len(fn.Recv.List) == 0 {
+		return ""
+	}
+	t := fn.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if ident, ok := t.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// httpMethods are the router methods registering a route: Fiber and chi
+// name them Get, Echo GET
+var httpMethods = map[string]string{
+	"Get": "GET", "Head": "HEAD", "Post": "POST", "Put": "PUT",
+	"Delete": "DELETE", "Patch": "PATCH", "Options": "OPTIONS",
+	"GET": "GET", "HEAD": "HEAD", "POST": "POST", "PUT": "PUT",
+	"DELETE": "DELETE", "PATCH": "PATCH", "OPTIONS": "OPTIONS",
+}
+
+// muxMethods are the net/http ServeMux methods taking a "METHOD /path" pattern
+var muxMethods = []string{"Handle", "HandleFunc"}
+
+// DocsPath is where the optional documentation is served; its routes are
+// left out of the document
+const DocsPath = "/docs"
+
+// routes finds the route registrations in every package under the handlers directory
+func (s *scanner) routes() ([]Route, error) {
+	root := filepath.Join(s.project.Dir, filepath.FromSlash(s.project.Handlers))
+	var dirs []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			rel, _ := filepath.Rel(s.project.Dir, p)
+			dirs = append(dirs, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []Route
+	for _, dir := range dirs {
+		p := s.load(s.importPath(dir))
+		if p == nil {
+			continue
+		}
+		for _, file := range p.files {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+					routes = append(routes, s.funcRoutes(p, fn)...)
+				}
+			}
+		}
+	}
+	return routes, nil
+}
+
+// funcRoutes finds the routes registered by fn. Handler variables and route
+// groups are tracked through the assignments preceding the registration:
+//
+//	handler := &order.CreateHandler{...}
+//	v1 := router.Group("/v1")
+//	v1.Post("/orders", handler.Handle)
+func (s *scanner) funcRoutes(p *pkg, fn *ast.FuncDecl) []Route {
+	handlers := map[string]ast.Expr{} // variable → handler type
+	groups := map[string]string{}     // variable → path prefix
+	var routes []Route
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || i >= len(n.Rhs) {
+					continue
+				}
+				if typ := constructed(n.Rhs[i]); typ != nil {
+					handlers[ident.Name] = typ
+				}
+				if call, ok := n.Rhs[i].(*ast.CallExpr); ok {
+					if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Group" && len(call.Args) > 0 {
+						if prefix, ok := stringLit(call.Args[0]); ok {
+							groups[ident.Name] = groups[exprName(sel.X)] + prefix
+						}
+					}
+				}
+			}
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || len(n.Args) < 2 {
+				return true
+			}
+			route, ok := stringLit(n.Args[0])
+			if !ok {
+				return true
+			}
+			method, ok := httpMethods[sel.Sel.Name]
+			if slices.Contains(muxMethods, sel.Sel.Name) {
+				method, route, ok = strings.Cut(route, " ")
+			}
+			if !ok || !strings.HasPrefix(route, "/") {
+				return true
+			}
+			route = groups[exprName(sel.X)] + fiberPath(route)
+			if route == DocsPath || strings.HasPrefix(route, DocsPath+"/") {
+				return true
+			}
+
+			r := Route{Method: method, Path: route, Pos: s.position(n.Pos())}
+			r.Handler = s.resolveHandler(p, unwrapAdapter(n.Args[len(n.Args)-1]), handlers)
+			if r.Handler == nil {
+				s.warnings = append(s.warnings, fmt.Sprintf("%s: %s %s is documented without request and response details; its handler is not a handler struct's method", r.Pos, method, route))
+			}
+			routes = append(routes, r)
+		}
+		return true
+	})
+	return routes
+}
+
+// fiberPath converts the {name} wildcards of chi and net/http patterns to
+// Fiber parameters: /orders/{id} → /orders/:id. Regular expressions,
+// trailing ... and the {$} end anchor are dropped.
+func fiberPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, seg := range segments {
+		name, ok := strings.CutPrefix(seg, "{")
+		if name, ok = strings.CutSuffix(name, "}"); !ok {
+			continue
+		}
+		if name == "$" {
+			segments[i] = ""
+			continue
+		}
+		name, _, _ = strings.Cut(name, ":")
+		segments[i] = ":" + strings.TrimSuffix(name, "...")
+	}
+	return strings.Join(segments, "/")
+}
+
+// unwrapAdapter returns h for an adapter call like util.Handler(h), which
+// net/http and chi routes register their handler with
+func unwrapAdapter(e ast.Expr) ast.Expr {
+	if call, ok := e.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		if _, ok := call.Args[0].(*ast.SelectorExpr); ok {
+			return call.Args[0]
+		}
+	}
+	return e
+}
+
+// constructed returns T for &T{...}, T{...} and new(T)
+func constructed(e ast.Expr) ast.Expr {
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		e = u.X
+	}
+	switch e := e.(type) {
+	case *ast.CompositeLit:
+		return e.Type
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok && ident.Name == "new" && len(e.Args) == 1 {
+			return e.Args[0]
+		}
+	case *ast.ParenExpr:
+		return constructed(e.X)
+	}
+	return nil
+}
+
+// resolveHandler resolves a registered handler expression like
+// handler.Handle or (&order.CreateHandler{}).Handle to the method's declaration
+func (s *scanner) resolveHandler(p *pkg, e ast.Expr, handlers map[string]ast.Expr) *Handler {
+	sel, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	typ := constructed(sel.X)
+	if ident, ok := sel.X.(*ast.Ident); ok {
+		typ = handlers[ident.Name]
+	}
+
+	target, name := p, ""
+	switch t := typ.(type) {
+	case *ast.Ident:
+		name = t.Name
+	case *ast.SelectorExpr:
+		pkgName, ok := t.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		target = s.load(p.imports[pkgName.Name])
+		name = t.Sel.Name
+	}
+	if target == nil || name == "" {
+		return nil
+	}
+	method := target.methods[name+"."+sel.Sel.Name]
+	if method == nil || method.Body == nil {
+		return nil
+	}
+
+	h := &Handler{Package: target.name, Name: name, pkg: target, Status: 200, Errors: map[int][]string{}}
+	if spec := target.types[name]; spec != nil && spec.Doc != nil {
+		h.Doc = strings.TrimSpace(spec.Doc.Text())
+	}
+	s.analyze(h, method)
+	return h
+}
+
+// analyze reads the endpoint's contract from the handler method's body
+func (s *scanner) analyze(h *Handler, fn *ast.FuncDecl) {
+	p := h.pkg
+	util := s.importPath(path.Join(s.project.Handlers, "util"))
+	isUtil := func(e ast.Expr, name string) bool {
+		sel, ok := e.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != name {
+			return false
+		}
+		x, ok := sel.X.(*ast.Ident)
+		return ok && p.imports[x.Name] == util
+	}
+
+	locals := map[string]ast.Expr{}     // variable → declared type
+	calls := map[string]*ast.CallExpr{} // variable → call it was assigned from
+	var data []ast.Expr                 // arguments of c.JSON
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				if n.Type != nil {
+					locals[name.Name] = n.Type
+				}
+			}
+		case *ast.AssignStmt:
+			if len(n.Rhs) == 1 {
+				if call, ok := n.Rhs[0].(*ast.CallExpr); ok {
+					if ident, ok := n.Lhs[0].(*ast.Ident); ok {
+						calls[ident.Name] = call
+					}
+				}
+			}
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			switch {
+			case isBind(sel.X) && len(n.Args) == 1:
+				h.bind(sel.Sel.Name, locals[addressed(n.Args[0])])
+			case strings.HasPrefix(sel.Sel.Name, "Bind") && isUtil(n.Fun, sel.Sel.Name) && len(n.Args) == 2:
+				h.bind(strings.TrimPrefix(sel.Sel.Name, "Bind"), locals[addressed(n.Args[1])])
+			case isUtil(n.Fun, "BuildErrorWithCode") && len(n.Args) == 3:
+				code, _ := stringLit(n.Args[2])
+				h.addError(status(n.Args[0]), code)
+			case isUtil(n.Fun, "BuildError") && len(n.Args) >= 1:
+				h.addError(status(n.Args[0]), "")
+			case isUtil(n.Fun, "Validate"):
+				h.addError(422, "VALIDATION_FAILED")
+			case isUtil(n.Fun, "ParseListQuery") && len(n.Args) == 2:
+				h.Paginated = true
+				h.Filters, h.Sorts = listSpec(p, p.vars[addressed(n.Args[1])])
+				for _, code := range []string{"INVALID_LIMIT", "INVALID_SORT", "INVALID_OFFSET", "INVALID_PAGINATION", "INVALID_CURSOR", "INVALID_FILTER"} {
+					h.addError(400, code)
+				}
+			case isUtil(n.Fun, "List") && len(n.Args) >= 4:
+				if scan, ok := n.Args[3].(*ast.Ident); ok {
+					if fn := p.funcs[scan.Name]; fn != nil && fn.Type.Results != nil && len(fn.Type.Results.List) > 0 {
+						h.Data = &typeRef{pkg: p, expr: fn.Type.Results.List[0].Type}
+					}
+				}
+			case (sel.Sel.Name == "SendStatus" || sel.Sel.Name == "NoContent") && len(n.Args) >= 1:
+				// c.SendStatus(status) in Fiber, c.NoContent(status) in Echo
+				// and util.NoContent(w, status) in chi and net/http
+				if code := status(n.Args[len(n.Args)-1]); code >= 200 && code < 300 {
+					h.Status, h.NoContent = code, code == 204
+				}
+			case sel.Sel.Name == "Status" && len(n.Args) == 1:
+				if code := status(n.Args[0]); code >= 200 && code < 300 {
+					h.Status = code
+				}
+			case sel.Sel.Name == "JSON" && len(n.Args) >= 1:
+				// c.JSON(v) in Fiber, c.JSON(status, v) in Echo and
+				// util.JSON(w, status, v) in chi and net/http
+				args := n.Args
+				if isUtil(n.Fun, "JSON") {
+					args = args[1:]
+				}
+				if len(args) == 2 {
+					if code := status(args[0]); code >= 200 && code < 300 {
+						h.Status = code
+					}
+				}
+				data = append(data, args[len(args)-1])
+			}
+		}
+		return true
+	})
+
+	if h.Paginated || h.Data != nil {
+		return
+	}
+	for _, arg := range data {
+		if lit, ok := arg.(*ast.CompositeLit); ok && isUtil(lit.Type, "SuccessResponse") {
+			for _, elt := range lit.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok && exprName(kv.Key) == "Data" {
+					if ident, ok := kv.Value.(*ast.Ident); ok {
+						h.Data, h.DataList = s.queryResult(calls[ident.Name])
+					}
+				}
+			}
+		}
+		if h.Data != nil {
+			return
+		}
+	}
+}
+
+// queryResult guesses the row type returned by a sqlc call like
+// h.Queries.GetOrder or h.Queries.ListOrderItemsByOrderID from its name,
+// looking the entity up in the entity package, then in the sqlc models
+func (s *scanner) queryResult(call *ast.CallExpr) (*typeRef, bool) {
+	if call == nil {
+		return nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || exprName(sel.X) != "Queries" {
+		return nil, false
+	}
+	name, list := sel.Sel.Name, false
+	for _, verb := range []string{"Get", "Create", "Update", "List"} {
+		if rest, ok := strings.CutPrefix(name, verb); ok {
+			name, list = rest, verb == "List"
+			break
+		}
+	}
+	if list {
+		if i := strings.Index(name, "By"); i > 0 {
+			name = name[:i]
+		}
+		name = strings.TrimSuffix(name, "s")
+	}
+
+	for _, dir := range []string{s.project.Entity, s.project.Models} {
+		if p := s.load(s.importPath(dir)); p != nil && p.types[name] != nil {
+			return &typeRef{pkg: p, expr: ast.NewIdent(name)}, list
+		}
+	}
+	return nil, false
+}
+
+// listSpec reads the Filters and Sorts of a util.ListSpec literal
+func listSpec(p *pkg, value ast.Expr) (filters, sorts []string) {
+	lit, ok := value.(*ast.CompositeLit)
+	if !ok {
+		return nil, nil
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		values, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		var list []string
+		for _, v := range values.Elts {
+			if s, ok := stringLit(v); ok {
+				list = append(list, s)
+			}
+		}
+		switch exprName(kv.Key) {
+		case "Filters":
+			filters = list
+		case "Sorts":
+			sorts = list
+		}
+	}
+	return filters, sorts
+}
+
+// bind records the type bound to the request's Body, URI or Query
+func (h *Handler) bind(kind string, target ast.Expr) {
+	switch kind {
+	case "Body":
+		h.Body = target
+	case "URI":
+		h.URI = target
+	case "Query":
+		h.Query = target
+	}
+}
+
+func (h *Handler) addError(status int, code string) {
+	if status == 0 {
+		return
+	}
+	codes := h.Errors[status]
+	if code != "" && !slices.Contains(codes, code) {
+		codes = append(codes, code)
+	}
+	h.Errors[status] = codes
+}
+
+// isBind reports whether e is c.Bind()
+func isBind(e ast.Expr) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Bind"
+}
+
+// addressed returns x for &x
+func addressed(e ast.Expr) string {
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		return exprName(u.X)
+	}
+	return ""
+}
+
+// exprName returns the name of an identifier, or the selected name of x.Name
+func exprName(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
+
+func stringLit(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// statusCodes maps the status constants of fiber and net/http to their code
+var statusCodes = map[string]int{
+	"StatusOK": 200, "StatusCreated": 201, "StatusAccepted": 202, "StatusNoContent": 204,
+	"StatusBadRequest": 400, "StatusUnauthorized": 401, "StatusPaymentRequired": 402,
+	"StatusForbidden": 403, "StatusNotFound": 404, "StatusMethodNotAllowed": 405,
+	"StatusNotAcceptable": 406, "StatusRequestTimeout": 408, "StatusConflict": 409,
+	"StatusGone": 410, "StatusPreconditionFailed": 412, "StatusRequestEntityTooLarge": 413,
+	"StatusUnsupportedMediaType": 415, "StatusUnprocessableEntity": 422, "StatusLocked": 423,
+	"StatusPreconditionRequired": 428, "StatusTooManyRequests": 429,
+	"StatusInternalServerError": 500, "StatusNotImplemented": 501, "StatusBadGateway": 502,
+	"StatusServiceUnavailable": 503, "StatusGatewayTimeout": 504,
+}
+
+// status returns the code of fiber.StatusX, http.StatusX or a literal, or 0
+func status(e ast.Expr) int {
+	if lit, ok := e.(*ast.BasicLit); ok && lit.Kind == token.INT {
+		code, _ := strconv.Atoi(lit.Value)
+		return code
+	}
+	return statusCodes[exprName(e)]
+}
+
+func (s *scanner) position(pos token.Pos) string {
+	position := s.fset.Position(pos)
+	rel, err := filepath.Rel(s.project.Dir, position.Filename)
+	if err != nil {
+		rel = position.Filename
+	}
+	return fmt.Sprintf("%s:%d", filepath.ToSlash(rel), position.Line)
+}
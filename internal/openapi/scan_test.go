@@ -0,0 +1,269 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// writeProject writes files, keyed by slash-separated path, into a temporary
+// module and returns the Project locating its packages
+func writeProject(t *testing.T, files map[string]string) Project {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/svc\n\ngo 1.23\n"
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return Project{
+		Dir:      dir,
+		Module:   "example.com/svc",
+		Handlers: "internal/handlers",
+		Entity:   "internal/entity",
+		Models:   "internal/models",
+	}
+}
+
+func TestFiberPath(t *testing.T) {
+	tests := []struct{ route, want string }{
+		{"/v1/orders/:id", "/v1/orders/:id"},
+		{"/v1/orders/{id}", "/v1/orders/:id"},
+		{"/v1/orders/{id:[0-9]+}/items", "/v1/orders/:id/items"},
+		{"/files/{path...}", "/files/:path"},
+		{"/v1/orders/{$}", "/v1/orders/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			if got := fiberPath(tt.route); got != tt.want {
+				t.Errorf("fiberPath(%q) = %q, want %q", tt.route, got, tt.want)
+			}
+		})
+	}
+}
+
+// orderHandlers declares the handlers the route setups of TestRoutes register
+const orderHandlers = `package order
+
+type GetHandler struct{}
+
+func (h *GetHandler) Handle() error { return nil }
+
+type CreateHandler struct{}
+
+func (h *CreateHandler) Handle() error { return nil }
+`
+
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup string
+		// want lists the routes as "METHOD path package.Handler", with - for
+		// handlers that aren't a handler struct's method
+		want     []string
+		warnings int
+	}{
+		{
+			name: "fiber groups",
+			setup: `package handlers
+import ("github.com/gofiber/fiber/v3"; "example.com/svc/internal/handlers/order")
+func SetupHandler(router *fiber.App) {
+	get := &order.GetHandler{}
+	v1 := router.Group("/v1")
+	orders := v1.Group("/orders")
+	orders.Get("/:id", get.Handle)
+	v1.Post("/orders", (&order.CreateHandler{}).Handle)
+	router.Get("/health", func(c fiber.Ctx) error { return nil })
+	router.Get("/docs/*", serveDocs)
+}
+`,
+			want:     []string{"GET /v1/orders/:id order.GetHandler", "POST /v1/orders order.CreateHandler", "GET /health -"},
+			warnings: 1,
+		},
+		{
+			name: "echo",
+			setup: `package handlers
+import ("github.com/labstack/echo/v4"; "example.com/svc/internal/handlers/order")
+func SetupHandler(e *echo.Echo) {
+	g := e.Group("/v1")
+	g.GET("/orders/:id", new(order.GetHandler).Handle)
+	g.POST("/orders", (&order.CreateHandler{}).Handle)
+}
+`,
+			want: []string{"GET /v1/orders/:id order.GetHandler", "POST /v1/orders order.CreateHandler"},
+		},
+		{
+			name: "chi",
+			setup: `package handlers
+import ("github.com/go-chi/chi/v5"; "example.com/svc/internal/handlers/order"; "example.com/svc/internal/handlers/util")
+func SetupHandler(r chi.Router) {
+	get := &order.GetHandler{}
+	r.Get("/v1/orders/{id:[0-9]+}", util.Handler(get.Handle))
+	r.Post("/v1/orders", util.Handler((&order.CreateHandler{}).Handle))
+}
+`,
+			want: []string{"GET /v1/orders/:id order.GetHandler", "POST /v1/orders order.CreateHandler"},
+		},
+		{
+			name: "stdlib",
+			setup: `package handlers
+import ("net/http"; "example.com/svc/internal/handlers/order"; "example.com/svc/internal/handlers/util")
+func SetupHandler(mux *http.ServeMux) {
+	get := &order.GetHandler{}
+	mux.Handle("GET /v1/orders/{id}", util.Handler(get.Handle))
+	mux.Handle("POST /v1/orders", util.Handler((&order.CreateHandler{}).Handle))
+	mux.HandleFunc("/healthz", healthz)
+}
+`,
+			// Patterns without a method match every method and are left out
+			want: []string{"GET /v1/orders/:id order.GetHandler", "POST /v1/orders order.CreateHandler"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScanner(writeProject(t, map[string]string{
+				"internal/handlers/handler.go":   tt.setup,
+				"internal/handlers/order/get.go": orderHandlers,
+			}))
+			routes, err := s.routes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range routes {
+				handler := "-"
+				if r.Handler != nil {
+					handler = r.Handler.Package + "." + r.Handler.Name
+				}
+				got = append(got, fmt.Sprintf("%s %s %s", r.Method, r.Path, handler))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("routes = %q, want %q", got, tt.want)
+			}
+			if len(s.warnings) != tt.warnings {
+				t.Errorf("warnings = %q, want %d", s.warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler string
+		// body, uri and query are the names of the bound types
+		body, uri, query string
+		status           int
+		noContent        bool
+		errors           map[int][]string
+		filters, sorts   []string
+	}{
+		{
+			name: "fiber body",
+			handler: `import ("github.com/gofiber/fiber/v3"; "example.com/svc/internal/handlers/util")
+func (h *Handler) Handle(c fiber.Ctx) error {
+	var req CreateRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return util.HandleError(c, util.BuildErrorWithCode(fiber.StatusBadRequest, "Invalid request body", "INVALID_BODY"))
+	}
+	if err := util.Validate(&req); err != nil {
+		return util.HandleError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(util.SuccessResponse{})
+}`,
+			body:   "CreateRequest",
+			status: 201,
+			errors: map[int][]string{400: {"INVALID_BODY"}, 422: {"VALIDATION_FAILED"}},
+		},
+		{
+			name: "net/http parameters",
+			handler: `import ("net/http"; "example.com/svc/internal/handlers/util")
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) error {
+	var params GetParams
+	if err := util.BindURI(r, &params); err != nil {
+		return util.BuildErrorWithCode(http.StatusBadRequest, "Invalid ID format", "INVALID_ID_FORMAT")
+	}
+	var query GetQuery
+	if err := util.BindQuery(r, &query); err != nil {
+		return util.BuildErrorWithCode(http.StatusBadRequest, "Invalid query parameters", "INVALID_QUERY")
+	}
+	if missing {
+		return util.BuildErrorWithCode(http.StatusNotFound, "Order not found", "NOT_FOUND")
+	}
+	return util.JSON(w, http.StatusOK, util.SuccessResponse{})
+}`,
+			uri:    "GetParams",
+			query:  "GetQuery",
+			status: 200,
+			errors: map[int][]string{400: {"INVALID_ID_FORMAT", "INVALID_QUERY"}, 404: {"NOT_FOUND"}},
+		},
+		{
+			name: "echo no content",
+			handler: `import ("net/http"; "github.com/labstack/echo/v4")
+func (h *Handler) Handle(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+}`,
+			status:    204,
+			noContent: true,
+			errors:    map[int][]string{},
+		},
+		{
+			name: "paginated list",
+			handler: `import ("github.com/gofiber/fiber/v3"; "example.com/svc/internal/handlers/util")
+var listSpec = util.ListSpec{Filters: []string{"status"}, Sorts: []string{"created_at", "total"}}
+func (h *Handler) Handle(c fiber.Ctx) error {
+	q, err := util.ParseListQuery(c, &listSpec)
+	if err != nil {
+		return util.HandleError(c, err)
+	}
+	return c.JSON(q)
+}`,
+			status:  200,
+			filters: []string{"status"},
+			sorts:   []string{"created_at", "total"},
+			errors: map[int][]string{400: {
+				"INVALID_LIMIT", "INVALID_SORT", "INVALID_OFFSET", "INVALID_PAGINATION", "INVALID_CURSOR", "INVALID_FILTER",
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScanner(writeProject(t, map[string]string{
+				"internal/handlers/handler.go": `package handlers
+import "example.com/svc/internal/handlers/order"
+func SetupHandler(router Router) { router.Get("/orders", (&order.Handler{}).Handle) }
+`,
+				"internal/handlers/order/handler.go": "package order\n" + tt.handler + "\ntype Handler struct{}\n",
+			}))
+			routes, err := s.routes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(routes) != 1 || routes[0].Handler == nil {
+				t.Fatalf("routes = %+v, want one resolved route; warnings: %q", routes, s.warnings)
+			}
+			h := routes[0].Handler
+
+			if got := [3]string{exprName(h.Body), exprName(h.URI), exprName(h.Query)}; got != [3]string{tt.body, tt.uri, tt.query} {
+				t.Errorf("body, uri, query = %q, want %q", got, [3]string{tt.body, tt.uri, tt.query})
+			}
+			if h.Status != tt.status || h.NoContent != tt.noContent {
+				t.Errorf("status = %d (no content %v), want %d (%v)", h.Status, h.NoContent, tt.status, tt.noContent)
+			}
+			if !reflect.DeepEqual(h.Errors, tt.errors) {
+				t.Errorf("errors = %v, want %v", h.Errors, tt.errors)
+			}
+			if !slices.Equal(h.Filters, tt.filters) || !slices.Equal(h.Sorts, tt.sorts) {
+				t.Errorf("filters, sorts = %q, %q; want %q, %q", h.Filters, h.Sorts, tt.filters, tt.sorts)
+			}
+		})
+	}
+}
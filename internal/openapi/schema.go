@@ -0,0 +1,284 @@
+package openapi
+
+import (
+	"go/ast"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// schemas converts Go types to schemas, collecting named structs as components
+type schemas struct {
+	scanner    *scanner
+	components map[string]*Schema
+	// names maps "importpath.Type" to its component name
+	names map[string]string
+	// bare lists the packages whose types keep their name as component name
+	bare map[string]bool
+}
+
+func newSchemas(s *scanner) *schemas {
+	return &schemas{
+		scanner:    s,
+		components: map[string]*Schema{},
+		names:      map[string]string{},
+		bare: map[string]bool{
+			s.importPath(s.project.Entity):                      true,
+			s.importPath(s.project.Models):                      true,
+			s.importPath(path.Join(s.project.Handlers, "util")): true,
+		},
+	}
+}
+
+// basicTypes maps predeclared Go types to schemas
+var basicTypes = map[string]Schema{
+	"string": {Type: "string"}, "bool": {Type: "boolean"},
+	"int": {Type: "integer"}, "int8": {Type: "integer"}, "int16": {Type: "integer"},
+	"int32": {Type: "integer", Format: "int32"}, "int64": {Type: "integer", Format: "int64"},
+	"uint": {Type: "integer"}, "uint8": {Type: "integer"}, "uint16": {Type: "integer"},
+	"uint32": {Type: "integer", Format: "int32"}, "uint64": {Type: "integer", Format: "int64"},
+	"byte": {Type: "integer"}, "rune": {Type: "integer", Format: "int32"},
+	"float32": {Type: "number", Format: "float"}, "float64": {Type: "number", Format: "double"},
+	"any": {},
+}
+
+// externalTypes maps types of the standard library to schemas
+var externalTypes = map[string]Schema{
+	"time.Time":                   {Type: "string", Format: "date-time"},
+	"encoding/json.RawMessage":    {},
+	"database/sql.NullString":     {Type: "string", Nullable: true},
+	"database/sql.NullBool":       {Type: "boolean", Nullable: true},
+	"database/sql.NullByte":       {Type: "integer", Nullable: true},
+	"database/sql.NullInt16":      {Type: "integer", Nullable: true},
+	"database/sql.NullInt32":      {Type: "integer", Format: "int32", Nullable: true},
+	"database/sql.NullInt64":      {Type: "integer", Format: "int64", Nullable: true},
+	"database/sql.NullFloat64":    {Type: "number", Format: "double", Nullable: true},
+	"database/sql.NullTime":       {Type: "string", Format: "date-time", Nullable: true},
+	"github.com/google/uuid.UUID": {Type: "string", Format: "uuid"},
+}
+
+// of returns the schema of the type expression e appearing in p
+func (s *schemas) of(p *pkg, e ast.Expr) *Schema {
+	switch e := e.(type) {
+	case *ast.Ident:
+		if basic, ok := basicTypes[e.Name]; ok {
+			return &basic
+		}
+		return s.named(p, e.Name)
+	case *ast.StarExpr:
+		return nullable(s.of(p, e.X))
+	case *ast.ArrayType:
+		if ident, ok := e.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.of(p, e.Elt)}
+	case *ast.MapType:
+		return &Schema{Type: "object", AdditionalProperties: s.of(p, e.Value)}
+	case *ast.StructType:
+		return s.object(p, e)
+	case *ast.SelectorExpr:
+		pkgName, ok := e.X.(*ast.Ident)
+		if !ok {
+			return &Schema{}
+		}
+		importPath := p.imports[pkgName.Name]
+		if external, ok := externalTypes[importPath+"."+e.Sel.Name]; ok {
+			return &external
+		}
+		if target := s.scanner.load(importPath); target != nil {
+			return s.named(target, e.Sel.Name)
+		}
+	}
+	return &Schema{}
+}
+
+// named returns the schema of the type name declared in p. Structs become
+// components; string types with constants become enums.
+func (s *schemas) named(p *pkg, name string) *Schema {
+	spec := p.types[name]
+	if spec == nil {
+		return &Schema{}
+	}
+
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		schema := s.of(p, spec.Type)
+		if values := p.consts[name]; len(values) > 0 && schema.Type == "string" {
+			schema.Enum = values
+		}
+		return schema
+	}
+
+	// Null wrappers like sqlc's NullOrdersStatus: a value and Valid
+	if fields := st.Fields.List; len(fields) == 2 && len(fields[1].Names) == 1 && fields[1].Names[0].Name == "Valid" {
+		return nullable(s.of(p, fields[0].Type))
+	}
+
+	key := p.path + "." + name
+	if component, ok := s.names[key]; ok {
+		return ref(component)
+	}
+	component := name
+	if prefix := exported(p.name); !s.bare[p.path] && !strings.HasPrefix(name, prefix) {
+		component = prefix + name
+	}
+	for i := 2; s.components[component] != nil; i++ {
+		component = name + strconv.Itoa(i)
+	}
+	s.names[key] = component
+	s.components[component] = &Schema{} // placeholder for recursive types
+	schema := s.object(p, st)
+	if spec.Doc != nil {
+		schema.Description = strings.TrimSpace(spec.Doc.Text())
+	}
+	s.components[component] = schema
+	return ref(component)
+}
+
+// object returns the schema of a struct, named after its json tags and
+// constrained by its validate tags
+func (s *schemas) object(p *pkg, st *ast.StructType) *Schema {
+	schema := &Schema{Type: "object"}
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			v, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(v)
+		}
+		jsonName, _, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+
+		if len(field.Names) == 0 {
+			// Embedded structs promote their fields
+			embedded := s.of(p, field.Type)
+			if component := strings.TrimPrefix(embedded.Ref, "#/components/schemas/"); component != "" && jsonName == "" {
+				embedded = s.components[component]
+			}
+			if embedded.Type == "object" && jsonName == "" {
+				schema.Properties = append(schema.Properties, embedded.Properties...)
+				schema.Required = append(schema.Required, embedded.Required...)
+				continue
+			}
+		}
+
+		for _, name := range fieldNames(field) {
+			if !ast.IsExported(name) {
+				continue
+			}
+			prop := Property{Name: name, Schema: s.of(p, field.Type)}
+			if jsonName != "" {
+				prop.Name = jsonName
+			}
+			if doc := fieldDoc(field); doc != "" {
+				prop.Schema = describe(prop.Schema, doc)
+			}
+			if constrain(prop.Schema, tag.Get("validate")) {
+				schema.Required = append(schema.Required, prop.Name)
+			}
+			schema.Properties = append(schema.Properties, prop)
+		}
+	}
+	return schema
+}
+
+// fieldNames returns the names of a field, or the type name of an embedded one
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) == 0 {
+		t := field.Type
+		if star, ok := t.(*ast.StarExpr); ok {
+			t = star.X
+		}
+		return []string{exprName(t)}
+	}
+	names := make([]string, len(field.Names))
+	for i, n := range field.Names {
+		names[i] = n.Name
+	}
+	return names
+}
+
+// fieldDoc returns the doc comment of a field as a single line
+func fieldDoc(field *ast.Field) string {
+	doc := field.Doc
+	if doc == nil {
+		doc = field.Comment
+	}
+	if doc == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+// describe returns s with a description, wrapping references that can't have one
+func describe(s *Schema, doc string) *Schema {
+	if s.Ref != "" {
+		return &Schema{AllOf: []*Schema{s}, Description: doc}
+	}
+	c := *s
+	c.Description = doc
+	return &c
+}
+
+// constrain applies the validate rules to s and reports whether the field
+// is required. Rules without an OpenAPI equivalent are ignored.
+func constrain(s *Schema, rules string) (required bool) {
+	if rules == "" || s.Ref != "" {
+		return rules != "" && strings.Contains(","+rules+",", ",required,")
+	}
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		case "email":
+			s.Format = "email"
+		case "url", "uri":
+			s.Format = "uri"
+		case "uuid", "uuid4":
+			s.Format = "uuid"
+		case "numeric":
+			s.Pattern = `^-?[0-9]+(\.[0-9]+)?$`
+		case "min", "max", "gte", "lte", "len":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			f := float64(n)
+			lower := name == "min" || name == "gte" || name == "len"
+			upper := name == "max" || name == "lte" || name == "len"
+			switch s.Type {
+			case "string":
+				if lower {
+					s.MinLength = &n
+				}
+				if upper {
+					s.MaxLength = &n
+				}
+			case "integer", "number":
+				if lower {
+					s.Minimum = &f
+				}
+				if upper {
+					s.Maximum = &f
+				}
+			}
+		}
+	}
+	return required
+}
+
+// exported returns name with its first letter in upper case
+func exported(name string) string {
+	if name == "" {
+		return ""
+	}
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
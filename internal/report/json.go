@@ -0,0 +1,24 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonReporter stays silent until Finish, then writes the whole Summary as one document
+type jsonReporter struct {
+	collector
+	w io.Writer
+}
+
+func (r *jsonReporter) Finish(err error) error {
+	r.collector.Finish(err)
+
+	enc := json.NewEncoder(r.w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if encErr := enc.Encode(r.summary); encErr != nil && err == nil {
+		return encErr
+	}
+	return err
+}
@@ -0,0 +1,180 @@
+// Package report routes progress from the generators to the user, either as
+// human-readable text, as a single machine-readable JSON document, or not at all.
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+)
+
+// Format selects how a Reporter presents progress
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatQuiet Format = "quiet"
+)
+
+// Formats lists every supported output format
+var Formats = []Format{FormatText, FormatJSON, FormatQuiet}
+
+// Reporter receives progress events from generators and CLI actions
+type Reporter interface {
+	// Step announces a phase of work, e.g. "📁 Creating directory structure..."
+	Step(msg string)
+	// Info prints free-form prose; it is omitted from JSON output
+	Info(msg string)
+	// FileCreated records a file written to disk
+	FileCreated(path string)
+	// FileOverwritten records an existing file replaced on disk
+	FileOverwritten(path string)
+	// FileUpdated records an existing file that was deliberately edited
+	FileUpdated(path string)
+	// FileSkipped records a file that was left untouched
+	FileSkipped(path, reason string)
+	// FileRenamed records a file moved to a new path
+	FileRenamed(from, to string)
+	// Command records an external command and its exit status
+	Command(result CommandResult)
+	// Warn records a non-fatal problem
+	Warn(msg string)
+	// NextStep records a follow-up action for the user
+	NextStep(step string)
+	// Finish completes the report with the final error (nil on success) and
+	// returns that error unchanged
+	Finish(err error) error
+	// Summary returns everything recorded so far
+	Summary() *Summary
+}
+
+// Summary is the machine-readable record of a single CLI invocation
+type Summary struct {
+	Command     string          `json:"command"`
+	Success     bool            `json:"success"`
+	Created     []string        `json:"created"`
+	Overwritten []string        `json:"overwritten"`
+	Updated     []string        `json:"updated"`
+	Skipped     []SkippedFile   `json:"skipped"`
+	Renamed     []RenamedFile   `json:"renamed"`
+	Commands    []CommandResult `json:"commands"`
+	Warnings    []string        `json:"warnings"`
+	NextSteps   []string        `json:"next_steps"`
+	Error       string          `json:"error,omitempty"`
+	ExitCode    int             `json:"exit_code"`
+	Hint        string          `json:"hint,omitempty"`
+}
+
+// SkippedFile describes a file that was not written
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// RenamedFile describes a file that was moved
+type RenamedFile struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// CommandResult describes an external command run during generation
+type CommandResult struct {
+	Command  string   `json:"command"`
+	Args     []string `json:"args"`
+	Dir      string   `json:"dir"`
+	ExitCode int      `json:"exit_code"`
+	Output   string   `json:"output,omitempty"`
+}
+
+// New creates a Reporter for the given format writing to w
+func New(format Format, command string, w io.Writer) (Reporter, error) {
+	base := collector{summary: newSummary(command)}
+
+	switch format {
+	case FormatText, "":
+		return &textReporter{collector: base, w: w}, nil
+	case FormatJSON:
+		return &jsonReporter{collector: base, w: w}, nil
+	case FormatQuiet:
+		return &base, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (expected text, json or quiet)", format)
+	}
+}
+
+// Discard returns a Reporter that records a summary but prints nothing
+func Discard(command string) Reporter {
+	return &collector{summary: newSummary(command)}
+}
+
+func newSummary(command string) *Summary {
+	return &Summary{
+		Command:     command,
+		Created:     []string{},
+		Overwritten: []string{},
+		Updated:     []string{},
+		Skipped:     []SkippedFile{},
+		Renamed:     []RenamedFile{},
+		Commands:    []CommandResult{},
+		Warnings:    []string{},
+		NextSteps:   []string{},
+	}
+}
+
+// collector records events without printing anything; it backs every reporter
+// and doubles as the quiet reporter
+type collector struct {
+	summary *Summary
+}
+
+func (c *collector) Step(string) {}
+
+func (c *collector) Info(string) {}
+
+func (c *collector) FileCreated(path string) {
+	c.summary.Created = append(c.summary.Created, path)
+}
+
+func (c *collector) FileOverwritten(path string) {
+	c.summary.Overwritten = append(c.summary.Overwritten, path)
+}
+
+func (c *collector) FileUpdated(path string) {
+	c.summary.Updated = append(c.summary.Updated, path)
+}
+
+func (c *collector) FileSkipped(path, reason string) {
+	c.summary.Skipped = append(c.summary.Skipped, SkippedFile{Path: path, Reason: reason})
+}
+
+func (c *collector) FileRenamed(from, to string) {
+	c.summary.Renamed = append(c.summary.Renamed, RenamedFile{From: from, To: to})
+}
+
+func (c *collector) Command(result CommandResult) {
+	c.summary.Commands = append(c.summary.Commands, result)
+}
+
+func (c *collector) Warn(msg string) {
+	c.summary.Warnings = append(c.summary.Warnings, msg)
+}
+
+func (c *collector) NextStep(step string) {
+	c.summary.NextSteps = append(c.summary.NextSteps, step)
+}
+
+func (c *collector) Finish(err error) error {
+	c.summary.Success = err == nil
+	c.summary.ExitCode = errs.ExitCode(err)
+	if err != nil {
+		c.summary.Error = err.Error()
+		c.summary.Hint = errs.Hint(err)
+	}
+	return err
+}
+
+func (c *collector) Summary() *Summary {
+	return c.summary
+}
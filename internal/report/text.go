@@ -0,0 +1,64 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+// textReporter prints the familiar emoji progress output
+type textReporter struct {
+	collector
+	w io.Writer
+}
+
+func (r *textReporter) Step(msg string) {
+	fmt.Fprintln(r.w, msg)
+}
+
+func (r *textReporter) Info(msg string) {
+	fmt.Fprintln(r.w, msg)
+}
+
+func (r *textReporter) FileCreated(path string) {
+	r.collector.FileCreated(path)
+	fmt.Fprintf(r.w, "  ✓ Created %s\n", path)
+}
+
+func (r *textReporter) FileOverwritten(path string) {
+	r.collector.FileOverwritten(path)
+	fmt.Fprintf(r.w, "  ✓ Overwrote %s\n", path)
+}
+
+func (r *textReporter) FileUpdated(path string) {
+	r.collector.FileUpdated(path)
+	fmt.Fprintf(r.w, "  ✓ Updated %s\n", path)
+}
+
+func (r *textReporter) FileRenamed(from, to string) {
+	r.collector.FileRenamed(from, to)
+	fmt.Fprintf(r.w, "  ✓ Renamed %s → %s\n", from, to)
+}
+
+func (r *textReporter) FileSkipped(path, reason string) {
+	r.collector.FileSkipped(path, reason)
+	fmt.Fprintf(r.w, "  - Skipped %s (%s)\n", path, reason)
+}
+
+func (r *textReporter) Warn(msg string) {
+	r.collector.Warn(msg)
+	fmt.Fprintf(r.w, "⚠️  Warning: %s\n", msg)
+}
+
+func (r *textReporter) Finish(err error) error {
+	r.collector.Finish(err)
+	if err != nil || len(r.summary.NextSteps) == 0 {
+		return err
+	}
+
+	fmt.Fprintln(r.w, "Next steps:")
+	for _, step := range r.summary.NextSteps {
+		fmt.Fprintf(r.w, "  %s\n", step)
+	}
+	fmt.Fprintln(r.w)
+	return err
+}
@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Dialect renders DDL for one of the sqlc engines: mysql, postgresql or sqlite
+type Dialect struct {
+	Engine string
+}
+
+// Engines lists the engines a Dialect can render
+var Engines = []string{"mysql", "postgresql", "sqlite"}
+
+// logicalTypes maps the portable column types accepted on the command line to
+// each engine's SQL type
+var logicalTypes = map[string]map[string]string{
+	"string":    {"mysql": "VARCHAR(255)", "postgresql": "VARCHAR(255)", "sqlite": "TEXT"},
+	"text":      {"mysql": "TEXT", "postgresql": "TEXT", "sqlite": "TEXT"},
+	"int":       {"mysql": "INT", "postgresql": "INTEGER", "sqlite": "INTEGER"},
+	"bigint":    {"mysql": "BIGINT", "postgresql": "BIGINT", "sqlite": "INTEGER"},
+	"bool":      {"mysql": "BOOLEAN", "postgresql": "BOOLEAN", "sqlite": "BOOLEAN"},
+	"float":     {"mysql": "DOUBLE", "postgresql": "DOUBLE PRECISION", "sqlite": "REAL"},
+	"decimal":   {"mysql": "DECIMAL(10, 2)", "postgresql": "NUMERIC(10, 2)", "sqlite": "NUMERIC"},
+	"date":      {"mysql": "DATE", "postgresql": "DATE", "sqlite": "DATE"},
+	"time":      {"mysql": "TIMESTAMP", "postgresql": "TIMESTAMPTZ", "sqlite": "DATETIME"},
+	"timestamp": {"mysql": "TIMESTAMP", "postgresql": "TIMESTAMPTZ", "sqlite": "DATETIME"},
+	"datetime":  {"mysql": "DATETIME", "postgresql": "TIMESTAMP", "sqlite": "DATETIME"},
+	"json":      {"mysql": "JSON", "postgresql": "JSONB", "sqlite": "TEXT"},
+	"uuid":      {"mysql": "CHAR(36)", "postgresql": "UUID", "sqlite": "TEXT"},
+	"bytes":     {"mysql": "BLOB", "postgresql": "BYTEA", "sqlite": "BLOB"},
+}
+
+// AutoIncrement reports whether the database assigns the values of column c
+// of t: an auto-increment column or, in SQLite, the INTEGER PRIMARY KEY that
+// aliases the rowid
+func (d Dialect) AutoIncrement(t *Table, c *Column) bool {
+	if c.AutoIncrement {
+		return true
+	}
+	return d.Engine == "sqlite" && c.Type == "INTEGER" && len(t.PrimaryKey) == 1 && t.PrimaryKey[0] == c.Name
+}
+
+// LogicalTypes returns the portable type names ColumnType understands
+func LogicalTypes() []string {
+	return []string{"string", "text", "int", "bigint", "bool", "float", "decimal", "date", "time", "timestamp", "datetime", "json", "uuid", "bytes"}
+}
+
+// ColumnType returns the SQL type for a portable type name such as "string".
+// Anything else, e.g. "varchar(64)", is assumed to be an SQL type already.
+func (d Dialect) ColumnType(typ string) string {
+	if types, ok := logicalTypes[strings.ToLower(typ)]; ok {
+		return types[d.Engine]
+	}
+	return strings.ToUpper(typ)
+}
+
+// AddColumn renders ALTER TABLE ... ADD COLUMN for a column definition such as
+// "sku VARCHAR(255) NOT NULL"
+func (d Dialect) AddColumn(table, definition string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", table, definition)
+}
+
+// RestoreColumn re-adds a dropped column. MySQL puts it back in its original
+// position; the other engines can only append it.
+func (d Dialect) RestoreColumn(table, definition, after string) string {
+	if d.Engine != "mysql" {
+		return d.AddColumn(table, definition)
+	}
+	if after == "" {
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s FIRST;", table, definition)
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s AFTER %s;", table, definition, after)
+}
+
+// DropColumn renders ALTER TABLE ... DROP COLUMN
+func (d Dialect) DropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", table, column)
+}
+
+// CreateIndex renders CREATE [UNIQUE] INDEX
+func (d Dialect) CreateIndex(table string, idx *Index) string {
+	unique := ""
+	if idx.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);", unique, idx.Name, table, strings.Join(idx.Columns, ", "))
+}
+
+// DropIndex renders DROP INDEX; MySQL scopes index names to their table
+func (d Dialect) DropIndex(table string, idx *Index) string {
+	if d.Engine == "mysql" {
+		return fmt.Sprintf("DROP INDEX %s ON %s;", idx.Name, table)
+	}
+	return fmt.Sprintf("DROP INDEX %s;", idx.Name)
+}
+
+// IndexName returns the conventional name for an index: idx_products_sku,
+// or uq_products_sku for unique indexes
+func IndexName(table string, columns []string, unique bool) string {
+	prefix := "idx"
+	if unique {
+		prefix = "uq"
+	}
+	return prefix + "_" + table + "_" + strings.Join(columns, "_")
+}
+
+var plainIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// reserved lists keywords that legacy schemas commonly use as names
+var reserved = map[string]bool{
+	"add": true, "all": true, "and": true, "as": true, "by": true, "check": true, "column": true,
+	"default": true, "delete": true, "desc": true, "from": true, "group": true, "index": true,
+	"insert": true, "key": true, "limit": true, "order": true, "primary": true, "references": true,
+	"select": true, "table": true, "to": true, "update": true, "values": true, "where": true,
+}
+
+// Quote quotes an identifier that isn't a plain lower-case name or is a
+// keyword: `order` in MySQL and SQLite, "order" in PostgreSQL
+func (d Dialect) Quote(name string) string {
+	if plainIdentifier.MatchString(name) && !reserved[name] {
+		return name
+	}
+	if d.Engine == "postgresql" {
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
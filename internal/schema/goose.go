@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is a goose SQL migration file
+type Migration struct {
+	// Version is the numeric prefix of the file name, e.g. 1 or 20240102150405
+	Version int64
+	// Name is the file name without the version prefix and .sql extension
+	Name string
+	Path string
+	// Added is when the file was added to version control (Unix seconds), or
+	// 0 when unknown. Only CheckVersions and Renumber use it.
+	Added int64
+}
+
+// ParseMigrationName splits a goose file name such as "00002_add_sku.sql"
+// into its version and name. ok is false for files goose would not run.
+func ParseMigrationName(file string) (version int64, name string, ok bool) {
+	base := filepath.Base(file)
+	if !strings.HasSuffix(base, ".sql") {
+		return 0, "", false
+	}
+	base = strings.TrimSuffix(base, ".sql")
+
+	prefix, name, found := strings.Cut(base, "_")
+	if !found {
+		return 0, "", false
+	}
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || version < 1 {
+		return 0, "", false
+	}
+	return version, name, true
+}
+
+// Migrations lists the goose migrations in dir, ordered by version
+func Migrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		version, name, ok := ParseMigrationName(entry.Name())
+		if !ok {
+			continue
+		}
+		migrations = append(migrations, Migration{Version: version, Name: name, Path: filepath.Join(dir, entry.Name())})
+	}
+
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// UpStatements returns the statements in the "-- +goose Up" section of a
+// goose migration, honouring StatementBegin/StatementEnd blocks.
+func UpStatements(src string) []string {
+	var (
+		statements []string
+		section    strings.Builder
+		inUp       bool
+	)
+
+	flush := func() {
+		statements = append(statements, splitStatements(section.String())...)
+		section.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(src))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if directive, ok := gooseDirective(line); ok {
+			switch directive {
+			case "Up":
+				inUp = true
+			case "Down":
+				flush()
+				return statements
+			case "StatementBegin":
+				flush()
+			case "StatementEnd":
+				if inUp {
+					if stmt := strings.TrimSpace(section.String()); stmt != "" {
+						statements = append(statements, strings.TrimSuffix(stmt, ";"))
+					}
+				}
+				section.Reset()
+			}
+			continue
+		}
+
+		if inUp {
+			section.WriteString(line)
+			section.WriteByte('\n')
+		}
+	}
+
+	flush()
+	return statements
+}
+
+// gooseDirective returns the annotation on a "-- +goose X" line
+func gooseDirective(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "--") {
+		return "", false
+	}
+	rest := strings.TrimSpace(strings.TrimPrefix(line, "--"))
+	if !strings.HasPrefix(rest, "+goose") {
+		return "", false
+	}
+	fields := strings.Fields(strings.TrimPrefix(rest, "+goose"))
+	if len(fields) == 0 {
+		return "", false
+	}
+	return fields[0], true
+}
+
+// Load replays the Up section of every migration in dir, in version order
+func Load(dir string) (*Schema, error) {
+	migrations, err := Migrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schema{}
+	for _, m := range migrations {
+		data, err := os.ReadFile(m.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(m.Path), err)
+		}
+		if err := s.ApplyMigration(string(data)); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(m.Path), err)
+		}
+	}
+	return s, nil
+}
+
+// ApplyMigration replays the Up section of a goose migration
+func (s *Schema) ApplyMigration(src string) error {
+	for _, stmt := range UpStatements(src) {
+		if err := s.Apply(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
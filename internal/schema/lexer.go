@@ -0,0 +1,169 @@
+package schema
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokQuotedIdent
+	tokString
+	tokNumber
+	tokPunct
+)
+
+// token is a lexical token of a SQL statement. Pos and End are byte offsets
+// into the statement text, so the original spelling can be recovered.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	end  int
+}
+
+// is reports whether t is the keyword kw (case-insensitive)
+func (t token) is(kw string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, kw)
+}
+
+// isPunct reports whether t is the punctuation p
+func (t token) isPunct(p string) bool {
+	return t.kind == tokPunct && t.text == p
+}
+
+// tokenize splits a single SQL statement into tokens, dropping comments
+func tokenize(src string) []token {
+	var tokens []token
+	i := 0
+
+	for i < len(src) {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '-' && i+1 < len(src) && src[i+1] == '-', c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 4
+			}
+
+		case c == '\'':
+			start := i
+			text, next := readQuoted(src, i, '\'')
+			tokens = append(tokens, token{kind: tokString, text: text, pos: start, end: next})
+			i = next
+
+		case c == '`' || c == '"':
+			start := i
+			text, next := readQuoted(src, i, c)
+			tokens = append(tokens, token{kind: tokQuotedIdent, text: text, pos: start, end: next})
+			i = next
+
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: src[start:i], pos: start, end: i})
+
+		case isIdentStart(rune(c)):
+			start := i
+			for i < len(src) && isIdentPart(rune(src[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[start:i], pos: start, end: i})
+
+		default:
+			tokens = append(tokens, token{kind: tokPunct, text: string(c), pos: i, end: i + 1})
+			i++
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(src), end: len(src)})
+}
+
+// readQuoted reads a quoted string or identifier starting at src[i], where a
+// doubled quote character escapes itself. It returns the unquoted text and the
+// offset just past the closing quote.
+func readQuoted(src string, i int, quote byte) (string, int) {
+	var sb strings.Builder
+	i++
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\\' && quote == '\'' && i+1 < len(src):
+			sb.WriteByte(src[i+1])
+			i += 2
+		case c == quote && i+1 < len(src) && src[i+1] == quote:
+			sb.WriteByte(quote)
+			i += 2
+		case c == quote:
+			return sb.String(), i + 1
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String(), i
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
+
+// splitStatements splits SQL text into statements on top-level semicolons
+func splitStatements(src string) []string {
+	var statements []string
+	start := 0
+	i := 0
+
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '-' && i+1 < len(src) && src[i+1] == '-', c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 4
+			}
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			_, i = readQuoted(src, i, c)
+			continue
+		case c == ';':
+			if stmt := strings.TrimSpace(src[start:i]); stmt != "" {
+				statements = append(statements, stmt)
+			}
+			start = i + 1
+		}
+		i++
+	}
+
+	if stmt := strings.TrimSpace(src[start:]); stmt != "" && len(tokenize(stmt)) > 1 {
+		statements = append(statements, stmt)
+	}
+	return statements
+}
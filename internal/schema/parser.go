@@ -0,0 +1,939 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Apply replays a single DDL statement against s. Statements that don't
+// change table structure (INSERT, SET, CREATE VIEW, ...) are ignored.
+func (s *Schema) Apply(stmt string) error {
+	p := &parser{src: stmt, tokens: tokenize(stmt)}
+
+	var err error
+	switch {
+	case p.accept("CREATE"):
+		p.accept("TEMPORARY")
+		switch {
+		case p.accept("TABLE"):
+			err = p.createTable(s)
+		case p.peek().is("UNIQUE") || p.peek().is("INDEX") || p.peek().is("FULLTEXT") || p.peek().is("SPATIAL"):
+			err = p.createIndex(s)
+		}
+	case p.accept("ALTER"):
+		if p.accept("TABLE") {
+			err = p.alterTable(s)
+		}
+	case p.accept("DROP"):
+		switch {
+		case p.accept("TABLE"):
+			p.dropTable(s)
+		case p.accept("INDEX"):
+			p.dropIndex(s)
+		}
+	case p.accept("RENAME"):
+		if p.accept("TABLE") {
+			err = p.renameTables(s)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w in %q", err, summarize(stmt))
+	}
+	return nil
+}
+
+// summarize shortens a statement for error messages
+func summarize(stmt string) string {
+	stmt = strings.Join(strings.Fields(stmt), " ")
+	if len(stmt) > 60 {
+		return stmt[:57] + "..."
+	}
+	return stmt
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) done() bool {
+	return p.peek().kind == tokEOF
+}
+
+// accept consumes the keyword sequence kws if it comes next
+func (p *parser) accept(kws ...string) bool {
+	for i, kw := range kws {
+		if p.pos+i >= len(p.tokens) || !p.tokens[p.pos+i].is(kw) {
+			return false
+		}
+	}
+	p.pos += len(kws)
+	return true
+}
+
+func (p *parser) acceptPunct(s string) bool {
+	if p.peek().isPunct(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectPunct(s string) error {
+	if !p.acceptPunct(s) {
+		return p.unexpected(fmt.Sprintf("%q", s))
+	}
+	return nil
+}
+
+func (p *parser) unexpected(want string) error {
+	t := p.peek()
+	if t.kind == tokEOF {
+		return fmt.Errorf("expected %s, found end of statement", want)
+	}
+	return fmt.Errorf("expected %s, found %q", want, p.src[t.pos:t.end])
+}
+
+// name reads an identifier, dropping any schema qualifier (db.table → table)
+func (p *parser) name() (string, error) {
+	t := p.peek()
+	if t.kind != tokIdent && t.kind != tokQuotedIdent {
+		return "", p.unexpected("a name")
+	}
+	p.pos++
+	for p.peek().isPunct(".") {
+		p.pos++
+		t = p.peek()
+		if t.kind != tokIdent && t.kind != tokQuotedIdent {
+			return "", p.unexpected("a name")
+		}
+		p.pos++
+	}
+	return t.text, nil
+}
+
+// skipIfExists consumes IF EXISTS / IF NOT EXISTS
+func (p *parser) skipIfExists() {
+	if !p.accept("IF", "EXISTS") {
+		p.accept("IF", "NOT", "EXISTS")
+	}
+}
+
+// skipGroup consumes a balanced parenthesised group starting at "(" and returns its source text
+func (p *parser) skipGroup() string {
+	start := p.peek().pos
+	depth := 0
+	for !p.done() {
+		t := p.next()
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+			if depth == 0 {
+				return p.src[start:t.end]
+			}
+		}
+	}
+	return p.src[start:]
+}
+
+// skipToComma consumes tokens up to the next top-level "," or ")" without consuming it
+func (p *parser) skipToComma() {
+	for !p.done() {
+		t := p.peek()
+		switch {
+		case t.isPunct(",") || t.isPunct(")"):
+			return
+		case t.isPunct("("):
+			p.skipGroup()
+		default:
+			p.pos++
+		}
+	}
+}
+
+// nameList reads "(a, b(10) DESC, c)" and returns the column names
+func (p *parser) nameList() ([]string, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		if p.peek().isPunct("(") {
+			// functional key part, e.g. ((lower(email)))
+			p.skipGroup()
+		} else {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			// "col(10)" is a prefix length, "lower(col)" an expression
+			if !p.peek().isPunct("(") || p.tokens[p.pos+1].kind == tokNumber {
+				names = append(names, name)
+			}
+		}
+		p.skipToComma()
+		if p.acceptPunct(")") {
+			return names, nil
+		}
+		if err := p.expectPunct(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// expr reads a default or ON UPDATE expression and returns it as written
+func (p *parser) expr() string {
+	start := p.peek().pos
+	switch {
+	case p.peek().isPunct("("):
+		p.skipGroup()
+	case p.peek().isPunct("-") || p.peek().isPunct("+"):
+		p.pos++
+		p.next()
+	default:
+		p.next()
+		if p.peek().isPunct("(") {
+			p.skipGroup()
+		}
+	}
+	// PostgreSQL casts, e.g. 'active'::status
+	for p.peek().isPunct(":") {
+		p.pos++
+		p.acceptPunct(":")
+		p.next()
+	}
+	return p.src[start:p.tokens[p.pos-1].end]
+}
+
+func (p *parser) createTable(s *Schema) error {
+	p.skipIfExists()
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if p.accept("LIKE") {
+		src, err := p.name()
+		if err != nil {
+			return err
+		}
+		if t := s.Table(src); t != nil {
+			s.dropTable(name)
+			s.Tables = append(s.Tables, cloneTable(t, name))
+		}
+		return nil
+	}
+
+	t := &Table{Name: name}
+	if err := p.expectPunct("("); err != nil {
+		return err
+	}
+	for {
+		if err := p.tableElement(t); err != nil {
+			return err
+		}
+		if p.acceptPunct(")") {
+			break
+		}
+		if err := p.expectPunct(","); err != nil {
+			return err
+		}
+	}
+
+	s.dropTable(name)
+	s.Tables = append(s.Tables, t)
+	return nil
+}
+
+// tableElement parses a column or constraint inside CREATE TABLE ( ... )
+func (p *parser) tableElement(t *Table) error {
+	if handled, err := p.constraint(t); handled || err != nil {
+		return err
+	}
+	c, err := p.column()
+	if err != nil {
+		return err
+	}
+	t.Columns = append(t.Columns, c.Column)
+	c.apply(t)
+	return nil
+}
+
+// constraint parses a table-level key or constraint if one comes next
+func (p *parser) constraint(t *Table) (bool, error) {
+	start := p.pos
+	constraintName := ""
+	if p.accept("CONSTRAINT") {
+		if !p.peek().is("PRIMARY") && !p.peek().is("UNIQUE") && !p.peek().is("FOREIGN") && !p.peek().is("CHECK") {
+			name, err := p.name()
+			if err != nil {
+				return true, err
+			}
+			constraintName = name
+		}
+	}
+
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		p.indexOptions()
+		cols, err := p.nameList()
+		if err != nil {
+			return true, err
+		}
+		t.PrimaryKey = cols
+		for _, col := range cols {
+			if c := t.Column(col); c != nil {
+				c.Nullable = false
+			}
+		}
+
+	case p.accept("UNIQUE"):
+		if !p.accept("KEY") {
+			p.accept("INDEX")
+		}
+		idx, err := p.index(constraintName, true)
+		if err != nil {
+			return true, err
+		}
+		t.addIndex(idx)
+
+	case p.accept("KEY"), p.accept("INDEX"), p.accept("FULLTEXT"), p.accept("SPATIAL"):
+		if !p.accept("KEY") {
+			p.accept("INDEX")
+		}
+		idx, err := p.index(constraintName, false)
+		if err != nil {
+			return true, err
+		}
+		t.addIndex(idx)
+
+	case p.accept("FOREIGN", "KEY"):
+		if !p.peek().isPunct("(") {
+			name, err := p.name()
+			if err != nil {
+				return true, err
+			}
+			if constraintName == "" {
+				constraintName = name
+			}
+		}
+		cols, err := p.nameList()
+		if err != nil {
+			return true, err
+		}
+		fk, err := p.references(cols)
+		if err != nil {
+			return true, err
+		}
+		fk.Name = constraintName
+		if fk.Name == "" {
+			fk.Name = fmt.Sprintf("fk_%s_%s", t.Name, strings.Join(cols, "_"))
+		}
+		t.ForeignKeys = append(t.ForeignKeys, fk)
+
+	case p.accept("CHECK"):
+		p.skipGroup()
+		p.skipToComma()
+
+	default:
+		p.pos = start
+		return false, nil
+	}
+	return true, nil
+}
+
+// index parses "[name] [USING x] (cols) [options]"
+func (p *parser) index(name string, unique bool) (*Index, error) {
+	if !p.peek().isPunct("(") && !p.peek().is("USING") {
+		n, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		name = n
+	}
+	p.indexOptions()
+	cols, err := p.nameList()
+	if err != nil {
+		return nil, err
+	}
+	p.skipToComma()
+	return &Index{Name: name, Columns: cols, Unique: unique}, nil
+}
+
+func (p *parser) indexOptions() {
+	if p.accept("USING") {
+		p.next()
+	}
+}
+
+// references parses "REFERENCES table (cols) [ON DELETE x] [ON UPDATE y]"
+func (p *parser) references(cols []string) (*ForeignKey, error) {
+	if !p.accept("REFERENCES") {
+		return nil, p.unexpected("REFERENCES")
+	}
+	table, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	fk := &ForeignKey{Columns: cols, RefTable: table}
+	if p.peek().isPunct("(") {
+		if fk.RefColumns, err = p.nameList(); err != nil {
+			return nil, err
+		}
+	}
+	for p.accept("ON") {
+		switch {
+		case p.accept("DELETE"):
+			fk.OnDelete = p.referenceAction()
+		case p.accept("UPDATE"):
+			fk.OnUpdate = p.referenceAction()
+		}
+	}
+	p.accept("MATCH", "FULL")
+	p.accept("MATCH", "SIMPLE")
+	return fk, nil
+}
+
+func (p *parser) referenceAction() string {
+	for _, action := range [][]string{{"SET", "NULL"}, {"SET", "DEFAULT"}, {"NO", "ACTION"}, {"CASCADE"}, {"RESTRICT"}} {
+		if p.accept(action...) {
+			return strings.Join(action, " ")
+		}
+	}
+	return ""
+}
+
+// parsedColumn is a column plus the inline keys declared with it
+type parsedColumn struct {
+	*Column
+	primaryKey bool
+	unique     bool
+	foreignKey *ForeignKey
+}
+
+// apply records the column's inline keys on t
+func (c parsedColumn) apply(t *Table) {
+	if c.primaryKey {
+		t.PrimaryKey = []string{c.Name}
+	}
+	if c.unique {
+		t.addIndex(&Index{Name: c.Name, Columns: []string{c.Name}, Unique: true})
+	}
+	if c.foreignKey != nil {
+		c.foreignKey.Name = fmt.Sprintf("fk_%s_%s", t.Name, c.Name)
+		t.ForeignKeys = append(t.ForeignKeys, c.foreignKey)
+	}
+}
+
+// postgresTypes maps multi-word PostgreSQL types onto a single base type
+var postgresTypes = []struct {
+	words []string
+	typ   string
+}{
+	{[]string{"DOUBLE", "PRECISION"}, "DOUBLE"},
+	{[]string{"CHARACTER", "VARYING"}, "VARCHAR"},
+	{[]string{"TIMESTAMP", "WITH", "TIME", "ZONE"}, "TIMESTAMPTZ"},
+	{[]string{"TIMESTAMP", "WITHOUT", "TIME", "ZONE"}, "TIMESTAMP"},
+	{[]string{"TIME", "WITH", "TIME", "ZONE"}, "TIMETZ"},
+	{[]string{"TIME", "WITHOUT", "TIME", "ZONE"}, "TIME"},
+}
+
+// column parses "name type [attributes...]"
+func (p *parser) column() (parsedColumn, error) {
+	start := p.peek().pos
+	name, err := p.name()
+	if err != nil {
+		return parsedColumn{}, err
+	}
+
+	c := parsedColumn{Column: &Column{Name: name, Nullable: true}}
+
+	typ := ""
+	for _, pt := range postgresTypes {
+		if p.accept(pt.words...) {
+			typ = pt.typ
+			break
+		}
+	}
+	if typ == "" {
+		t := p.next()
+		if t.kind != tokIdent {
+			return parsedColumn{}, fmt.Errorf("expected a type for column %s", name)
+		}
+		typ = strings.ToUpper(t.text)
+	}
+	c.Type = typ
+
+	if p.acceptPunct("(") {
+		for !p.acceptPunct(")") {
+			t := p.next()
+			switch {
+			case t.kind == tokEOF:
+				return parsedColumn{}, p.unexpected(`")"`)
+			case t.isPunct(","):
+			default:
+				c.Args = append(c.Args, t.text)
+			}
+		}
+	}
+	if p.acceptPunct("[") {
+		// PostgreSQL arrays are scanned as raw values
+		p.acceptPunct("]")
+		c.Type += "[]"
+	}
+
+	if err := p.columnAttributes(&c); err != nil {
+		return parsedColumn{}, err
+	}
+
+	switch c.Type {
+	case "SERIAL", "BIGSERIAL", "SMALLSERIAL":
+		c.Nullable = false
+		c.AutoIncrement = true
+	}
+	if c.primaryKey {
+		c.Nullable = false
+	}
+
+	c.Definition = strings.TrimSpace(p.src[start:p.tokens[p.pos-1].end])
+	return c, nil
+}
+
+func (p *parser) columnAttributes(c *parsedColumn) error {
+	for !p.done() && !p.peek().isPunct(",") && !p.peek().isPunct(")") && !p.peek().is("FIRST") && !p.peek().is("AFTER") {
+		switch {
+		case p.accept("UNSIGNED"):
+			c.Unsigned = true
+		case p.accept("SIGNED"), p.accept("ZEROFILL"), p.accept("BINARY"):
+		case p.accept("CHARACTER", "SET"), p.accept("CHARSET"), p.accept("COLLATE"):
+			p.next()
+		case p.accept("NOT", "NULL"):
+			c.Nullable = false
+		case p.accept("NULL"):
+			c.Nullable = true
+		case p.accept("DEFAULT"):
+			def := p.expr()
+			c.Default = &def
+		case p.accept("AUTO_INCREMENT"), p.accept("AUTOINCREMENT"):
+			c.AutoIncrement = true
+		case p.accept("PRIMARY", "KEY"):
+			c.primaryKey = true
+		case p.accept("UNIQUE"):
+			p.accept("KEY")
+			c.unique = true
+		case p.accept("KEY"):
+			c.primaryKey = true
+		case p.accept("ON", "UPDATE"):
+			c.OnUpdate = p.expr()
+		case p.accept("COMMENT"):
+			c.Comment = p.next().text
+		case p.peek().is("REFERENCES"):
+			fk, err := p.references([]string{c.Name})
+			if err != nil {
+				return err
+			}
+			c.foreignKey = fk
+		case p.accept("CONSTRAINT"):
+			p.next()
+		case p.accept("CHECK"):
+			p.skipGroup()
+		case p.accept("GENERATED", "ALWAYS", "AS", "IDENTITY"), p.accept("GENERATED", "BY", "DEFAULT", "AS", "IDENTITY"):
+			c.AutoIncrement = true
+			c.Nullable = false
+			if p.peek().isPunct("(") {
+				p.skipGroup()
+			}
+		case p.accept("GENERATED", "ALWAYS", "AS"), p.accept("AS"):
+			c.Generated = true
+			p.skipGroup()
+		default:
+			// VISIBLE, STORED, COLUMN_FORMAT ... carry no structural meaning
+			if p.peek().isPunct("(") {
+				p.skipGroup()
+			} else {
+				p.next()
+			}
+		}
+	}
+	return nil
+}
+
+func (t *Table) addIndex(idx *Index) {
+	if idx.Name == "" {
+		idx.Name = strings.Join(idx.Columns, "_")
+	}
+	if existing := t.Index(idx.Name); existing != nil {
+		*existing = *idx
+		return
+	}
+	t.Indexes = append(t.Indexes, idx)
+}
+
+func (p *parser) alterTable(s *Schema) error {
+	p.accept("ONLY")
+	p.skipIfExists()
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	t := s.Table(name)
+	if t == nil {
+		return fmt.Errorf("table %s does not exist", name)
+	}
+
+	for {
+		if err := p.alterAction(s, t); err != nil {
+			return err
+		}
+		p.skipToComma()
+		if !p.acceptPunct(",") {
+			return nil
+		}
+	}
+}
+
+func (p *parser) alterAction(s *Schema, t *Table) error {
+	switch {
+	case p.accept("ADD"):
+		if handled, err := p.constraint(t); handled || err != nil {
+			return err
+		}
+		p.accept("COLUMN")
+		p.skipIfExists()
+		if p.acceptPunct("(") {
+			for {
+				if err := p.addColumn(t); err != nil {
+					return err
+				}
+				if p.acceptPunct(")") {
+					return nil
+				}
+				if err := p.expectPunct(","); err != nil {
+					return err
+				}
+			}
+		}
+		return p.addColumn(t)
+
+	case p.accept("DROP"):
+		switch {
+		case p.accept("PRIMARY", "KEY"):
+			t.PrimaryKey = nil
+		case p.accept("FOREIGN", "KEY"), p.accept("CONSTRAINT"):
+			p.skipIfExists()
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			t.dropConstraint(name)
+		case p.accept("INDEX"), p.accept("KEY"):
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			t.dropIndex(name)
+		default:
+			p.accept("COLUMN")
+			p.skipIfExists()
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			if t.Column(name) == nil {
+				return fmt.Errorf("column %s.%s does not exist", t.Name, name)
+			}
+			t.dropColumn(name)
+		}
+
+	case p.accept("MODIFY"):
+		p.accept("COLUMN")
+		return p.replaceColumn(t, "")
+
+	case p.accept("CHANGE"):
+		p.accept("COLUMN")
+		old, err := p.name()
+		if err != nil {
+			return err
+		}
+		return p.replaceColumn(t, old)
+
+	case p.accept("RENAME"):
+		switch {
+		case p.accept("COLUMN"):
+			return p.renameColumn(t)
+		case p.accept("INDEX"), p.accept("KEY"):
+			from, err := p.name()
+			if err != nil {
+				return err
+			}
+			p.accept("TO")
+			to, err := p.name()
+			if err != nil {
+				return err
+			}
+			if idx := t.Index(from); idx != nil {
+				idx.Name = to
+			}
+		default:
+			if !p.accept("TO") {
+				p.accept("AS")
+			}
+			to, err := p.name()
+			if err != nil {
+				return err
+			}
+			s.dropTable(to)
+			t.Name = to
+		}
+
+	case p.accept("ALTER"):
+		p.accept("COLUMN")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		c := t.Column(name)
+		if c == nil {
+			return fmt.Errorf("column %s.%s does not exist", t.Name, name)
+		}
+		switch {
+		case p.accept("SET", "DEFAULT"):
+			def := p.expr()
+			c.Default = &def
+		case p.accept("DROP", "DEFAULT"):
+			c.Default = nil
+		case p.accept("SET", "NOT", "NULL"):
+			c.Nullable = false
+		case p.accept("DROP", "NOT", "NULL"):
+			c.Nullable = true
+		case p.accept("SET", "DATA", "TYPE"), p.accept("TYPE"):
+			typ := strings.ToUpper(p.next().text)
+			c.Type = typ
+			c.Args = nil
+			if p.acceptPunct("(") {
+				for !p.acceptPunct(")") && !p.done() {
+					if t := p.next(); !t.isPunct(",") {
+						c.Args = append(c.Args, t.text)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addColumn parses a column definition with an optional FIRST / AFTER position
+func (p *parser) addColumn(t *Table) error {
+	c, err := p.column()
+	if err != nil {
+		return err
+	}
+	first, after := p.position()
+	t.dropColumn(c.Name)
+	t.addColumn(c.Column, first, after)
+	c.apply(t)
+	return nil
+}
+
+// replaceColumn handles MODIFY (old == "") and CHANGE old new
+func (p *parser) replaceColumn(t *Table, old string) error {
+	c, err := p.column()
+	if err != nil {
+		return err
+	}
+	if old == "" {
+		old = c.Name
+	}
+	i := t.columnIndex(old)
+	if i < 0 {
+		return fmt.Errorf("column %s.%s does not exist", t.Name, old)
+	}
+	first, after := p.position()
+	t.renameColumn(old, c.Name)
+	if first || after != "" {
+		t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+		t.addColumn(c.Column, first, after)
+	} else {
+		t.Columns[i] = c.Column
+	}
+	c.apply(t)
+	return nil
+}
+
+func (p *parser) renameColumn(t *Table) error {
+	from, err := p.name()
+	if err != nil {
+		return err
+	}
+	if !p.accept("TO") {
+		return p.unexpected("TO")
+	}
+	to, err := p.name()
+	if err != nil {
+		return err
+	}
+	if t.Column(from) == nil {
+		return fmt.Errorf("column %s.%s does not exist", t.Name, from)
+	}
+	t.renameColumn(from, to)
+	return nil
+}
+
+// position parses an optional FIRST or AFTER column clause
+func (p *parser) position() (first bool, after string) {
+	if p.accept("FIRST") {
+		return true, ""
+	}
+	if p.accept("AFTER") {
+		name, _ := p.name()
+		return false, name
+	}
+	return false, ""
+}
+
+func (t *Table) dropIndex(name string) {
+	for i, idx := range t.Indexes {
+		if strings.EqualFold(idx.Name, name) {
+			t.Indexes = append(t.Indexes[:i], t.Indexes[i+1:]...)
+			return
+		}
+	}
+}
+
+// dropConstraint removes a foreign key or unique constraint by name
+func (t *Table) dropConstraint(name string) {
+	for i, fk := range t.ForeignKeys {
+		if strings.EqualFold(fk.Name, name) {
+			t.ForeignKeys = append(t.ForeignKeys[:i], t.ForeignKeys[i+1:]...)
+			return
+		}
+	}
+	t.dropIndex(name)
+}
+
+// createIndex handles CREATE [UNIQUE] INDEX name ON table (cols)
+func (p *parser) createIndex(s *Schema) error {
+	unique := p.accept("UNIQUE")
+	if !p.accept("FULLTEXT") {
+		p.accept("SPATIAL")
+	}
+	if !p.accept("INDEX") {
+		return p.unexpected("INDEX")
+	}
+	p.accept("CONCURRENTLY")
+	p.skipIfExists()
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if !p.accept("ON") {
+		return p.unexpected("ON")
+	}
+	p.accept("ONLY")
+	table, err := p.name()
+	if err != nil {
+		return err
+	}
+	t := s.Table(table)
+	if t == nil {
+		return fmt.Errorf("table %s does not exist", table)
+	}
+	p.indexOptions()
+	cols, err := p.nameList()
+	if err != nil {
+		return err
+	}
+	t.addIndex(&Index{Name: name, Columns: cols, Unique: unique})
+	return nil
+}
+
+// dropTable handles DROP TABLE [IF EXISTS] a, b
+func (p *parser) dropTable(s *Schema) {
+	p.skipIfExists()
+	for {
+		name, err := p.name()
+		if err != nil {
+			return
+		}
+		s.dropTable(name)
+		if !p.acceptPunct(",") {
+			return
+		}
+	}
+}
+
+// dropIndex handles DROP INDEX name ON table (MySQL) and DROP INDEX name (PostgreSQL)
+func (p *parser) dropIndex(s *Schema) {
+	p.accept("CONCURRENTLY")
+	p.skipIfExists()
+	name, err := p.name()
+	if err != nil {
+		return
+	}
+	if p.accept("ON") {
+		if table, err := p.name(); err == nil {
+			if t := s.Table(table); t != nil {
+				t.dropIndex(name)
+			}
+		}
+		return
+	}
+	for _, t := range s.Tables {
+		t.dropIndex(name)
+	}
+}
+
+// renameTables handles RENAME TABLE a TO b [, c TO d]
+func (p *parser) renameTables(s *Schema) error {
+	for {
+		from, err := p.name()
+		if err != nil {
+			return err
+		}
+		if !p.accept("TO") {
+			return p.unexpected("TO")
+		}
+		to, err := p.name()
+		if err != nil {
+			return err
+		}
+		t := s.Table(from)
+		if t == nil {
+			return fmt.Errorf("table %s does not exist", from)
+		}
+		s.dropTable(to)
+		t.Name = to
+		if !p.acceptPunct(",") {
+			return nil
+		}
+	}
+}
+
+// cloneTable copies t under a new name, for CREATE TABLE ... LIKE
+func cloneTable(t *Table, name string) *Table {
+	clone := &Table{Name: name, PrimaryKey: append([]string(nil), t.PrimaryKey...)}
+	for _, c := range t.Columns {
+		cc := *c
+		cc.Args = append([]string(nil), c.Args...)
+		clone.Columns = append(clone.Columns, &cc)
+	}
+	for _, idx := range t.Indexes {
+		clone.Indexes = append(clone.Indexes, &Index{Name: idx.Name, Columns: append([]string(nil), idx.Columns...), Unique: idx.Unique})
+	}
+	return clone
+}
@@ -0,0 +1,175 @@
+// Package schema builds a model of a database schema by replaying the DDL in
+// goose migrations, so generators can derive Go code from the tables a
+// project actually has rather than from what ready-go originally generated.
+package schema
+
+import (
+	"slices"
+	"strings"
+)
+
+// Schema is the set of tables produced by replaying migrations in order
+type Schema struct {
+	Tables []*Table
+}
+
+// Table returns the table with the given name (case-insensitive), or nil
+func (s *Schema) Table(name string) *Table {
+	for _, t := range s.Tables {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// TableNames returns the names of every table, in creation order
+func (s *Schema) TableNames() []string {
+	names := make([]string, len(s.Tables))
+	for i, t := range s.Tables {
+		names[i] = t.Name
+	}
+	return names
+}
+
+func (s *Schema) dropTable(name string) {
+	s.Tables = slices.DeleteFunc(s.Tables, func(t *Table) bool {
+		return strings.EqualFold(t.Name, name)
+	})
+}
+
+// Table is a single table definition
+type Table struct {
+	Name        string
+	Columns     []*Column
+	PrimaryKey  []string
+	Indexes     []*Index
+	ForeignKeys []*ForeignKey
+}
+
+// Column returns the column with the given name (case-insensitive), or nil
+func (t *Table) Column(name string) *Column {
+	i := t.columnIndex(name)
+	if i < 0 {
+		return nil
+	}
+	return t.Columns[i]
+}
+
+// Index returns the index with the given name (case-insensitive), or nil
+func (t *Table) Index(name string) *Index {
+	for _, idx := range t.Indexes {
+		if strings.EqualFold(idx.Name, name) {
+			return idx
+		}
+	}
+	return nil
+}
+
+func (t *Table) columnIndex(name string) int {
+	return slices.IndexFunc(t.Columns, func(c *Column) bool {
+		return strings.EqualFold(c.Name, name)
+	})
+}
+
+// addColumn inserts c at the end, first, or after the named column
+func (t *Table) addColumn(c *Column, first bool, after string) {
+	switch {
+	case first:
+		t.Columns = slices.Insert(t.Columns, 0, c)
+	case after != "":
+		if i := t.columnIndex(after); i >= 0 {
+			t.Columns = slices.Insert(t.Columns, i+1, c)
+			return
+		}
+		fallthrough
+	default:
+		t.Columns = append(t.Columns, c)
+	}
+}
+
+func (t *Table) dropColumn(name string) {
+	t.Columns = slices.DeleteFunc(t.Columns, func(c *Column) bool {
+		return strings.EqualFold(c.Name, name)
+	})
+	t.PrimaryKey = slices.DeleteFunc(t.PrimaryKey, func(col string) bool {
+		return strings.EqualFold(col, name)
+	})
+	for _, idx := range t.Indexes {
+		idx.Columns = slices.DeleteFunc(idx.Columns, func(col string) bool {
+			return strings.EqualFold(col, name)
+		})
+	}
+	t.Indexes = slices.DeleteFunc(t.Indexes, func(idx *Index) bool {
+		return len(idx.Columns) == 0
+	})
+	t.ForeignKeys = slices.DeleteFunc(t.ForeignKeys, func(fk *ForeignKey) bool {
+		return slices.ContainsFunc(fk.Columns, func(col string) bool {
+			return strings.EqualFold(col, name)
+		})
+	})
+}
+
+// renameColumn renames a column and every reference to it in keys and indexes
+func (t *Table) renameColumn(from, to string) {
+	rename := func(cols []string) {
+		for i, col := range cols {
+			if strings.EqualFold(col, from) {
+				cols[i] = to
+			}
+		}
+	}
+	if c := t.Column(from); c != nil {
+		c.Name = to
+	}
+	rename(t.PrimaryKey)
+	for _, idx := range t.Indexes {
+		rename(idx.Columns)
+	}
+	for _, fk := range t.ForeignKeys {
+		rename(fk.Columns)
+	}
+}
+
+// Column is a single column definition
+type Column struct {
+	Name string
+	// Type is the upper-case base type, e.g. VARCHAR, BIGINT or TIMESTAMP
+	Type string
+	// Args are the type arguments: lengths, precision, or ENUM/SET values
+	Args     []string
+	Unsigned bool
+	Nullable bool
+	// Default is the default expression as written, or nil when there is none
+	Default       *string
+	AutoIncrement bool
+	// Generated is true for computed columns, which cannot be written
+	Generated bool
+	// OnUpdate is the ON UPDATE expression, e.g. CURRENT_TIMESTAMP
+	OnUpdate string
+	Comment  string
+	// Definition is the column definition as written in the migration
+	Definition string
+}
+
+// IsEnum reports whether the column is an ENUM with known values
+func (c *Column) IsEnum() bool {
+	return c.Type == "ENUM" && len(c.Args) > 0
+}
+
+// Index is a secondary index or unique constraint
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// ForeignKey is a foreign key constraint
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   string
+	OnUpdate   string
+}
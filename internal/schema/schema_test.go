@@ -0,0 +1,396 @@
+package schema
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// describe renders a table on one line so cases can pin its whole shape:
+// "name: col TYPE(args) flags, ... | pk(cols) | index name(cols) | fk name(cols)->ref(cols)"
+func describe(t *Table) string {
+	var cols []string
+	for _, c := range t.Columns {
+		col := c.Name + " " + c.Type
+		if len(c.Args) > 0 {
+			col += "(" + strings.Join(c.Args, ",") + ")"
+		}
+		if c.Unsigned {
+			col += " unsigned"
+		}
+		if !c.Nullable {
+			col += " notnull"
+		}
+		if c.Default != nil {
+			col += " default=" + *c.Default
+		}
+		if c.AutoIncrement {
+			col += " autoinc"
+		}
+		if c.Generated {
+			col += " generated"
+		}
+		if c.OnUpdate != "" {
+			col += " onupdate=" + c.OnUpdate
+		}
+		if c.Comment != "" {
+			col += fmt.Sprintf(" comment=%q", c.Comment)
+		}
+		cols = append(cols, col)
+	}
+
+	parts := []string{t.Name + ": " + strings.Join(cols, ", ")}
+	if len(t.PrimaryKey) > 0 {
+		parts = append(parts, "pk("+strings.Join(t.PrimaryKey, ",")+")")
+	}
+	for _, idx := range t.Indexes {
+		kind := "index"
+		if idx.Unique {
+			kind = "unique"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s(%s)", kind, idx.Name, strings.Join(idx.Columns, ",")))
+	}
+	for _, fk := range t.ForeignKeys {
+		fkDesc := fmt.Sprintf("fk %s(%s)->%s(%s)", fk.Name, strings.Join(fk.Columns, ","), fk.RefTable, strings.Join(fk.RefColumns, ","))
+		if fk.OnDelete != "" {
+			fkDesc += " delete=" + fk.OnDelete
+		}
+		if fk.OnUpdate != "" {
+			fkDesc += " update=" + fk.OnUpdate
+		}
+		parts = append(parts, fkDesc)
+	}
+	return strings.Join(parts, " | ")
+}
+
+// migration wraps statements in a goose Up section
+func migration(up string) string {
+	return "-- +goose Up\n" + up + "\n\n-- +goose Down\nDROP TABLE IF EXISTS ignored;\n"
+}
+
+func TestApplyMigration(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []string
+		// want describes every table in creation order
+		want []string
+	}{
+		{
+			name: "backticks and UNSIGNED",
+			migrations: []string{migration("CREATE TABLE `order items` (\n" +
+				"  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n" +
+				"  `order` INT(11) UNSIGNED ZEROFILL NOT NULL,\n" +
+				"  `price` DECIMAL(10, 2) NOT NULL DEFAULT 0.00,\n" +
+				"  PRIMARY KEY (`id`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;")},
+			want: []string{"order items: id BIGINT unsigned notnull autoinc, order INT(11) unsigned notnull, price DECIMAL(10,2) notnull default=0.00 | pk(id)"},
+		},
+		{
+			name: "semicolon inside COMMENT",
+			migrations: []string{migration("CREATE TABLE notes (\n" +
+				"  id INT PRIMARY KEY,\n" +
+				"  body TEXT COMMENT 'plain text; no HTML'\n" +
+				");\n" +
+				"CREATE TABLE tags (id INT PRIMARY KEY);")},
+			want: []string{
+				`notes: id INT notnull, body TEXT comment="plain text; no HTML" | pk(id)`,
+				"tags: id INT notnull | pk(id)",
+			},
+		},
+		{
+			name: "escaped ENUM quotes",
+			migrations: []string{migration(`CREATE TABLE answers (
+  id INT PRIMARY KEY,
+  choice ENUM('it''s', 'don\'t', 'a,b') NOT NULL DEFAULT 'it''s'
+);`)},
+			want: []string{`answers: id INT notnull, choice ENUM(it's,don't,a,b) notnull default='it''s' | pk(id)`},
+		},
+		{
+			name: "generated columns",
+			migrations: []string{migration(`CREATE TABLE lines (
+  id INT GENERATED ALWAYS AS IDENTITY,
+  qty INT NOT NULL,
+  price DECIMAL(10,2) NOT NULL,
+  total DECIMAL(10,2) AS (qty * price) STORED,
+  label VARCHAR(64) GENERATED ALWAYS AS (CONCAT(qty, 'x')) VIRTUAL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);`)},
+			want: []string{"lines: id INT notnull autoinc, qty INT notnull, price DECIMAL(10,2) notnull, total DECIMAL(10,2) generated, label VARCHAR(64) generated, updated_at TIMESTAMP notnull default=CURRENT_TIMESTAMP onupdate=CURRENT_TIMESTAMP | pk(id)"},
+		},
+		{
+			name: "PostgreSQL types",
+			migrations: []string{migration(`CREATE TABLE events (
+  id BIGSERIAL PRIMARY KEY,
+  at TIMESTAMP WITH TIME ZONE NOT NULL,
+  ratio DOUBLE PRECISION,
+  tags TEXT[]
+);`)},
+			want: []string{"events: id BIGSERIAL notnull autoinc, at TIMESTAMPTZ notnull, ratio DOUBLE, tags TEXT[] | pk(id)"},
+		},
+		{
+			name: "keys and foreign keys",
+			migrations: []string{migration(`CREATE TABLE customers (id INT PRIMARY KEY, email VARCHAR(255) UNIQUE);
+CREATE TABLE orders (
+  id INT NOT NULL AUTO_INCREMENT,
+  customer_id INT NOT NULL REFERENCES customers (id) ON DELETE CASCADE,
+  code CHAR(8),
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_code (code),
+  KEY idx_customer (customer_id),
+  CONSTRAINT fk_orders_parent FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE SET NULL ON UPDATE CASCADE,
+  CHECK (id > 0)
+);`)},
+			want: []string{
+				"customers: id INT notnull, email VARCHAR(255) | pk(id) | unique email(email)",
+				"orders: id INT notnull autoinc, customer_id INT notnull, code CHAR(8) | pk(id) | unique uq_code(code) | index idx_customer(customer_id) | fk fk_orders_customer_id(customer_id)->customers(id) delete=CASCADE | fk fk_orders_parent(customer_id)->customers(id) delete=SET NULL update=CASCADE",
+			},
+		},
+		{
+			name: "ALTER ADD, DROP, RENAME and MODIFY",
+			migrations: []string{
+				migration("CREATE TABLE products (id INT PRIMARY KEY, name VARCHAR(100), legacy INT, price INT);"),
+				migration(`ALTER TABLE products ADD COLUMN sku VARCHAR(32) NOT NULL AFTER id;
+ALTER TABLE products ADD COLUMN position INT FIRST;
+ALTER TABLE products ADD (stock INT, weight DOUBLE);
+ALTER TABLE products DROP COLUMN legacy;
+ALTER TABLE products RENAME COLUMN name TO title;
+ALTER TABLE products MODIFY price DECIMAL(10,2) NOT NULL;
+ALTER TABLE products CHANGE COLUMN weight weight_kg FLOAT AFTER title;
+ALTER TABLE products ALTER COLUMN stock SET DEFAULT 0, ALTER COLUMN title SET NOT NULL;`),
+			},
+			want: []string{"products: position INT, id INT notnull, sku VARCHAR(32) notnull, title VARCHAR(100) notnull, weight_kg FLOAT, price DECIMAL(10,2) notnull, stock INT default=0 | pk(id)"},
+		},
+		{
+			name: "ALTER keys and constraints",
+			migrations: []string{
+				migration(`CREATE TABLE users (id INT PRIMARY KEY);
+CREATE TABLE posts (id INT, user_id INT, slug VARCHAR(64), CONSTRAINT fk_author FOREIGN KEY (user_id) REFERENCES users (id));`),
+				migration(`ALTER TABLE posts ADD PRIMARY KEY (id);
+ALTER TABLE posts ADD UNIQUE INDEX uq_slug (slug);
+ALTER TABLE posts ADD INDEX idx_user (user_id);
+ALTER TABLE posts RENAME INDEX idx_user TO idx_author;
+ALTER TABLE posts DROP FOREIGN KEY fk_author;
+ALTER TABLE posts ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE RESTRICT;
+ALTER TABLE posts ALTER COLUMN slug TYPE TEXT;`),
+			},
+			want: []string{
+				"users: id INT notnull | pk(id)",
+				"posts: id INT notnull, user_id INT, slug TEXT | pk(id) | unique uq_slug(slug) | index idx_author(user_id) | fk fk_user(user_id)->users(id) delete=RESTRICT",
+			},
+		},
+		{
+			name: "dropping a column drops its keys",
+			migrations: []string{
+				migration(`CREATE TABLE users (id INT PRIMARY KEY);
+CREATE TABLE sessions (id INT, user_id INT REFERENCES users (id), token CHAR(32), INDEX idx_token (token, user_id));`),
+				migration("ALTER TABLE sessions DROP user_id;"),
+			},
+			want: []string{
+				"users: id INT notnull | pk(id)",
+				"sessions: id INT, token CHAR(32) | index idx_token(token)",
+			},
+		},
+		{
+			name: "CREATE and DROP INDEX",
+			migrations: []string{migration(`CREATE TABLE orders (id INT PRIMARY KEY, status VARCHAR(16), placed_at TIMESTAMP);
+CREATE INDEX idx_status ON orders (status);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_placed ON orders USING btree (placed_at, id);
+CREATE INDEX idx_gone ON orders (placed_at);
+DROP INDEX idx_gone ON orders;
+CREATE INDEX CONCURRENTLY idx_pg ON orders (status);
+DROP INDEX IF EXISTS idx_pg;`)},
+			want: []string{"orders: id INT notnull, status VARCHAR(16), placed_at TIMESTAMP | pk(id) | index idx_status(status) | unique uq_placed(placed_at,id)"},
+		},
+		{
+			name: "RENAME, LIKE and DROP TABLE",
+			migrations: []string{
+				migration(`CREATE TABLE IF NOT EXISTS items (id INT PRIMARY KEY, name TEXT, KEY idx_name (name));
+CREATE TABLE scratch (id INT);`),
+				migration(`RENAME TABLE items TO products;
+CREATE TABLE archived_products LIKE products;
+ALTER TABLE archived_products RENAME TO products_archive;
+DROP TABLE IF EXISTS scratch, missing;
+INSERT INTO products (id, name) VALUES (1, 'ignored; not DDL');`),
+			},
+			want: []string{
+				"products: id INT notnull, name TEXT | pk(id) | index idx_name(name)",
+				"products_archive: id INT notnull, name TEXT | pk(id) | index idx_name(name)",
+			},
+		},
+		{
+			name: "StatementBegin and StatementEnd",
+			migrations: []string{`-- +goose Up
+CREATE TABLE accounts (id INT PRIMARY KEY, balance INT);
+
+-- +goose StatementBegin
+CREATE TRIGGER no_overdraft BEFORE UPDATE ON accounts
+FOR EACH ROW
+BEGIN
+  IF NEW.balance < 0 THEN
+    SET NEW.balance = 0;
+  END IF;
+END;
+-- +goose StatementEnd
+
+ALTER TABLE accounts ADD COLUMN owner VARCHAR(64);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER no_overdraft;
+-- +goose StatementEnd
+ALTER TABLE accounts DROP COLUMN owner;
+`},
+			want: []string{"accounts: id INT notnull, balance INT, owner VARCHAR(64) | pk(id)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Schema{}
+			for _, m := range tt.migrations {
+				if err := s.ApplyMigration(m); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var got []string
+			for _, table := range s.Tables {
+				got = append(got, describe(table))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("tables:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		stmt string
+		want string
+	}{
+		{"alter a missing table", "ALTER TABLE missing ADD COLUMN a INT", "table missing does not exist"},
+		{"drop a missing column", "ALTER TABLE users DROP COLUMN missing", "column users.missing does not exist"},
+		{"rename a missing column", "ALTER TABLE users RENAME COLUMN missing TO other", "column users.missing does not exist"},
+		{"modify a missing column", "ALTER TABLE users MODIFY missing INT", "column users.missing does not exist"},
+		{"index a missing table", "CREATE INDEX idx ON missing (a)", "table missing does not exist"},
+		{"rename a missing table", "RENAME TABLE missing TO other", "table missing does not exist"},
+		{"column without a type", "CREATE TABLE broken (id)", "expected a type for column id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Schema{}
+			if err := s.Apply("CREATE TABLE users (id INT PRIMARY KEY)"); err != nil {
+				t.Fatal(err)
+			}
+			err := s.Apply(tt.stmt)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Apply(%q) = %v, want an error containing %q", tt.stmt, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "comments and quoted semicolons",
+			src: `-- +goose Up
+-- a comment; with a semicolon
+CREATE TABLE a (x TEXT DEFAULT ';'); /* block; comment */
+# MySQL comment;
+INSERT INTO a VALUES ("b;c"), ` + "(`d;e`)" + `;
+-- +goose Down
+DROP TABLE a;`,
+			want: []string{
+				"-- a comment; with a semicolon\nCREATE TABLE a (x TEXT DEFAULT ';')",
+				"/* block; comment */\n# MySQL comment;\nINSERT INTO a VALUES (\"b;c\"), (`d;e`)",
+			},
+		},
+		{
+			name: "statement block",
+			src: `-- +goose Up
+-- +goose StatementBegin
+CREATE FUNCTION f() RETURNS INT BEGIN RETURN 1; END;
+-- +goose StatementEnd
+CREATE TABLE b (id INT)`,
+			want: []string{
+				"CREATE FUNCTION f() RETURNS INT BEGIN RETURN 1; END",
+				"CREATE TABLE b (id INT)",
+			},
+		},
+		{
+			name: "no Up section",
+			src:  "CREATE TABLE c (id INT);",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UpStatements(tt.src); !slices.Equal(got, tt.want) {
+				t.Errorf("UpStatements:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// Replayed by version, not by file name order
+		"00010_add_email.sql":    migration("ALTER TABLE users ADD COLUMN email VARCHAR(255);"),
+		"00002_create_users.sql": migration("CREATE TABLE users (id INT PRIMARY KEY);"),
+		"README.md":              "not a migration",
+		"notes.sql":              "no version",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := describe(s.Table("USERS")), "users: id INT notnull, email VARCHAR(255) | pk(id)"; got != want {
+		t.Errorf("users = %q, want %q", got, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "00011_broken.sql"), []byte(migration("ALTER TABLE missing DROP COLUMN a;")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil || !strings.HasPrefix(err.Error(), "00011_broken.sql: ") {
+		t.Errorf("Load = %v, want an error naming the migration", err)
+	}
+}
+
+func TestParseMigrationName(t *testing.T) {
+	tests := []struct {
+		file    string
+		version int64
+		name    string
+		ok      bool
+	}{
+		{"00001_create_users.sql", 1, "create_users", true},
+		{"20240102150405_add_sku.sql", 20240102150405, "add_sku", true},
+		{"db/migrations/00003_x.sql", 3, "x", true},
+		{"00000_zero.sql", 0, "", false},
+		{"create_users.sql", 0, "", false},
+		{"00001.sql", 0, "", false},
+		{"00001_create_users.go", 0, "", false},
+	}
+	for _, tt := range tests {
+		version, name, ok := ParseMigrationName(tt.file)
+		if version != tt.version || name != tt.name || ok != tt.ok {
+			t.Errorf("ParseMigrationName(%q) = %d, %q, %v; want %d, %q, %v", tt.file, version, name, ok, tt.version, tt.name, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package schema
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Versioning is a goose migration numbering scheme
+type Versioning string
+
+const (
+	// VersioningSequential numbers migrations 00001, 00002, ... (goose create -s)
+	VersioningSequential Versioning = "sequential"
+	// VersioningTimestamp numbers migrations by creation time, 20060102150405
+	VersioningTimestamp Versioning = "timestamp"
+)
+
+// Versionings lists every supported Versioning
+var Versionings = []Versioning{VersioningSequential, VersioningTimestamp}
+
+// timestampVersion is the smallest version treated as a timestamp rather than
+// a sequence number
+const timestampVersion = 10000000000000
+
+const timestampLayout = "20060102150405"
+
+// Timestamped reports whether the migration uses a timestamp version
+func (m Migration) Timestamped() bool {
+	return m.Version >= timestampVersion
+}
+
+// FileName returns the migration's file name for a new version
+func (m Migration) FileName(version string) string {
+	return version + "_" + m.Name + ".sql"
+}
+
+// DetectVersioning returns the scheme used by migrations, or "" when there are
+// none or both schemes are mixed
+func DetectVersioning(migrations []Migration) Versioning {
+	var v Versioning
+	for _, m := range migrations {
+		scheme := VersioningSequential
+		if m.Timestamped() {
+			scheme = VersioningTimestamp
+		}
+		if v != "" && v != scheme {
+			return ""
+		}
+		v = scheme
+	}
+	return v
+}
+
+// NextVersion returns the version prefix for a new migration. An empty
+// versioning follows the latest existing migration, defaulting to timestamps.
+func NextVersion(migrations []Migration, versioning Versioning, now time.Time) string {
+	var latest int64
+	for _, m := range migrations {
+		latest = max(latest, m.Version)
+	}
+
+	if versioning == "" {
+		versioning = VersioningTimestamp
+		if latest > 0 && latest < timestampVersion {
+			versioning = VersioningSequential
+		}
+	}
+
+	if versioning == VersioningSequential {
+		return formatSequential(latest + 1)
+	}
+	return nextTimestamp(latest, now)
+}
+
+func formatSequential(n int64) string {
+	return fmt.Sprintf("%05d", n)
+}
+
+// nextTimestamp returns now as a version, moved past latest if the clock is behind it
+func nextTimestamp(latest int64, now time.Time) string {
+	t := now.UTC()
+	if latest >= timestampVersion {
+		if last, err := time.Parse(timestampLayout, strconv.FormatInt(latest, 10)); err == nil && !t.After(last) {
+			t = last.Add(time.Second)
+		}
+	}
+	return t.Format(timestampLayout)
+}
+
+// ProblemKind classifies a versioning problem
+type ProblemKind string
+
+const (
+	// ProblemDuplicate means two migrations share a version; goose refuses to run
+	ProblemDuplicate ProblemKind = "duplicate"
+	// ProblemOutOfOrder means a migration was added after one with a higher
+	// version, so databases already past that version will never apply it
+	ProblemOutOfOrder ProblemKind = "out-of-order"
+	// ProblemGap means sequential versions skip a number
+	ProblemGap ProblemKind = "gap"
+	// ProblemScheme means a migration doesn't follow the configured versioning
+	ProblemScheme ProblemKind = "scheme"
+)
+
+// Problem is a single finding from CheckVersions
+type Problem struct {
+	Kind    ProblemKind
+	Message string
+	Files   []string
+}
+
+// Fatal reports whether the problem breaks goose (as opposed to being untidy)
+func (p Problem) Fatal() bool {
+	return p.Kind == ProblemDuplicate || p.Kind == ProblemOutOfOrder
+}
+
+// CheckVersions looks for duplicate versions, out-of-order additions, gaps in
+// sequential numbering and migrations that don't follow versioning. Out-of-order
+// detection needs Migration.Added; migrations where it is zero are not compared.
+func CheckVersions(migrations []Migration, versioning Versioning) []Problem {
+	var problems []Problem
+
+	byVersion := groupVersions(migrations)
+	for _, version := range sortedKeys(byVersion) {
+		if dups := byVersion[version]; len(dups) > 1 {
+			problems = append(problems, Problem{
+				Kind:    ProblemDuplicate,
+				Message: fmt.Sprintf("%d migrations share version %d", len(dups), version),
+				Files:   fileNames(dups),
+			})
+		}
+	}
+
+	for _, m := range migrations {
+		if m.Added == 0 {
+			continue
+		}
+		for _, other := range migrations {
+			if other.Added != 0 && other.Version > m.Version && other.Added < m.Added {
+				problems = append(problems, Problem{
+					Kind:    ProblemOutOfOrder,
+					Message: fmt.Sprintf("%s was added after %s but has a lower version", filepath.Base(m.Path), filepath.Base(other.Path)),
+					Files:   []string{filepath.Base(m.Path), filepath.Base(other.Path)},
+				})
+				break
+			}
+		}
+	}
+
+	var sequential []Migration
+	for _, m := range migrations {
+		if !m.Timestamped() {
+			sequential = append(sequential, m)
+		}
+	}
+	var expected int64 = 1
+	for _, version := range sortedKeys(groupVersions(sequential)) {
+		if version > expected {
+			msg := fmt.Sprintf("versions %s to %s are missing", formatSequential(expected), formatSequential(version-1))
+			if version == expected+1 {
+				msg = fmt.Sprintf("version %s is missing", formatSequential(expected))
+			}
+			problems = append(problems, Problem{Kind: ProblemGap, Message: msg})
+		}
+		expected = version + 1
+	}
+
+	if versioning != "" {
+		var stray []Migration
+		for _, m := range migrations {
+			if m.Timestamped() != (versioning == VersioningTimestamp) {
+				stray = append(stray, m)
+			}
+		}
+		if len(stray) > 0 {
+			problems = append(problems, Problem{
+				Kind:    ProblemScheme,
+				Message: fmt.Sprintf("%d migration(s) don't use %s versioning", len(stray), versioning),
+				Files:   fileNames(stray),
+			})
+		}
+	}
+
+	return problems
+}
+
+// Rename moves a migration to a new version
+type Rename struct {
+	From Migration
+	To   string // new file name
+}
+
+// Renumber plans the renames that make migrations safe to run after a rebase
+// or merge. Migrations are walked in the order they were added (by version
+// when that is unknown); any migration whose version is not above every
+// migration before it — a duplicate or an out-of-order addition — is moved
+// to the end with a fresh version. Under sequential versioning, timestamped
+// migrations are converted too. Migrations that are already in order keep
+// their names, since databases record applied versions.
+func Renumber(migrations []Migration, versioning Versioning, now time.Time) []Rename {
+	ordered := slices.Clone(migrations)
+	slices.SortStableFunc(ordered, func(a, b Migration) int {
+		if a.Added != 0 && b.Added != 0 && a.Added != b.Added {
+			return cmp.Compare(a.Added, b.Added)
+		}
+		return cmp.Or(cmp.Compare(a.Version, b.Version), strings.Compare(a.Name, b.Name))
+	})
+
+	var (
+		kept  []Migration
+		moved []Migration
+		high  int64
+	)
+	for _, m := range ordered {
+		wrongScheme := versioning == VersioningSequential && m.Timestamped()
+		if m.Version <= high || wrongScheme {
+			moved = append(moved, m)
+			continue
+		}
+		kept = append(kept, m)
+		high = m.Version
+	}
+
+	renames := make([]Rename, 0, len(moved))
+	for _, m := range moved {
+		version := NextVersion(kept, versioning, now)
+		renames = append(renames, Rename{From: m, To: m.FileName(version)})
+
+		v, _ := strconv.ParseInt(version, 10, 64)
+		kept = append(kept, Migration{Version: v, Name: m.Name})
+	}
+	return renames
+}
+
+func groupVersions(migrations []Migration) map[int64][]Migration {
+	g := map[int64][]Migration{}
+	for _, m := range migrations {
+		g[m.Version] = append(g[m.Version], m)
+	}
+	return g
+}
+
+func sortedKeys(m map[int64][]Migration) []int64 {
+	keys := make([]int64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func fileNames(migrations []Migration) []string {
+	names := make([]string, len(migrations))
+	for i, m := range migrations {
+		names[i] = filepath.Base(m.Path)
+	}
+	return names
+}
@@ -0,0 +1,214 @@
+package schema
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// mig builds a migration; added is the Unix time it was committed, 0 when unknown
+func mig(version int64, name string, added int64) Migration {
+	return Migration{Version: version, Name: name, Path: "database/migrations/" + Migration{Name: name}.FileName(formatVersion(version)), Added: added}
+}
+
+func formatVersion(v int64) string {
+	if v >= timestampVersion {
+		return strconv.FormatInt(v, 10)
+	}
+	return formatSequential(v)
+}
+
+var now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+		versioning Versioning
+		want       string
+	}{
+		{"first sequential", nil, VersioningSequential, "00001"},
+		{"next sequential", []Migration{mig(1, "a", 0), mig(7, "b", 0)}, VersioningSequential, "00008"},
+		{"first timestamp", nil, VersioningTimestamp, "20240301120000"},
+		{"timestamp after sequential", []Migration{mig(3, "a", 0)}, VersioningTimestamp, "20240301120000"},
+		{"clock behind the latest", []Migration{mig(20240301120000, "a", 0)}, VersioningTimestamp, "20240301120001"},
+		{"unset follows sequential", []Migration{mig(2, "a", 0)}, "", "00003"},
+		{"unset follows timestamps", []Migration{mig(20230101000000, "a", 0)}, "", "20240301120000"},
+		{"unset without migrations", nil, "", "20240301120000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextVersion(tt.migrations, tt.versioning, now); got != tt.want {
+				t.Errorf("NextVersion = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectVersioning(t *testing.T) {
+	tests := []struct {
+		migrations []Migration
+		want       Versioning
+	}{
+		{nil, ""},
+		{[]Migration{mig(1, "a", 0), mig(2, "b", 0)}, VersioningSequential},
+		{[]Migration{mig(20240101000000, "a", 0)}, VersioningTimestamp},
+		{[]Migration{mig(1, "a", 0), mig(20240101000000, "b", 0)}, ""},
+	}
+	for _, tt := range tests {
+		if got := DetectVersioning(tt.migrations); got != tt.want {
+			t.Errorf("DetectVersioning(%v) = %q, want %q", tt.migrations, got, tt.want)
+		}
+	}
+}
+
+func TestCheckVersions(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+		versioning Versioning
+		want       []Problem
+	}{
+		{
+			name:       "in order",
+			migrations: []Migration{mig(1, "a", 100), mig(2, "b", 200), mig(3, "c", 300)},
+			versioning: VersioningSequential,
+		},
+		{
+			name:       "duplicate",
+			migrations: []Migration{mig(1, "a", 0), mig(2, "b", 0), mig(2, "c", 0)},
+			want: []Problem{{
+				Kind:    ProblemDuplicate,
+				Message: "2 migrations share version 2",
+				Files:   []string{"00002_b.sql", "00002_c.sql"},
+			}},
+		},
+		{
+			name:       "out of order",
+			migrations: []Migration{mig(1, "a", 100), mig(2, "late", 300), mig(3, "c", 200)},
+			want: []Problem{{
+				Kind:    ProblemOutOfOrder,
+				Message: "00002_late.sql was added after 00003_c.sql but has a lower version",
+				Files:   []string{"00002_late.sql", "00003_c.sql"},
+			}},
+		},
+		{
+			name:       "unknown history is not compared",
+			migrations: []Migration{mig(1, "a", 100), mig(2, "b", 0), mig(3, "c", 50)},
+			want: []Problem{{
+				Kind:    ProblemOutOfOrder,
+				Message: "00001_a.sql was added after 00003_c.sql but has a lower version",
+				Files:   []string{"00001_a.sql", "00003_c.sql"},
+			}},
+		},
+		{
+			name:       "one version missing",
+			migrations: []Migration{mig(1, "a", 0), mig(2, "b", 0), mig(4, "d", 0)},
+			want:       []Problem{{Kind: ProblemGap, Message: "version 00003 is missing"}},
+		},
+		{
+			name:       "several versions missing",
+			migrations: []Migration{mig(2, "b", 0), mig(6, "f", 0)},
+			want: []Problem{
+				{Kind: ProblemGap, Message: "version 00001 is missing"},
+				{Kind: ProblemGap, Message: "versions 00003 to 00005 are missing"},
+			},
+		},
+		{
+			name:       "timestamps have no gaps",
+			migrations: []Migration{mig(20240101000000, "a", 0), mig(20240301000000, "b", 0)},
+			versioning: VersioningTimestamp,
+		},
+		{
+			name:       "wrong scheme",
+			migrations: []Migration{mig(1, "a", 0), mig(20240101000000, "b", 0)},
+			versioning: VersioningSequential,
+			want: []Problem{{
+				Kind:    ProblemScheme,
+				Message: "1 migration(s) don't use sequential versioning",
+				Files:   []string{"20240101000000_b.sql"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckVersions(tt.migrations, tt.versioning)
+			if !slices.EqualFunc(got, tt.want, func(a, b Problem) bool {
+				return a.Kind == b.Kind && a.Message == b.Message && slices.Equal(a.Files, b.Files)
+			}) {
+				t.Errorf("CheckVersions:\n%+v\nwant:\n%+v", got, tt.want)
+			}
+		})
+	}
+
+	fatal := map[ProblemKind]bool{ProblemDuplicate: true, ProblemOutOfOrder: true, ProblemGap: false, ProblemScheme: false}
+	for kind, want := range fatal {
+		if got := (Problem{Kind: kind}).Fatal(); got != want {
+			t.Errorf("%s Fatal = %v, want %v", kind, got, want)
+		}
+	}
+}
+
+func TestRenumber(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+		versioning Versioning
+		want       map[string]string
+	}{
+		{
+			name:       "in order",
+			migrations: []Migration{mig(1, "a", 100), mig(2, "b", 200)},
+			versioning: VersioningSequential,
+			want:       map[string]string{},
+		},
+		{
+			// Both branches added 00003; the one merged last moves
+			name:       "duplicate after a rebase",
+			migrations: []Migration{mig(1, "a", 100), mig(2, "b", 200), mig(3, "mine", 400), mig(3, "theirs", 300)},
+			versioning: VersioningSequential,
+			want:       map[string]string{"mine": "00004_mine.sql"},
+		},
+		{
+			name:       "out of order",
+			migrations: []Migration{mig(1, "a", 100), mig(2, "late", 300), mig(3, "c", 200), mig(4, "d", 250)},
+			versioning: VersioningSequential,
+			want:       map[string]string{"late": "00005_late.sql"},
+		},
+		{
+			name:       "duplicates without history move by name",
+			migrations: []Migration{mig(1, "b", 0), mig(1, "a", 0), mig(1, "c", 0)},
+			versioning: VersioningSequential,
+			want:       map[string]string{"b": "00002_b.sql", "c": "00003_c.sql"},
+		},
+		{
+			name:       "timestamps under sequential versioning",
+			migrations: []Migration{mig(1, "a", 100), mig(20240101000000, "b", 200), mig(20240102000000, "c", 300)},
+			versioning: VersioningSequential,
+			want:       map[string]string{"b": "00002_b.sql", "c": "00003_c.sql"},
+		},
+		{
+			name:       "timestamp duplicates",
+			migrations: []Migration{mig(20240101000000, "a", 100), mig(20240101000000, "b", 200)},
+			versioning: VersioningTimestamp,
+			want:       map[string]string{"b": "20240301120000_b.sql"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			for _, r := range Renumber(tt.migrations, tt.versioning, now) {
+				got[r.From.Name] = r.To
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("renames = %v, want %v", got, tt.want)
+			}
+			for name, to := range tt.want {
+				if got[name] != to {
+					t.Errorf("%s renamed to %q, want %q", name, got[name], to)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+)
+
+// AuthOptions configures AddAuth
+type AuthOptions struct {
+	Common
+
+	// JWT selects bearer JWT authentication, the only scheme so far (required)
+	JWT bool
+}
+
+// AddAuth adds an auth package next to the handlers whose Authenticate
+// middleware verifies bearer JWTs signed with an HS256 secret, an RS256
+// public key or the keys of a JWKS URL, and puts the Principal they are
+// issued to in the request context. It adds the JWT settings to
+// config.Config and .env.example and installs Authenticate in SetupHandler;
+// routes restrict themselves to roles with auth.RequireRoles.
+func AddAuth(ctx context.Context, opts AuthOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "add auth")
+
+	cfg := config.NewAuthConfig(opts.JWT)
+	cfg.ProjectPath = opts.ProjectDir
+	cfg.Process()
+
+	if err := cfg.ApplyManifest(); err != nil {
+		return nil, &Error{Op: "add auth", Err: err}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, &Error{Op: "add auth", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n🔍 Detected project at: %s", cfg.ProjectPath))
+	rep.Info("🔐 Adding JWT authentication\n")
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, &Error{Op: "add auth", Err: err}
+	}
+
+	gen := generator.NewAuthGenerator(cfg, env)
+	if err := gen.Generate(ctx); err != nil {
+		return nil, &Error{Op: "add auth", Err: err}
+	}
+
+	rep.Info("\n✅ JWT authentication added successfully!\n")
+	rep.NextStep("Set JWT_SECRET, JWT_PUBLIC_KEY_FILE or JWT_JWKS_URL, and JWT_ISSUER and JWT_AUDIENCE, in .env")
+	rep.NextStep(fmt.Sprintf("Restrict routes with auth.RequireRoles(\"admin\") in %s", cfg.Router.Setup))
+	rep.NextStep(fmt.Sprintf("go test ./%s/auth/", cfg.Layout.Handlers))
+
+	return newResult(cfg.ProjectPath, rep), nil
+}
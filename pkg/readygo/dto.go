@@ -0,0 +1,58 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+)
+
+// DTOOptions configures AddDTO
+type DTOOptions struct {
+	Common
+
+	// Entity is the entity name in PascalCase, e.g. "Product" (required)
+	Entity string
+	// Table is the entity's table (default: the plural of Entity in lowercase)
+	Table string
+}
+
+// AddDTO writes the Create and Update request DTOs of a table created by the
+// migrations, with validate tags derived from its columns. The first DTO
+// also adds the shared validator to the util package.
+func AddDTO(ctx context.Context, opts DTOOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "add dto")
+
+	cfg := config.NewEntityConfig(opts.Entity)
+	cfg.TableName = opts.Table
+	cfg.ProjectPath = opts.ProjectDir
+	cfg.Process()
+
+	if err := cfg.ApplyManifest(); err != nil {
+		return nil, &Error{Op: "add dto", Err: err}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, &Error{Op: "add dto", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n🔍 Detected project at: %s", cfg.ProjectPath))
+	rep.Info(fmt.Sprintf("📝 Adding DTOs: %s (%s)\n", cfg.EntityName, cfg.TableName))
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, &Error{Op: "add dto", Err: err}
+	}
+
+	gen := generator.NewDTOGenerator(cfg, env)
+	if err := gen.Generate(ctx); err != nil {
+		return nil, &Error{Op: "add dto", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n✅ DTOs for '%s' added successfully!\n", cfg.EntityName))
+	rep.NextStep(fmt.Sprintf("Review the validate tags in %s/%s/dto.go", cfg.Layout.Handlers, cfg.EntityNameLower))
+	rep.NextStep("Bind the body, then call util.Validate(&req) and return util.HandleError(c, err) on failure")
+
+	return newResult(cfg.ProjectPath, rep), nil
+}
@@ -0,0 +1,73 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+)
+
+// EndpointOptions configures AddEndpoint
+type EndpointOptions struct {
+	Common
+
+	// Method is the HTTP method: GET, POST, PUT, PATCH or DELETE (required)
+	Method string
+	// Path is the route in Fiber syntax, e.g. "/v1/orders/:id/cancel"
+	// (required). {id} parameters are accepted too.
+	Path string
+	// Package is the handler package (default: the singular of the first path
+	// segment after the version, e.g. "order")
+	Package string
+	// Name is the handler name without its Handler suffix (default: the last
+	// path segment in PascalCase, e.g. "Cancel")
+	Name string
+	// Query and Body are the fields of the query string and JSON body, each
+	// name[:type] with a type of string (default), int, int32, int64, float,
+	// bool or time
+	Query []string
+	Body  []string
+}
+
+// AddEndpoint adds a handler for a route that is not an entity CRUD, binding
+// its path parameters, query string and body into typed structs, with a test
+// skeleton. The route is registered in the project's route setup function.
+func AddEndpoint(ctx context.Context, opts EndpointOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "add endpoint")
+
+	cfg := config.NewEndpointConfig(opts.Method, opts.Path)
+	cfg.Package = opts.Package
+	cfg.Name = opts.Name
+	cfg.Query = opts.Query
+	cfg.Body = opts.Body
+	cfg.ProjectPath = opts.ProjectDir
+	cfg.Process()
+
+	if err := cfg.ApplyManifest(); err != nil {
+		return nil, &Error{Op: "add endpoint", Err: err}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, &Error{Op: "add endpoint", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n🔍 Detected project at: %s", cfg.ProjectPath))
+	rep.Info(fmt.Sprintf("🔗 Adding endpoint: %s %s (%s.%sHandler)\n", cfg.Method, cfg.Path, cfg.Package, cfg.Name))
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, &Error{Op: "add endpoint", Err: err}
+	}
+
+	gen := generator.NewEndpointGenerator(cfg, env)
+	if err := gen.Generate(ctx); err != nil {
+		return nil, &Error{Op: "add endpoint", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n✅ Endpoint '%s %s' added successfully!\n", cfg.Method, cfg.Path))
+	rep.NextStep(fmt.Sprintf("Implement %sHandler in %s/%s", cfg.Name, cfg.Layout.Handlers, cfg.Package))
+	rep.NextStep(fmt.Sprintf("go test ./%s/%s/...", cfg.Layout.Handlers, cfg.Package))
+
+	return newResult(cfg.ProjectPath, rep), nil
+}
@@ -0,0 +1,89 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+)
+
+// EntityOptions configures AddEntity
+type EntityOptions struct {
+	Common
+
+	// Name is the entity name in PascalCase, e.g. "Product" (required)
+	Name string
+	// BelongsTo lists parent entities, e.g. "Order": each adds an order_id
+	// foreign key, a List<Entity>sByOrderID query and a nested
+	// GET /v1/orders/:id/<entities> route
+	BelongsTo []string
+	// ManyToMany lists entities linked through a join table, e.g. "Tag": each
+	// adds Add/Remove/List queries and routes under /v1/<entities>/:id/tags
+	ManyToMany []string
+	// SoftDelete adds a deleted_at column: Delete sets it and Get/List skip deleted rows
+	SoftDelete bool
+	// Audit adds created_by and updated_by columns, filled from util.Actor
+	Audit bool
+	// Versioned adds a version column; updates with a stale version get a 409
+	Versioned bool
+	// Cache adds GET /v1/<entities>/:id reading through Redis for CACHE_TTL,
+	// invalidated by Update and Delete
+	Cache bool
+}
+
+// AddEntity adds an entity, its migration and its queries to an existing
+// project. Related entities must already be created by a migration.
+func AddEntity(ctx context.Context, opts EntityOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "add entity")
+
+	cfg := config.NewEntityConfig(opts.Name)
+	cfg.ProjectPath = opts.ProjectDir
+	cfg.BelongsTo = opts.BelongsTo
+	cfg.ManyToMany = opts.ManyToMany
+	cfg.SoftDelete = opts.SoftDelete
+	cfg.Audit = opts.Audit
+	cfg.Versioned = opts.Versioned
+	cfg.Cache = opts.Cache
+	cfg.Process()
+
+	if err := cfg.ApplyManifest(); err != nil {
+		return nil, &Error{Op: "add entity", Err: err}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, &Error{Op: "add entity", Err: err}
+	}
+	if err := cfg.ValidateEngine(); err != nil {
+		return nil, &Error{Op: "add entity", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n🔍 Detected project at: %s", cfg.ProjectPath))
+	rep.Info(fmt.Sprintf("🚀 Adding entity: %s\n", cfg.EntityName))
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, &Error{Op: "add entity", Err: err}
+	}
+
+	gen := generator.NewEntityGenerator(cfg, env)
+	if err := gen.Generate(ctx); err != nil {
+		return nil, &Error{Op: "add entity", Err: fmt.Errorf("failed to generate entity: %w", err)}
+	}
+
+	rep.Info(fmt.Sprintf("\n✅ Entity '%s' added successfully!\n", cfg.EntityName))
+	rep.NextStep("Edit the migration file to customize your table schema")
+	rep.NextStep(fmt.Sprintf("ready-go gen entity --force --name %s %s   # Refresh the struct after editing the migration", cfg.EntityName, cfg.TableName))
+	rep.NextStep(fmt.Sprintf("Add SQLC queries to %s/", cfg.Layout.Queries))
+	rep.NextStep("make sqlc-generate")
+	rep.NextStep("make migrate-up")
+	rep.NextStep(fmt.Sprintf("Review the handlers and the validate tags of the DTOs in %s/%s", cfg.Layout.Handlers, cfg.EntityNameLower))
+	if cfg.Cache {
+		rep.NextStep("Tune CACHE_TTL in .env; Update and Delete invalidate the cached entity, other writes to its table don't")
+	}
+	if cfg.Audit {
+		rep.NextStep("Call util.SetActor(c, userID) in your authentication middleware to fill created_by and updated_by")
+	}
+
+	return newResult(cfg.ProjectPath, rep), nil
+}
@@ -0,0 +1,77 @@
+package readygo_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+)
+
+func TestAddEntityEngine(t *testing.T) {
+	relations := []struct {
+		name string
+		opts readygo.EntityOptions
+		want string
+	}{
+		{"plain", readygo.EntityOptions{}, "INSERT INTO posts"},
+		{"belongs-to", readygo.EntityOptions{BelongsTo: []string{"Tag"}}, "WHERE tag_id = ?"},
+		{"many-to-many", readygo.EntityOptions{ManyToMany: []string{"Tag"}}, "INSERT IGNORE INTO post_tags"},
+	}
+	for _, engine := range []string{"mysql", "postgresql", "sqlite"} {
+		for _, rel := range relations {
+			t.Run(engine+"/"+rel.name, func(t *testing.T) {
+				dir := newProject(t)
+				if err := os.WriteFile(filepath.Join(dir, "ready-go.yaml"), []byte("version: 1\nmodule: example.com/p\nengine: "+engine+"\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				tags := "-- +goose Up\nCREATE TABLE tags (id BIGINT PRIMARY KEY, name VARCHAR(64) NOT NULL);\n"
+				if err := os.WriteFile(filepath.Join(dir, "database", "migrations", "00001_create_tags.sql"), []byte(tags), 0o644); err != nil {
+					t.Fatal(err)
+				}
+
+				opts := rel.opts
+				opts.Name, opts.ProjectDir = "Post", dir
+				_, err := readygo.AddEntity(context.Background(), opts)
+				queries, readErr := os.ReadFile(filepath.Join(dir, "database", "queries", "post.sql"))
+
+				if engine != "mysql" {
+					want := "add entity only supports mysql projects, not " + engine
+					if readygo.ExitCode(err) != 2 || !strings.Contains(err.Error(), want) {
+						t.Fatalf("err = %v, want a validation error containing %q", err, want)
+					}
+					if readErr == nil {
+						t.Error("wrote queries for a refused entity")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if readErr != nil {
+					t.Fatal(readErr)
+				}
+				if !strings.Contains(string(queries), rel.want) {
+					t.Errorf("post.sql lacks %q:\n%s", rel.want, queries)
+				}
+			})
+		}
+	}
+}
+
+// gen entity only reads migrations, so it stays available on every engine
+func TestGenEntityPostgreSQL(t *testing.T) {
+	dir := newProject(t)
+	if err := os.WriteFile(filepath.Join(dir, "ready-go.yaml"), []byte("version: 1\nmodule: example.com/p\nengine: postgresql\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tags := "-- +goose Up\nCREATE TABLE tags (id BIGSERIAL PRIMARY KEY, name TEXT NOT NULL);\n"
+	if err := os.WriteFile(filepath.Join(dir, "database", "migrations", "00001_create_tags.sql"), []byte(tags), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readygo.GenEntity(context.Background(), readygo.GenEntityOptions{Table: "tags", Common: readygo.Common{ProjectDir: dir}}); err != nil {
+		t.Fatal(err)
+	}
+}
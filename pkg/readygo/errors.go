@@ -0,0 +1,47 @@
+package readygo
+
+import (
+	"fmt"
+
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+)
+
+// The concrete error kinds returned by the library. Match them with errors.As:
+//
+//	var conflict *readygo.ConflictError
+//	if errors.As(err, &conflict) {
+//		// conflict.Path already exists
+//	}
+type (
+	ValidationError = errs.ValidationError
+	ConflictError   = errs.ConflictError
+	TemplateError   = errs.TemplateError
+	ToolchainError  = errs.ToolchainError
+)
+
+// ExitCode returns the CLI exit code for err: 0 for nil, 2 validation,
+// 3 conflict, 4 template, 5 toolchain and 1 for anything else
+func ExitCode(err error) int {
+	return errs.ExitCode(err)
+}
+
+// Hint returns the recovery hint attached to err, if any
+func Hint(err error) string {
+	return errs.Hint(err)
+}
+
+// Error reports which operation failed; the underlying cause is available
+// through errors.Unwrap, errors.Is and errors.As
+type Error struct {
+	// Op is the library call that failed, e.g. "new project"
+	Op  string
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
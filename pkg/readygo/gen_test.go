@@ -0,0 +1,294 @@
+package readygo_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+)
+
+// newSQLite creates a SQLite database for GenFromDB to read
+func newSQLite(t *testing.T, ddl string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(ddl); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGenFromDBSQLite(t *testing.T) {
+	dsn := "sqlite://" + newSQLite(t, `
+		CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			customer_id INTEGER NOT NULL REFERENCES customers (id),
+			total REAL
+		);`)
+
+	dir := newProject(t)
+	if err := os.WriteFile(filepath.Join(dir, "ready-go.yaml"), []byte("version: 1\nmodule: example.com/p\nengine: sqlite\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readygo.GenFromDB(context.Background(), readygo.GenFromDBOptions{DSN: dsn, Common: readygo.Common{ProjectDir: dir}}); err != nil {
+		t.Fatal(err)
+	}
+
+	queries, err := os.ReadFile(filepath.Join(dir, "database", "queries", "customer.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		// id aliases the rowid, so SQLite assigns it
+		"INSERT INTO customers (name)\nVALUES (?);",
+		"SELECT * FROM customers ORDER BY id\nLIMIT ? OFFSET ?;",
+	} {
+		if !strings.Contains(string(queries), want) {
+			t.Errorf("customer.sql lacks %q:\n%s", want, queries)
+		}
+	}
+
+	queries, err = os.ReadFile(filepath.Join(dir, "database", "queries", "order.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"INSERT INTO orders (customer_id, total)\nVALUES (?, ?);",
+		"SELECT * FROM orders WHERE customer_id = ? ORDER BY id\nLIMIT ? OFFSET ?;",
+	} {
+		if !strings.Contains(string(queries), want) {
+			t.Errorf("order.sql lacks %q:\n%s", want, queries)
+		}
+	}
+}
+
+func TestGenFromDBEngineMismatch(t *testing.T) {
+	dsn := "sqlite://" + newSQLite(t, `CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT NOT NULL);`)
+
+	// Without a manifest the project is MySQL
+	dir := newProject(t)
+	_, err := readygo.GenFromDB(context.Background(), readygo.GenFromDBOptions{DSN: dsn, Common: readygo.Common{ProjectDir: dir}})
+	if readygo.ExitCode(err) != 2 || !strings.Contains(err.Error(), "the database is sqlite but the project uses mysql") {
+		t.Fatalf("err = %v, want a validation error", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "database", "migrations"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("wrote %d migrations for a rejected import", len(entries))
+	}
+}
+
+func TestGenFromOpenAPIQuery(t *testing.T) {
+	const list = `openapi: 3.0.3
+info: {title: Orders, version: "1"}
+paths:
+  /v1/orders:
+    get:
+      operationId: listOrders
+      tags: [orders]
+      parameters:%s
+      responses:
+        "200": {description: ok}
+`
+	const params = `
+        - {name: status, in: query, required: true, schema: {type: string, enum: [open, closed]}}
+        - {name: limit, in: query, required: true, schema: {type: integer, minimum: 1}}
+        - {name: cursor, in: query, schema: {type: string}}`
+
+	tests := []struct {
+		name string
+		// before is the parameters of an earlier run, written when set
+		before   string
+		types    []string
+		handler  []string
+		warnings []string
+	}{
+		{
+			name: "required parameters are validated",
+			types: []string{
+				"Status string  `query:\"status\" validate:\"required,oneof=open closed\"`",
+				"Limit  *int    `query:\"limit\" validate:\"required,gte=1\"`",
+				"Cursor *string `query:\"cursor\"`",
+			},
+			handler: []string{"util.Validate(&query)"},
+		},
+		{
+			name:     "parameters added on a re-run are reported",
+			before:   " []",
+			types:    []string{"type ListOrdersQuery struct"},
+			handler:  []string{"// kept"},
+			warnings: []string{"orders.ListOrdersHandler does not bind ListOrdersQuery (query parameters) of GET /v1/orders"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newProject(t)
+			if err := os.MkdirAll(filepath.Join(dir, "internal", "handlers"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			spec := filepath.Join(dir, "api.yaml")
+			opts := readygo.GenFromOpenAPIOptions{Spec: spec, Common: readygo.Common{ProjectDir: dir}}
+			handlerPath := filepath.Join(dir, "internal", "handlers", "orders", "list_orders.go")
+
+			if tt.before != "" {
+				if err := os.WriteFile(spec, []byte(fmt.Sprintf(list, tt.before)), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := readygo.GenFromOpenAPI(context.Background(), opts); err != nil {
+					t.Fatal(err)
+				}
+				// The user implements the handler
+				src, err := os.ReadFile(handlerPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				src = []byte(strings.Replace(string(src), "// TODO: Implement your logic here", "// kept", 1))
+				if err := os.WriteFile(handlerPath, src, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := os.WriteFile(spec, []byte(fmt.Sprintf(list, params)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			res, err := readygo.GenFromOpenAPI(context.Background(), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for path, wants := range map[string][]string{
+				filepath.Join(dir, "internal", "handlers", "orders", "types.go"): tt.types,
+				handlerPath: tt.handler,
+			} {
+				src, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, want := range wants {
+					if !strings.Contains(string(src), want) {
+						t.Errorf("%s lacks %q:\n%s", filepath.Base(path), want, src)
+					}
+				}
+			}
+			for _, want := range tt.warnings {
+				if !slices.ContainsFunc(res.Warnings, func(w string) bool { return strings.Contains(w, want) }) {
+					t.Errorf("warnings %q lack %q", res.Warnings, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenFromOpenAPIRerun(t *testing.T) {
+	const before = `openapi: 3.0.3
+info: {title: Orders, version: "1"}
+paths:
+  /v1/orders/{id}:
+    get:
+      operationId: getOrder
+      tags: [orders]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer, format: int64}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Order'}
+components:
+  schemas:
+    Order:
+      type: object
+      required: [id]
+      properties:
+        id: {type: integer, format: int64}
+`
+	// The second version adds a property and an operation
+	after := strings.Replace(before, "        id: {type: integer, format: int64}\n", "        id: {type: integer, format: int64}\n        note: {type: string}\n", 1)
+	after = strings.Replace(after, "components:", `  /v1/orders:
+    post:
+      operationId: createOrder
+      tags: [orders]
+      responses:
+        "204": {description: created}
+components:`, 1)
+
+	dir := newProject(t)
+	handlers := filepath.Join(dir, "internal", "handlers")
+	if err := os.MkdirAll(handlers, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	setup := "package handlers\n\nimport (\n\t\"github.com/gofiber/fiber/v3\"\n\n\t\"example.com/p/cmd\"\n)\n\nfunc SetupHandler(router *fiber.App, svc *cmd.APIService) {\n}\n"
+	if err := os.WriteFile(filepath.Join(handlers, "handler.go"), []byte(setup), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spec := filepath.Join(dir, "api.yaml")
+	opts := readygo.GenFromOpenAPIOptions{Spec: spec, Common: readygo.Common{ProjectDir: dir}}
+	get := filepath.Join(handlers, "orders", "get_order.go")
+
+	for i, version := range []string{before, after} {
+		if err := os.WriteFile(spec, []byte(version), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readygo.GenFromOpenAPI(context.Background(), opts); err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+		if i == 0 {
+			// The user implements the handler
+			src, err := os.ReadFile(get)
+			if err != nil {
+				t.Fatal(err)
+			}
+			src = []byte(strings.Replace(string(src), "// TODO: Implement your logic here", "resp.ID = params.ID", 1))
+			if err := os.WriteFile(get, src, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"orders/get_order.go", []string{"resp.ID = params.ID"}},
+		{"orders/create_order.go", []string{"type CreateOrderHandler struct", "// TODO: Implement your logic here"}},
+		{"orders/types.go", []string{"Note *string `json:\"note,omitempty\"`"}},
+		{"handler.go", []string{
+			"\tsetupOrdersHandlers(router, svc)\n}",
+			`router.Get("/v1/orders/:id", getOrderHandler.Handle)`,
+			`router.Post("/v1/orders", createOrderHandler.Handle)`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			src, err := os.ReadFile(filepath.Join(handlers, filepath.FromSlash(tt.file)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(src), want) {
+					t.Errorf("%s lacks %q:\n%s", tt.file, want, src)
+				}
+			}
+			// Re-runs rewrite setupOrdersHandlers rather than adding another
+			if tt.file == "handler.go" && strings.Count(string(src), "func setupOrdersHandlers") != 1 {
+				t.Errorf("handler.go declares setupOrdersHandlers more than once:\n%s", src)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+)
+
+// GRPCServiceOptions configures AddGRPCService
+type GRPCServiceOptions struct {
+	Common
+
+	// Service is the service name in PascalCase, e.g. "Orders" (required). A
+	// Service suffix is dropped: OrdersService → Orders.
+	Service string
+	// GRPCPort is the default GRPC_PORT when the project has no gRPC server yet (default: 9090)
+	GRPCPort string
+}
+
+// AddGRPCService adds a gRPC service: a proto definition, an implementation
+// stub embedding the generated Unimplemented server, and its registration.
+// Projects without a gRPC server first get the server, its command, the buf
+// config and the proto make targets.
+func AddGRPCService(ctx context.Context, opts GRPCServiceOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "add grpc-service")
+
+	cfg := config.NewGRPCServiceConfig(opts.Service)
+	if opts.GRPCPort != "" {
+		cfg.GRPCPort = opts.GRPCPort
+	}
+	cfg.ProjectPath = opts.ProjectDir
+	cfg.Process()
+
+	if err := cfg.ApplyManifest(); err != nil {
+		return nil, &Error{Op: "add grpc-service", Err: err}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, &Error{Op: "add grpc-service", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n🔍 Detected project at: %s", cfg.ProjectPath))
+	rep.Info(fmt.Sprintf("🛰️  Adding gRPC service: %s.v1.%sService\n", cfg.Package, cfg.ServiceName))
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, &Error{Op: "add grpc-service", Err: err}
+	}
+
+	gen := generator.NewGRPCServiceGenerator(cfg, env)
+	if err := gen.Generate(ctx); err != nil {
+		return nil, &Error{Op: "add grpc-service", Err: err}
+	}
+
+	grpc := cfg.GRPC
+	if grpc == nil {
+		grpc = config.DefaultGRPC()
+	}
+	rep.Info(fmt.Sprintf("\n✅ gRPC service '%sService' added successfully!\n", cfg.ServiceName))
+	rep.NextStep(fmt.Sprintf("Add RPCs and messages to %s/%s/v1/%s.proto", grpc.Proto, cfg.Package, cfg.Package))
+	rep.NextStep("make proto-tools    # Once: installs buf, protoc-gen-go and protoc-gen-go-grpc")
+	rep.NextStep(fmt.Sprintf("make proto          # Generates %s/%s/v1", grpc.Gen, cfg.Package))
+	rep.NextStep(fmt.Sprintf("Implement the RPCs in %s/%s/service.go", grpc.Server, cfg.Package))
+
+	return newResult(cfg.ProjectPath, rep), nil
+}
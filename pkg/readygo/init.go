@@ -0,0 +1,144 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/detect"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+)
+
+// Inspection is what Inspect found in an existing project
+type Inspection = detect.Project
+
+// Piece is an optional part of a ready-go project that Init can add
+type Piece = detect.Piece
+
+// Pieces Init can add to an existing project
+const (
+	PieceMakefile = detect.PieceMakefile
+	PieceSQLC     = detect.PieceSQLC
+	PieceUtil     = detect.PieceUtil
+)
+
+// InitOptions configures Init, which adopts the Go service at ProjectDir
+type InitOptions struct {
+	Common
+
+	// Module overrides the module path read from go.mod
+	Module string
+	// Engine overrides the detected database engine (mysql, postgresql or sqlite)
+	Engine string
+	// Router overrides the detected router framework (fiber, echo, chi or stdlib)
+	Router string
+	// Add lists the missing pieces to add; pieces already present are ignored
+	Add []Piece
+}
+
+// Inspect examines an existing Go service without changing anything
+func Inspect(ctx context.Context, dir string) (*Inspection, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	abs, err := filepath.Abs(dirOrCurrent(dir))
+	if err != nil {
+		return nil, &Error{Op: "inspect", Err: err}
+	}
+
+	project, err := detect.Inspect(abs)
+	if err != nil {
+		return nil, &Error{Op: "inspect", Err: err}
+	}
+	return project, nil
+}
+
+// Init adopts an existing Go service into ready-go conventions by writing the
+// project manifest and adding the requested missing pieces
+func Init(ctx context.Context, opts InitOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "init")
+
+	project, err := Inspect(ctx, opts.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Module != "" {
+		project.Module = opts.Module
+	}
+	if opts.Engine != "" {
+		if !slices.Contains([]string{"mysql", "postgresql", "sqlite"}, opts.Engine) {
+			return nil, &Error{Op: "init", Err: &errs.ValidationError{
+				Field:   "engine",
+				Message: fmt.Sprintf("unknown engine %q", opts.Engine),
+				Hint:    "use mysql, postgresql or sqlite",
+			}}
+		}
+		project.Engine = opts.Engine
+		project.EngineSource = "flag"
+	}
+	if opts.Router != "" {
+		if !slices.Contains(config.Frameworks, opts.Router) {
+			return nil, &Error{Op: "init", Err: &errs.ValidationError{
+				Field:   "router",
+				Message: fmt.Sprintf("unknown router %q", opts.Router),
+				Hint:    "use " + strings.Join(config.Frameworks, ", "),
+			}}
+		}
+		project.Router = opts.Router
+	}
+
+	missing := project.Missing()
+	var pieces []Piece
+	for _, piece := range opts.Add {
+		if !slices.Contains(detect.Pieces, piece) {
+			return nil, &Error{Op: "init", Err: &errs.ValidationError{
+				Field:   "add",
+				Message: fmt.Sprintf("unknown piece %q", piece),
+				Hint:    "use makefile, sqlc or util",
+			}}
+		}
+		if slices.Contains(missing, piece) && !slices.Contains(pieces, piece) {
+			pieces = append(pieces, piece)
+		}
+	}
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, &Error{Op: "init", Err: err}
+	}
+
+	gen := generator.NewInitGenerator(project, pieces, env)
+	if err := gen.Generate(ctx); err != nil {
+		return nil, &Error{Op: "init", Err: fmt.Errorf("failed to initialize project: %w", err)}
+	}
+
+	if project.Router == "" {
+		rep.Warn("no router found; assuming " + gen.Manifest().Router.Framework + ", pass --router to change it")
+	}
+	if project.RouterSetup == "" {
+		rep.Warn("no router setup found; route registration will use " + gen.Manifest().Router.Setup)
+	}
+
+	rep.Info(fmt.Sprintf("\n✅ Project at %s now uses ready-go conventions\n", project.Dir))
+	for _, piece := range missing {
+		if !slices.Contains(pieces, piece) {
+			rep.NextStep(fmt.Sprintf("ready-go init --with %s   # Add the missing %s", piece, piece))
+		}
+	}
+	rep.NextStep("ready-go add entity <EntityName>")
+
+	return newResult(project.Dir, rep), nil
+}
+
+func dirOrCurrent(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
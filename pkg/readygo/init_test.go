@@ -0,0 +1,59 @@
+package readygo_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+)
+
+func TestInitRouter(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+		want     string
+		wantErr  string
+	}{
+		{name: "detected", want: "framework: chi"},
+		{name: "overridden", override: "stdlib", want: "framework: stdlib"},
+		{name: "unknown", override: "gin", wantErr: `unknown router "gin"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := map[string]string{
+				"go.mod": "module example.com/svc\n\ngo 1.23\n",
+				"main.go": `package main
+import ("net/http"; "github.com/go-chi/chi/v5")
+func main() { r := chi.NewRouter(); _ = http.ListenAndServe(":8080", r) }
+`,
+			}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := readygo.Init(context.Background(), readygo.InitOptions{Router: tt.override, Common: readygo.Common{ProjectDir: dir}})
+			if tt.wantErr != "" {
+				if readygo.ExitCode(err) != 2 || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want a validation error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			manifest, err := os.ReadFile(filepath.Join(dir, "ready-go.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(manifest), tt.want) {
+				t.Errorf("ready-go.yaml lacks %q:\n%s", tt.want, manifest)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+)
+
+// MiddlewareOptions configures AddMiddleware
+type MiddlewareOptions struct {
+	Common
+
+	// Names are middleware from the catalog (required): request-id, recovery,
+	// security-headers, cors, rate-limit, gzip, body-limit or timeout
+	Names []string
+}
+
+// AddMiddleware adds middleware from the catalog to a project's middleware
+// package, adds their settings to config.Config and .env.example, and
+// installs them in SetupHandler in the order of the catalog, whatever order
+// they are given in. Middleware the project has already are kept as they are.
+func AddMiddleware(ctx context.Context, opts MiddlewareOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "add middleware")
+
+	cfg := config.NewMiddlewareConfig(opts.Names)
+	cfg.ProjectPath = opts.ProjectDir
+	cfg.Process()
+
+	if err := cfg.ApplyManifest(); err != nil {
+		return nil, &Error{Op: "add middleware", Err: err}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, &Error{Op: "add middleware", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n🔍 Detected project at: %s", cfg.ProjectPath))
+	rep.Info(fmt.Sprintf("🧱 Adding middleware: %s\n", strings.Join(cfg.Names, ", ")))
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, &Error{Op: "add middleware", Err: err}
+	}
+
+	gen := generator.NewMiddlewareGenerator(cfg, env)
+	if err := gen.Generate(ctx); err != nil {
+		return nil, &Error{Op: "add middleware", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n✅ Middleware '%s' added successfully!\n", strings.Join(cfg.Names, ", ")))
+	rep.NextStep("Review the new settings in internal/config/config.go and .env.example")
+
+	return newResult(cfg.ProjectPath, rep), nil
+}
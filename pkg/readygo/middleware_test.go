@@ -0,0 +1,134 @@
+package readygo_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+)
+
+// installed matches the middleware a setup function installs
+var installed = regexp.MustCompile(`\b(RequestID|LoggingMiddleware|Recovery|SecurityHeaders|CORS|Authenticate|RateLimit|Gzip|BodyLimit|Timeout)\b`)
+
+func TestAddMiddlewareOrder(t *testing.T) {
+	const fiberSetup = `package handlers
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"example.com/p/cmd"
+)
+
+func SetupHandler(router *fiber.App, svc *cmd.APIService) {
+%s
+}
+
+func LoggingMiddleware() fiber.Handler { return nil }
+`
+	const stdlibSetup = `package handlers
+
+import (
+	"net/http"
+
+	"example.com/p/cmd"
+	"example.com/p/internal/config"
+)
+
+func SetupHandler(mux *http.ServeMux, svc *cmd.APIService) {}
+
+func Middleware(next http.Handler, cfg *config.Config) http.Handler {
+	chain := []func(http.Handler) http.Handler{
+%s
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		next = chain[i](next)
+	}
+	return next
+}
+
+func LoggingMiddleware(next http.Handler) http.Handler { return next }
+`
+
+	tests := []struct {
+		name      string
+		framework string
+		// uses is the middleware installed already, and runs the names
+		// passed to each add middleware
+		uses string
+		runs [][]string
+		want []string
+	}{
+		{
+			name:      "catalog order whatever the order given",
+			framework: "fiber",
+			uses:      "\trouter.Use(LoggingMiddleware())",
+			runs:      [][]string{{"timeout", "request-id", "cors"}},
+			want:      []string{"RequestID", "LoggingMiddleware", "CORS", "Timeout"},
+		},
+		{
+			name:      "inserted among installed middleware",
+			framework: "fiber",
+			uses:      "\trouter.Use(LoggingMiddleware())\n\trouter.Use(middleware.Gzip(svc.Config))",
+			runs:      [][]string{{"body-limit"}, {"security-headers"}},
+			want:      []string{"LoggingMiddleware", "SecurityHeaders", "Gzip", "BodyLimit"},
+		},
+		{
+			name:      "installed middleware are not added again",
+			framework: "fiber",
+			uses:      "\trouter.Use(LoggingMiddleware())\n\trouter.Use(middleware.CORS(svc.Config))",
+			runs:      [][]string{{"cors", "recovery"}, {"recovery", "cors"}},
+			want:      []string{"LoggingMiddleware", "Recovery", "CORS"},
+		},
+		{
+			name:      "net/http chain",
+			framework: "stdlib",
+			uses:      "\t\tLoggingMiddleware,",
+			runs:      [][]string{{"timeout", "request-id"}, {"request-id", "recovery"}},
+			want:      []string{"RequestID", "LoggingMiddleware", "Recovery", "Timeout"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newProject(t)
+			setup, fn := fiberSetup, "func SetupHandler"
+			if tt.framework == "stdlib" {
+				setup, fn = stdlibSetup, "func Middleware"
+			}
+			files := map[string]string{
+				"ready-go.yaml":                "version: 1\nmodule: example.com/p\nrouter:\n  framework: " + tt.framework + "\n",
+				"internal/handlers/handler.go": strings.Replace(setup, "%s", tt.uses, 1),
+			}
+			for name, content := range files {
+				path := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, names := range tt.runs {
+				if _, err := readygo.AddMiddleware(context.Background(), readygo.MiddlewareOptions{Names: names, Common: readygo.Common{ProjectDir: dir}}); err != nil {
+					t.Fatalf("add middleware %s: %v", strings.Join(names, " "), err)
+				}
+			}
+
+			src, err := os.ReadFile(filepath.Join(dir, "internal", "handlers", "handler.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := string(src)[strings.Index(string(src), fn):]
+			body = body[:strings.Index(body, "\n}\n")]
+			got := installed.FindAllString(body, -1)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("installed %q, want %q:\n%s", got, tt.want, src)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+)
+
+// MigrationOptions configures AddMigration
+type MigrationOptions struct {
+	Common
+
+	// Name is the migration name in snake_case, e.g. "add_sku_to_products" (required)
+	Name string
+
+	// AddColumns adds columns: "table.column:type[:unique][:index][:notnull][:default=value]"
+	AddColumns []string
+	// AddIndexes adds indexes: "table(column[,column...])[:unique]"
+	AddIndexes []string
+	// DropColumns drops columns: "table.column"
+	DropColumns []string
+}
+
+// AddMigration writes a goose migration for the given changes in the
+// project's SQL dialect. With no changes it writes an empty migration.
+func AddMigration(ctx context.Context, opts MigrationOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "add migration")
+
+	cfg := config.NewMigrationConfig(opts.Name)
+	cfg.ProjectPath = opts.ProjectDir
+	cfg.AddColumns = opts.AddColumns
+	cfg.AddIndexes = opts.AddIndexes
+	cfg.DropColumns = opts.DropColumns
+	cfg.Process()
+
+	if err := cfg.ApplyManifest(); err != nil {
+		return nil, &Error{Op: "add migration", Err: err}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, &Error{Op: "add migration", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n🔍 Detected project at: %s", cfg.ProjectPath))
+	rep.Info(fmt.Sprintf("🚀 Adding %s migration: %s\n", cfg.Engine, cfg.Name))
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, &Error{Op: "add migration", Err: err}
+	}
+
+	gen := generator.NewMigrationGenerator(cfg, env)
+	if err := gen.Generate(ctx); err != nil {
+		return nil, &Error{Op: "add migration", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n✅ Migration '%s' added successfully!\n", cfg.Name))
+	if len(cfg.AddColumns)+len(cfg.AddIndexes)+len(cfg.DropColumns) == 0 {
+		rep.NextStep("Write the Up and Down sections of the migration")
+	} else {
+		rep.NextStep("Review the generated Up and Down sections")
+	}
+	rep.NextStep("make migrate-up")
+	rep.NextStep("make sqlc-generate")
+
+	return newResult(cfg.ProjectPath, rep), nil
+}
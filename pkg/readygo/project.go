@@ -0,0 +1,141 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+)
+
+// ProjectOptions configures NewProject. Empty fields fall back to the CLI defaults.
+type ProjectOptions struct {
+	Common
+
+	// Name is the project directory name (required)
+	Name string
+	// Module is the Go module path (default: github.com/username/<Name>)
+	Module string
+	// OutputDir is the parent directory of the project (default: ".")
+	OutputDir  string
+	ServerPort string
+	DBPort     string
+	RedisPort  string
+	KafkaPort  string
+	// GRPCPort is the gRPC server port (default: 9090), used with TransportGRPC
+	GRPCPort string
+	// Router is the HTTP router framework (default: RouterFiber)
+	Router string
+	// Transports lists the servers the project runs (default: TransportHTTP).
+	// TransportGRPC adds a gRPC server that shuts down together with HTTP.
+	Transports []string
+	// SampleName is the sample entity name (default: User)
+	SampleName string
+	// Layout overrides where generated directories live; empty fields keep the defaults
+	Layout Layout
+	// Versioning numbers migrations (default: VersioningSequential)
+	Versioning Versioning
+}
+
+// NewProject scaffolds a new project
+func NewProject(ctx context.Context, opts ProjectOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "new")
+
+	cfg := opts.config()
+	cfg.Process()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, &Error{Op: "new project", Err: fmt.Errorf("invalid configuration: %w", err)}
+	}
+
+	rep.Info(fmt.Sprintf("\n🚀 Creating project: %s", cfg.ProjectName))
+	rep.Info(fmt.Sprintf("📦 Module: %s", cfg.ModuleName))
+	rep.Info(fmt.Sprintf("🧭 Router: %s", cfg.Router.Framework))
+	rep.Info(fmt.Sprintf("🎯 Sample API: %s\n", cfg.SampleAPIName))
+	if cfg.GRPC != nil {
+		rep.Info(fmt.Sprintf("🛰️  gRPC: port %s\n", cfg.GRPCPort))
+	}
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, &Error{Op: "new project", Err: err}
+	}
+
+	gen := generator.NewProjectGenerator(cfg, env)
+	if err := gen.Generate(ctx); err != nil {
+		return nil, &Error{Op: "new project", Err: fmt.Errorf("failed to generate project: %w", err)}
+	}
+
+	rep.Info(fmt.Sprintf("\n✅ Project successfully created at ./%s\n", cfg.ProjectName))
+	rep.NextStep(fmt.Sprintf("cd %s", cfg.ProjectName))
+	rep.NextStep("make docker-up      # Start all services")
+	rep.NextStep("make migrate-up     # Run migrations")
+	rep.NextStep("make sqlc-generate  # Generate SQLC models")
+	rep.NextStep("make run-api        # Start the application")
+	rep.NextStep(fmt.Sprintf("open http://localhost:%s  # Access your application", cfg.ServerPort))
+	if cfg.GRPC != nil {
+		rep.NextStep("ready-go add grpc-service Orders  # Then make proto")
+	}
+
+	return newResult(filepath.Join(cfg.OutputDir, cfg.ProjectName), rep), nil
+}
+
+// config converts the options into a ProjectConfig, keeping defaults for empty fields
+func (o ProjectOptions) config() *config.ProjectConfig {
+	cfg := config.NewProjectConfig(o.Name)
+
+	if o.Module != "" {
+		cfg.ModuleName = o.Module
+	}
+	if o.OutputDir != "" {
+		cfg.OutputDir = o.OutputDir
+	}
+	if o.ServerPort != "" {
+		cfg.ServerPort = o.ServerPort
+	}
+	if o.DBPort != "" {
+		cfg.DBPort = o.DBPort
+	}
+	if o.RedisPort != "" {
+		cfg.RedisPort = o.RedisPort
+	}
+	if o.KafkaPort != "" {
+		cfg.KafkaPort = o.KafkaPort
+	}
+	if o.GRPCPort != "" {
+		cfg.GRPCPort = o.GRPCPort
+	}
+	if o.Router != "" {
+		cfg.Router.Framework = o.Router
+	}
+	if len(o.Transports) > 0 {
+		cfg.Transports = slices.Clone(o.Transports)
+	}
+	if o.SampleName != "" {
+		cfg.SampleAPIName = o.SampleName
+	}
+	if o.Versioning != "" {
+		cfg.Versioning = o.Versioning
+	}
+
+	overrides := []struct {
+		dst *string
+		src string
+	}{
+		{&cfg.Layout.Migrations, o.Layout.Migrations},
+		{&cfg.Layout.Queries, o.Layout.Queries},
+		{&cfg.Layout.Entity, o.Layout.Entity},
+		{&cfg.Layout.Handlers, o.Layout.Handlers},
+		{&cfg.Layout.Models, o.Layout.Models},
+		{&cfg.Layout.API, o.Layout.API},
+	}
+	for _, override := range overrides {
+		if override.src != "" {
+			*override.dst = override.src
+		}
+	}
+
+	return cfg
+}
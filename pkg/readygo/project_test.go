@@ -0,0 +1,49 @@
+package readygo_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+)
+
+func TestNewProjectTwice(t *testing.T) {
+	// go mod tidy fails fast offline; NewProject only warns about it
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	dir := t.TempDir()
+	opts := readygo.ProjectOptions{
+		Name:      "orders",
+		Module:    "example.com/orders",
+		OutputDir: dir,
+		Common:    readygo.Common{Conflict: readygo.ConflictOverwrite},
+	}
+	if _, err := readygo.NewProject(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readygo.NewProject(context.Background(), opts); err != nil {
+		t.Fatalf("second run with ConflictOverwrite: %v", err)
+	}
+
+	opts.Conflict = readygo.ConflictSkip
+	if _, err := readygo.NewProject(context.Background(), opts); err != nil {
+		t.Fatalf("run with ConflictSkip: %v", err)
+	}
+
+	gomod, err := os.ReadFile(filepath.Join(dir, "orders", "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(gomod), "module example.com/orders\n") {
+		t.Errorf("go.mod = %q", gomod)
+	}
+
+	opts.Conflict = readygo.ConflictFail
+	if _, err := readygo.NewProject(context.Background(), opts); readygo.ExitCode(err) != 3 {
+		t.Errorf("run with ConflictFail: err = %v, want a conflict", err)
+	}
+}
@@ -0,0 +1,186 @@
+// Package readygo exposes the ready-go generators as a Go library, so other
+// tools can scaffold projects and entities without shelling out to the CLI.
+//
+//	res, err := readygo.NewProject(ctx, readygo.ProjectOptions{
+//		Name:   "orders",
+//		Module: "github.com/acme/orders",
+//	})
+package readygo
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+	"github.com/muazwzxv/ready-go-cli/internal/report"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+	"github.com/muazwzxv/ready-go-cli/templates"
+)
+
+// Reporter receives progress events while a generator runs. NewReporter
+// returns the CLI's reporters; callers may also implement their own.
+type Reporter = report.Reporter
+
+// Summary is everything a Reporter recorded, as returned by Reporter.Summary
+type Summary = report.Summary
+
+// Format selects how a Reporter from NewReporter presents progress
+type Format = report.Format
+
+// Formats accepted by NewReporter
+const (
+	FormatText  = report.FormatText
+	FormatJSON  = report.FormatJSON
+	FormatQuiet = report.FormatQuiet
+)
+
+// RenamedFile describes a file that was moved
+type RenamedFile = report.RenamedFile
+
+// CommandResult describes an external command run during generation
+type CommandResult = report.CommandResult
+
+// SkippedFile describes a file that was not written
+type SkippedFile = report.SkippedFile
+
+// Layout holds project-relative, slash-separated directory paths, as stored in
+// the ready-go.yaml manifest
+type Layout = config.Layout
+
+// ConflictPolicy decides what happens when a generated file already exists
+type ConflictPolicy = generator.ConflictPolicy
+
+// Conflict policies accepted by Common.Conflict
+const (
+	ConflictFail      = generator.ConflictFail
+	ConflictSkip      = generator.ConflictSkip
+	ConflictOverwrite = generator.ConflictOverwrite
+	ConflictPrompt    = generator.ConflictPrompt
+)
+
+// ConflictResolver answers, file by file, whether to overwrite under ConflictPrompt
+type ConflictResolver = generator.ConflictResolver
+
+// Resolution is a ConflictResolver's answer for one file
+type Resolution = generator.Resolution
+
+// Resolutions a ConflictResolver may return
+const (
+	ResolveSkip         = generator.ResolveSkip
+	ResolveOverwrite    = generator.ResolveOverwrite
+	ResolveOverwriteAll = generator.ResolveOverwriteAll
+	ResolveAbort        = generator.ResolveAbort
+)
+
+// Routers accepted by ProjectOptions.Router; RouterStdlib is the Go 1.22
+// net/http ServeMux
+const (
+	RouterFiber  = config.FrameworkFiber
+	RouterEcho   = config.FrameworkEcho
+	RouterChi    = config.FrameworkChi
+	RouterStdlib = config.FrameworkStdlib
+)
+
+// Transports accepted by ProjectOptions.Transports
+const (
+	TransportHTTP = config.TransportHTTP
+	TransportGRPC = config.TransportGRPC
+)
+
+// Versioning is a goose migration numbering scheme
+type Versioning = schema.Versioning
+
+// Versioning schemes accepted by ProjectOptions.Versioning
+const (
+	VersioningSequential = schema.VersioningSequential
+	VersioningTimestamp  = schema.VersioningTimestamp
+)
+
+// NullStyle selects how GenEntity represents nullable columns
+type NullStyle = generator.NullStyle
+
+// Null styles accepted by GenEntityOptions.Nulls
+const (
+	NullSQL     = generator.NullSQL
+	NullPointer = generator.NullPointer
+)
+
+// Result describes what a generator did
+type Result struct {
+	// Dir is the project root the generator worked in
+	Dir         string
+	Created     []string
+	Overwritten []string
+	Updated     []string
+	Skipped     []SkippedFile
+	Renamed     []RenamedFile
+	Commands    []CommandResult
+	Warnings    []string
+	NextSteps   []string
+}
+
+// DefaultTemplates returns the templates bundled with ready-go
+func DefaultTemplates() fs.FS {
+	return templates.FS
+}
+
+// NewReporter returns a Reporter printing progress to w as text, as a single
+// JSON document written by Finish, or not at all
+func NewReporter(format Format, w io.Writer) (Reporter, error) {
+	return report.New(format, "", w)
+}
+
+// Common holds the options shared by every operation. Each *Options struct
+// embeds it, so they are set as Common: readygo.Common{ProjectDir: dir}.
+type Common struct {
+	// ProjectDir is the root of the project to work in (default: current
+	// directory). NewProject ignores it and creates the project in OutputDir.
+	ProjectDir string
+	// Conflict decides what happens to files that already exist (default: ConflictFail)
+	Conflict ConflictPolicy
+	// Resolver is consulted for each existing file under ConflictPrompt
+	Resolver ConflictResolver
+	// Templates overrides the bundled templates (default: DefaultTemplates())
+	Templates fs.FS
+	// Reporter receives progress events (default: discarded)
+	Reporter Reporter
+}
+
+// newEnv builds the generator environment from the common options
+func newEnv(fsys fs.FS, policy ConflictPolicy, resolver ConflictResolver, r Reporter) (*generator.Env, error) {
+	if fsys == nil {
+		fsys = DefaultTemplates()
+	}
+	return generator.NewEnv(fsys, policy, resolver, r)
+}
+
+// reporterOrDiscard returns r, naming the command in its summary when
+// NewReporter left it unnamed, or a Reporter discarding everything
+func reporterOrDiscard(r Reporter, command string) Reporter {
+	if r == nil {
+		return report.Discard(command)
+	}
+	if s := r.Summary(); s != nil && s.Command == "" {
+		s.Command = command
+	}
+	return r
+}
+
+func newResult(dir string, r Reporter) *Result {
+	summary := r.Summary()
+	if summary == nil {
+		return &Result{Dir: dir}
+	}
+	return &Result{
+		Dir:         dir,
+		Created:     summary.Created,
+		Overwritten: summary.Overwritten,
+		Updated:     summary.Updated,
+		Skipped:     summary.Skipped,
+		Renamed:     summary.Renamed,
+		Commands:    summary.Commands,
+		Warnings:    summary.Warnings,
+		NextSteps:   summary.NextSteps,
+	}
+}
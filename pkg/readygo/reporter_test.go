@@ -0,0 +1,107 @@
+package readygo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muazwzxv/ready-go-cli/pkg/readygo"
+)
+
+// recorder is a Reporter implemented outside the module, as an embedding
+// tool would write it
+type recorder struct {
+	steps   []string
+	created []string
+	summary readygo.Summary
+}
+
+var _ readygo.Reporter = (*recorder)(nil)
+
+func (r *recorder) Step(msg string)               { r.steps = append(r.steps, msg) }
+func (r *recorder) Info(string)                   {}
+func (r *recorder) FileCreated(path string)       { r.created = append(r.created, path) }
+func (r *recorder) FileOverwritten(string)        {}
+func (r *recorder) FileUpdated(string)            {}
+func (r *recorder) FileSkipped(string, string)    {}
+func (r *recorder) FileRenamed(string, string)    {}
+func (r *recorder) Command(readygo.CommandResult) {}
+func (r *recorder) Warn(string)                   {}
+func (r *recorder) NextStep(step string)          { r.summary.NextSteps = append(r.summary.NextSteps, step) }
+func (r *recorder) Finish(err error) error        { return err }
+func (r *recorder) Summary() *readygo.Summary     { return &r.summary }
+
+// newProject makes the smallest project the generators accept: a go.mod and
+// the database directories
+func newProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/p\n\ngo 1.23\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, sub := range []string{"migrations", "queries"} {
+		if err := os.MkdirAll(filepath.Join(dir, "database", sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCustomReporter(t *testing.T) {
+	rep := &recorder{}
+	res, err := readygo.AddMigration(context.Background(), readygo.MigrationOptions{
+		Name: "add_sku_to_products",
+		Common: readygo.Common{
+			ProjectDir: newProject(t),
+			Reporter:   rep,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.created) != 1 || filepath.Base(filepath.Dir(rep.created[0])) != "migrations" {
+		t.Errorf("created = %v, want one migration", rep.created)
+	}
+	if rep.summary.Command != "add migration" {
+		t.Errorf("command = %q, want %q", rep.summary.Command, "add migration")
+	}
+	if len(res.NextSteps) == 0 {
+		t.Error("the result has no next steps from the reporter's summary")
+	}
+}
+
+func TestNewReporter(t *testing.T) {
+	var out bytes.Buffer
+	rep, err := readygo.NewReporter(readygo.FormatJSON, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := readygo.AddMigration(context.Background(), readygo.MigrationOptions{
+		Name: "add_sku_to_products",
+		Common: readygo.Common{
+			ProjectDir: newProject(t),
+			Reporter:   rep,
+		},
+	})
+	if err := rep.Finish(err); err != nil {
+		t.Fatal(err)
+	}
+
+	var summary readygo.Summary
+	if err := json.Unmarshal(out.Bytes(), &summary); err != nil {
+		t.Fatalf("output is not a JSON summary: %v\n%s", err, out.String())
+	}
+	if summary.Command != "add migration" || !summary.Success {
+		t.Errorf("summary = %+v", summary)
+	}
+	if len(summary.Created) != 1 || summary.Created[0] != res.Created[0] {
+		t.Errorf("created = %v, result %v", summary.Created, res.Created)
+	}
+
+	if _, err := readygo.NewReporter("yaml", &out); err == nil {
+		t.Error("NewReporter accepted an unknown format")
+	}
+}
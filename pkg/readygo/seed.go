@@ -0,0 +1,60 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+)
+
+// SeedOptions configures AddSeed
+type SeedOptions struct {
+	Common
+
+	// Entity is the entity name in PascalCase, e.g. "Product" (required)
+	Entity string
+	// Table is the entity's table (default: the plural of Entity in lowercase)
+	Table string
+}
+
+// AddSeed writes a deterministic fake-data factory for an entity, calling its
+// sqlc Create query. The first seed also adds the seeds package, the seed
+// command and the `make seed` target.
+func AddSeed(ctx context.Context, opts SeedOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "add seed")
+
+	cfg := config.NewEntityConfig(opts.Entity)
+	cfg.TableName = opts.Table
+	cfg.ProjectPath = opts.ProjectDir
+	cfg.Process()
+
+	if err := cfg.ApplyManifest(); err != nil {
+		return nil, &Error{Op: "add seed", Err: err}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, &Error{Op: "add seed", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n🔍 Detected project at: %s", cfg.ProjectPath))
+	rep.Info(fmt.Sprintf("🌱 Adding seeder: %s (%s)\n", cfg.EntityName, cfg.TableName))
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, &Error{Op: "add seed", Err: err}
+	}
+
+	gen := generator.NewSeedGenerator(cfg, env)
+	if err := gen.Generate(ctx); err != nil {
+		return nil, &Error{Op: "add seed", Err: err}
+	}
+
+	rep.Info(fmt.Sprintf("\n✅ Seeder '%s' added successfully!\n", cfg.EntityName))
+	rep.NextStep(fmt.Sprintf("Adjust the fake values in %s/%s.go", cfg.Layout.Seeds(), cfg.EntityNameLower))
+	rep.NextStep("make sqlc-generate  # The seeder calls the generated Create query")
+	rep.NextStep("make migrate-up")
+	rep.NextStep("make seed           # N=100 SEED=2 ONLY=product to customise")
+
+	return newResult(cfg.ProjectPath, rep), nil
+}
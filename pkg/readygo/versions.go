@@ -0,0 +1,120 @@
+package readygo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muazwzxv/ready-go-cli/internal/config"
+	"github.com/muazwzxv/ready-go-cli/internal/errs"
+	"github.com/muazwzxv/ready-go-cli/internal/generator"
+	"github.com/muazwzxv/ready-go-cli/internal/schema"
+)
+
+// MigrationProblem is a versioning problem found by CheckMigrations
+type MigrationProblem = schema.Problem
+
+// MigrationsOptions configures CheckMigrations and RenumberMigrations
+type MigrationsOptions struct {
+	Common
+
+	// DryRun makes RenumberMigrations report the renames without making them
+	DryRun bool
+}
+
+// CheckResult describes what CheckMigrations found
+type CheckResult struct {
+	*Result
+	Problems []MigrationProblem
+}
+
+// CheckMigrations looks for duplicate versions, migrations merged out of
+// order, gaps in sequential numbering and migrations that don't follow the
+// project's versioning. It returns a ValidationError when goose would refuse
+// to run or silently skip a migration; gaps and mixed schemes are warnings.
+func CheckMigrations(ctx context.Context, opts MigrationsOptions) (*CheckResult, error) {
+	rep := reporterOrDiscard(opts.Reporter, "migrations check")
+
+	cfg, env, err := migrationsEnv(opts, rep)
+	if err != nil {
+		return nil, &Error{Op: "migrations check", Err: err}
+	}
+
+	problems, err := generator.NewVersionChecker(cfg, env).Check(ctx)
+	if err != nil {
+		return nil, &Error{Op: "migrations check", Err: err}
+	}
+
+	fatal := 0
+	for _, p := range problems {
+		msg := fmt.Sprintf("%s: %s", p.Kind, p.Message)
+		if len(p.Files) > 0 && p.Kind != schema.ProblemOutOfOrder {
+			msg += fmt.Sprintf(" (%v)", p.Files)
+		}
+		rep.Warn(msg)
+		if p.Fatal() {
+			fatal++
+		}
+	}
+
+	res := &CheckResult{Result: newResult(cfg.ProjectPath, rep), Problems: problems}
+	if fatal > 0 {
+		return res, &Error{Op: "migrations check", Err: errs.Validation(
+			"run `ready-go migrations renumber` to move them after the existing migrations",
+			"%d problem(s) would keep migrations from running as expected", fatal,
+		)}
+	}
+
+	if len(problems) == 0 {
+		rep.Info(fmt.Sprintf("✅ Migrations in %s are consistent", cfg.Layout.Migrations))
+	}
+	return res, nil
+}
+
+// RenumberMigrations gives duplicate and out-of-order migrations new versions
+// after every other migration, e.g. after a rebase brought in a migration with
+// the same number. Only renumber migrations that no shared database has applied.
+func RenumberMigrations(ctx context.Context, opts MigrationsOptions) (*Result, error) {
+	rep := reporterOrDiscard(opts.Reporter, "migrations renumber")
+
+	cfg, env, err := migrationsEnv(opts, rep)
+	if err != nil {
+		return nil, &Error{Op: "migrations renumber", Err: err}
+	}
+
+	renames, err := generator.NewVersionChecker(cfg, env).Renumber(ctx, opts.DryRun)
+	if err != nil {
+		return nil, &Error{Op: "migrations renumber", Err: err}
+	}
+
+	switch {
+	case len(renames) == 0:
+		rep.Info("✅ Nothing to renumber")
+	case opts.DryRun:
+		rep.NextStep("ready-go migrations renumber   # Apply the renames")
+	default:
+		rep.NextStep("ready-go migrations check")
+		rep.NextStep("make migrate-up")
+	}
+
+	return newResult(cfg.ProjectPath, rep), nil
+}
+
+// migrationsEnv loads the project configuration shared by the migrations commands
+func migrationsEnv(opts MigrationsOptions, rep Reporter) (*config.MigrationConfig, *generator.Env, error) {
+	cfg := config.NewMigrationConfig("")
+	cfg.ProjectPath = opts.ProjectDir
+	cfg.Process()
+
+	if err := cfg.ApplyManifest(); err != nil {
+		return nil, nil, err
+	}
+	if err := cfg.ValidateProject(); err != nil {
+		return nil, nil, err
+	}
+
+	env, err := newEnv(opts.Templates, opts.Conflict, opts.Resolver, rep)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, env, nil
+}
@@ -0,0 +1,11 @@
+// Package templates embeds the templates ready-go renders into new and existing projects.
+package templates
+
+import "embed"
+
+// FS holds every template, keyed by its path relative to this directory
+// (e.g. "entity/entity.go.tmpl"). New top-level template directories must be
+// added to the embed pattern below.
+//
+//go:embed all:_partials all:auth all:cmd all:database all:endpoint all:entity all:fromdb all:fromopenapi all:grpc all:init all:internal all:middleware all:migration all:openapi all:project all:seed
+var FS embed.FS